@@ -0,0 +1,414 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Events of an ActivityNotification
+const (
+	ActivityStarted = "started"
+	ActivityUpdated = "updated"
+	ActivityEnded   = "ended"
+)
+
+// Activity is a background task of the server, like a library scan, a media analysis
+// or a transcoder optimization. See GetActivities and WatchActivities
+type Activity struct {
+	Cancellable FlexBool `json:"cancellable" xml:"cancellable,attr"`
+	// Progress is a percentage, from 0 to 100
+	Progress FlexInt `json:"progress" xml:"progress,attr"`
+	Subtitle string  `json:"subtitle" xml:"subtitle,attr"`
+	Title    string  `json:"title" xml:"title,attr"`
+	Type     string  `json:"type" xml:"type,attr"`
+	UserID   FlexInt `json:"userID" xml:"userID,attr"`
+	UUID     string  `json:"uuid" xml:"uuid,attr"`
+	// Context identifies what the activity works on, i.e. the library being scanned
+	Context ActivityContext `json:"Context" xml:"Context"`
+}
+
+// ActivityContext is what an activity works on
+type ActivityContext struct {
+	Key              string     `json:"key" xml:"key,attr"`
+	LibrarySectionID FlexString `json:"librarySectionID" xml:"librarySectionID,attr"`
+}
+
+type activitiesResponse struct {
+	MediaContainer struct {
+		Activity []Activity `json:"Activity" xml:"Activity"`
+	} `json:"MediaContainer" xml:"MediaContainer"`
+}
+
+// scanActivityTypes are the activity types of library scans and metadata refreshes
+var scanActivityTypes = map[string]bool{
+	"library.update.section": true,
+	"library.refresh.items":  true,
+}
+
+// ActivityWatcher follows the activities of a server, like library scans, through
+// its notifications. Start watching before triggering the activity so its end is not missed
+//
+//	watcher, err := plexConn.WatchActivities()
+//
+//	if err != nil {
+//		...
+//	}
+//
+//	defer watcher.Close()
+//
+//	if err := plexConn.ScanLibraryPath("1", "/data/movies/Alien (1979)"); err != nil {
+//		...
+//	}
+//
+//	err = watcher.WaitForScan(ctx, "1")
+type ActivityWatcher struct {
+	plex   *Plex
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	running map[string]ActivityNotification
+	ended   map[string]ActivityNotification
+	changed chan struct{}
+	err     error
+	closed  bool
+}
+
+// WatchActivities subscribes to the notifications of the server and tracks its activities until Close is called
+func (p *Plex) WatchActivities() (*ActivityWatcher, error) {
+	return p.WatchActivitiesCtx(context.Background())
+}
+
+// WatchActivitiesCtx is like WatchActivities but carries ctx for cancellation and deadlines.
+// Cancelling ctx stops the watcher the same way Close does
+func (p *Plex) WatchActivitiesCtx(ctx context.Context) (*ActivityWatcher, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	w := &ActivityWatcher{
+		plex:    p,
+		cancel:  cancel,
+		running: map[string]ActivityNotification{},
+		ended:   map[string]ActivityNotification{},
+		changed: make(chan struct{}),
+	}
+
+	events := NewNotificationEvents()
+	events.OnActivity(w.handle)
+
+	// the connection is made before subscribe returns, so a failure to connect has been
+	// reported by then. Notifications other than activities are dropped without a log
+	p.subscribe(ctx, events, nil, w.fail, nil)
+
+	if err := w.Err(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *ActivityWatcher) handle(n NotificationContainer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, a := range n.ActivityNotification {
+		uuid := a.Activity.UUID
+
+		if uuid == "" {
+			uuid = a.UUID
+			a.Activity.UUID = uuid
+		}
+
+		if a.Event == ActivityEnded {
+			delete(w.running, uuid)
+			w.ended[uuid] = a
+		} else {
+			w.running[uuid] = a
+		}
+	}
+
+	w.notifyLocked()
+}
+
+func (w *ActivityWatcher) fail(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed || w.err != nil {
+		return
+	}
+
+	w.err = err
+	w.notifyLocked()
+}
+
+func (w *ActivityWatcher) notifyLocked() {
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+// Close stops watching and closes the notification connection
+func (w *ActivityWatcher) Close() {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+
+	w.cancel()
+}
+
+// Err returns the error that broke the notification connection, if any
+func (w *ActivityWatcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.err
+}
+
+// Running returns the activities that have started but not ended yet
+func (w *ActivityWatcher) Running() []ActivityNotification {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	activities := make([]ActivityNotification, 0, len(w.running))
+
+	for _, a := range w.running {
+		activities = append(activities, a)
+	}
+
+	sort.Slice(activities, func(i, j int) bool {
+		return activities[i].Activity.UUID < activities[j].Activity.UUID
+	})
+
+	return activities
+}
+
+// activityPollInterval is how often a wait asks GetActivities whether the activity is
+// still running, in case its end is reported under another type or not at all
+var activityPollInterval = 5 * time.Second
+
+// WaitForScan blocks until a scan or metadata refresh of the library has ended since the
+// watcher started and no other one is running. It returns early when ctx is done or the
+// notification connection breaks.
+//
+// Every few seconds the wait also asks GetActivities, so a scan whose end was not notified
+// does not block forever: once a scan of the library has been seen running, in the
+// notifications or in GetActivities, the wait returns when GetActivities lists none.
+// A scan that is never seen keeps the wait going until ctx is done, pass a ctx with a
+// deadline, a server can take hours to scan
+func (w *ActivityWatcher) WaitForScan(ctx context.Context, sectionKey string) error {
+	return w.waitForEnd(ctx, func(a Activity) bool {
+		return isScanOf(a, sectionKey)
+	})
+}
+
+// WaitForActivity is like WaitForScan for the activities whose Type is activityType
+func (w *ActivityWatcher) WaitForActivity(ctx context.Context, activityType string) error {
+	return w.waitForEnd(ctx, func(a Activity) bool {
+		return a.Type == activityType
+	})
+}
+
+// Run calls start, which triggers a background operation like OptimizeDatabase, and waits
+// like WaitForScan for the activity it started to end. That activity is the first one the
+// notifications or GetActivities report that was not running or ended before start was
+// called, whatever its type. Run one operation at a time, an activity started meanwhile
+// by someone else could be taken for it
+//
+//	err := watcher.Run(ctx, plexConn.OptimizeDatabase)
+func (w *ActivityWatcher) Run(ctx context.Context, start func() error) error {
+	known, err := w.known(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if err := start(); err != nil {
+		return err
+	}
+
+	var started string
+
+	return w.waitForEnd(ctx, func(a Activity) bool {
+		if started == "" && a.UUID != "" && !known[a.UUID] {
+			started = a.UUID
+		}
+
+		return started != "" && a.UUID == started
+	})
+}
+
+// known returns the uuids of the activities the watcher or GetActivities know of
+func (w *ActivityWatcher) known(ctx context.Context) (map[string]bool, error) {
+	activities, err := w.plex.GetActivitiesCtx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]bool{}
+
+	for _, a := range activities {
+		known[a.UUID] = true
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for uuid := range w.running {
+		known[uuid] = true
+	}
+
+	for uuid := range w.ended {
+		known[uuid] = true
+	}
+
+	return known, nil
+}
+
+// waitForEnd blocks until an activity matching match has ended and none is running,
+// or until GetActivities reports none running after one was seen
+func (w *ActivityWatcher) waitForEnd(ctx context.Context, match func(Activity) bool) error {
+	ticker := time.NewTicker(activityPollInterval)
+	defer ticker.Stop()
+
+	seen := false
+
+	for {
+		w.mu.Lock()
+		running, ended := w.stateLocked(match)
+		err := w.err
+		changed := w.changed
+		w.mu.Unlock()
+
+		if ended && !running {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		seen = seen || running
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		case <-ticker.C:
+			running, ok := w.poll(ctx, match)
+
+			if running {
+				seen = true
+			} else if ok && seen {
+				return nil
+			}
+		}
+	}
+}
+
+// stateLocked reports whether an activity matching match is running and whether one has ended
+func (w *ActivityWatcher) stateLocked(match func(Activity) bool) (running, ended bool) {
+	for _, a := range w.running {
+		if match(a.Activity) {
+			running = true
+			break
+		}
+	}
+
+	for _, a := range w.ended {
+		if match(a.Activity) {
+			ended = true
+			break
+		}
+	}
+
+	return running, ended
+}
+
+// poll reports whether GetActivities lists an activity matching match. ok is false when
+// the server could not be asked, so the wait goes on with the notifications alone
+func (w *ActivityWatcher) poll(ctx context.Context, match func(Activity) bool) (running, ok bool) {
+	activities, err := w.plex.GetActivitiesCtx(ctx)
+
+	if err != nil {
+		return false, false
+	}
+
+	for _, a := range activities {
+		if match(a) {
+			return true, true
+		}
+	}
+
+	return false, true
+}
+
+func isScanOf(a Activity, sectionKey string) bool {
+	return scanActivityTypes[a.Type] && a.Context.LibrarySectionID.String() == sectionKey
+}
+
+// WaitForScan blocks until a scan of the library that is running, or about to start, ends.
+// The end of a scan that finished before the call is missed and the wait lasts until ctx is
+// done, use WatchActivities to trigger and wait without a gap
+func (p *Plex) WaitForScan(sectionKey string) error {
+	return p.WaitForScanCtx(context.Background(), sectionKey)
+}
+
+// WaitForScanCtx is like WaitForScan but carries ctx for cancellation and deadlines
+func (p *Plex) WaitForScanCtx(ctx context.Context, sectionKey string) error {
+	w, err := p.WatchActivitiesCtx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer w.Close()
+
+	return w.WaitForScan(ctx, sectionKey)
+}
+
+// GetActivities returns the activities running on the server. Unlike WatchActivities it
+// needs no notification connection, which suits polling from a cli or a dashboard
+func (p *Plex) GetActivities() ([]Activity, error) {
+	return p.GetActivitiesCtx(context.Background())
+}
+
+// GetActivitiesCtx is like GetActivities but carries ctx for cancellation and deadlines
+func (p *Plex) GetActivitiesCtx(ctx context.Context) ([]Activity, error) {
+	resp, err := p.get(ctx, p.URL+"/activities", p.Headers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var result activitiesResponse
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.MediaContainer.Activity, nil
+}
+
+// CancelActivity stops a running activity. Only activities that are Cancellable can be stopped
+func (p *Plex) CancelActivity(uuid string) error {
+	return p.CancelActivityCtx(context.Background(), uuid)
+}
+
+// CancelActivityCtx is like CancelActivity but carries ctx for cancellation and deadlines
+func (p *Plex) CancelActivityCtx(ctx context.Context, uuid string) error {
+	if uuid == "" {
+		return fmt.Errorf(ErrorCommon, "an activity uuid is required")
+	}
+
+	return p.action(ctx, http.MethodDelete, "/activities/"+uuid, nil)
+}
@@ -0,0 +1,308 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func TestScanLibraryAndWait(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	watcher, err := conn.WatchActivitiesCtx(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer watcher.Close()
+
+	if err := server.WaitForSubscribers(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.ScanLibraryPath("1", "/data/movies/Alien (1979)"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := watcher.WaitForScan(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if running := watcher.Running(); len(running) != 0 {
+		t.Errorf("expected no running activities, got %+v", running)
+	}
+
+	requests := server.Requests()
+
+	if last := requests[len(requests)-1]; last.Path != "/library/sections/1/refresh" || last.Query.Get("path") != "/data/movies/Alien (1979)" {
+		t.Errorf("unexpected scan request %+v", last)
+	}
+}
+
+func TestWaitForScanIgnoresOtherLibraries(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	watcher, err := conn.WatchActivitiesCtx(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer watcher.Close()
+
+	if err := server.WaitForSubscribers(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	scan := plextest.Activity{UUID: "scan-2", Type: "library.update.section", SectionKey: "2"}
+
+	if err := server.NotifyActivity(ActivityStarted, scan); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := server.NotifyActivity(ActivityEnded, scan); err != nil {
+		t.Fatal(err)
+	}
+
+	shortCtx, shortCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer shortCancel()
+
+	if err := watcher.WaitForScan(shortCtx, "1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the wait to time out, got %v", err)
+	}
+
+	if err := watcher.WaitForScan(ctx, "2"); err != nil {
+		t.Errorf("expected the scan of library 2 to be seen, got %v", err)
+	}
+}
+
+func TestWatcherDoesNotPrint(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	r, w, err := os.Pipe()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+
+	defer func() { os.Stdout = stdout }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	watcher, err := conn.WatchActivitiesCtx(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := server.WaitForSubscribers(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// notifications arrive in order, so the timeline is handled once the activity ended
+	if err := server.Notify(map[string]interface{}{"type": "timeline", "size": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	optimize := plextest.Activity{UUID: "optimize", Type: "database.optimize"}
+
+	if err := server.NotifyActivity(ActivityEnded, optimize); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := watcher.WaitForActivity(ctx, optimize.Type); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher.Close()
+
+	// give the connection time to close, which used to print as well
+	time.Sleep(200 * time.Millisecond)
+
+	os.Stdout = stdout
+	w.Close()
+
+	printed, err := ioutil.ReadAll(r)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(printed) != 0 {
+		t.Errorf("expected the watcher to print nothing, got %q", printed)
+	}
+}
+
+func TestRunWaitsForTheStartedActivity(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	interval := activityPollInterval
+	activityPollInterval = 50 * time.Millisecond
+
+	defer func() { activityPollInterval = interval }()
+
+	// the optimization is not notified. An older activity of the same type keeps running
+	// while the new one is listed once, then gone
+	server.Handle(http.MethodPut, "/library/optimize", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	old := `{"uuid":"old","type":"database.optimize"}`
+	responses := []string{old, old + `,{"uuid":"new","type":"database.optimize"}`, old}
+
+	var polls int32
+
+	server.Handle(http.MethodGet, "/activities", func(w http.ResponseWriter, r *http.Request) {
+		poll := int(atomic.AddInt32(&polls, 1)) - 1
+
+		if poll >= len(responses) {
+			poll = len(responses) - 1
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"MediaContainer":{"Activity":[` + responses[poll] + `]}}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	watcher, err := conn.WatchActivitiesCtx(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer watcher.Close()
+
+	if err := watcher.Run(ctx, conn.OptimizeDatabase); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := atomic.LoadInt32(&polls); n != 3 {
+		t.Errorf("expected the run to end on the third request to /activities, got %d", n)
+	}
+
+	failed := errors.New("failed to start")
+
+	if err := watcher.Run(ctx, func() error { return failed }); err != failed {
+		t.Errorf("expected the error of start, got %v", err)
+	}
+}
+
+func TestScanLibraryErrors(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	if err := conn.ScanLibrary("99"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown library, got %v", err)
+	}
+
+	if err := conn.ScanLibraryPath("1", ""); err == nil {
+		t.Error("expected an error without a path")
+	}
+
+	if err := conn.RefreshLibraryMetadata("1"); err != nil {
+		t.Error(err)
+	}
+
+	if err := conn.CancelLibraryScan("1"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetAndCancelActivities(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	activities, err := conn.GetActivities()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(activities) != 3 {
+		t.Fatalf("expected 3 activities, got %+v", activities)
+	}
+
+	analysis := activities[0]
+
+	if analysis.Type != "media.generate.bif" || analysis.Progress.Int() != 40 || !analysis.Cancellable.Bool() || analysis.Context.LibrarySectionID.String() != "1" {
+		t.Errorf("unexpected activity %+v", analysis)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	watcher, err := conn.WatchActivitiesCtx(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer watcher.Close()
+
+	if err := server.WaitForSubscribers(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.CancelActivity(analysis.UUID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := watcher.WaitForActivity(ctx, analysis.Type); err != nil {
+		t.Fatal(err)
+	}
+
+	if activities, err = conn.GetActivities(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(activities) != 2 || activities[0].UUID == analysis.UUID {
+		t.Errorf("expected the analysis to be gone, got %+v", activities)
+	}
+
+	// the database backup cannot be cancelled
+	if err := conn.CancelActivity(activities[1].UUID); err == nil {
+		t.Error("expected an error when cancelling an activity that is not cancellable")
+	}
+
+	if err := conn.CancelActivity(analysis.UUID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an activity that ended, got %v", err)
+	}
+
+	if err := conn.CancelActivity(""); err == nil {
+		t.Error("expected an error without a uuid")
+	}
+}
+
+func TestGetActivitiesXML(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	conn.Headers.Accept = "application/xml"
+
+	activities, err := conn.GetActivities()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(activities) != 3 || activities[1].Title != "Optimizing" || activities[0].Context.Key != "/library/sections/1" || activities[2].Cancellable.Bool() {
+		t.Errorf("unexpected activities %+v", activities)
+	}
+}
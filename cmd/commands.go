@@ -284,7 +284,7 @@ func getSessions(c *cli.Context) error {
 			userIsWatching += session.GrandparentTitle + " - " + session.ParentTitle
 			userIsWatching += " - " + session.Title
 		} else {
-			userIsWatching += session.Title + " (" + string(session.Year) + ")"
+			userIsWatching += session.Title + " (" + session.Year.String() + ")"
 		}
 
 		fmt.Println(userIsWatching)
@@ -363,13 +363,7 @@ func linkApp(c *cli.Context) error {
 		return cli.NewExitError("request plex pin failed: "+err.Error(), 1)
 	}
 
-	expireAtParsed, err := time.Parse(time.RFC3339, info.ExpiresAt)
-
-	if err != nil {
-		return cli.NewExitError(fmt.Sprintf("could not get expiration for plex pin: %v", err), 1)
-	}
-
-	expires := time.Until(expireAtParsed).String()
+	expires := time.Until(info.ExpiresAt.Time).String()
 
 	fmt.Printf("your pin %s and expires in %s\n", info.Code, expires)
 
@@ -762,7 +756,7 @@ func stopPlayback(c *cli.Context) error {
 		return cli.NewExitError(err, 1)
 	}
 
-	sessionCount := sessions.MediaContainer.Size
+	sessionCount := sessions.MediaContainer.Size.Int()
 
 	if sessionCount < 1 {
 		fmt.Println("no users in session")
@@ -780,7 +774,7 @@ func stopPlayback(c *cli.Context) error {
 			title += session.GrandparentTitle + " - " + session.ParentTitle
 			title += " - " + session.Title
 		} else {
-			title += session.Title + " (" + string(session.Year) + ")"
+			title += session.Title + " (" + session.Year.String() + ")"
 		}
 
 		fmt.Printf("\t[%d] %s - %s\n", i, session.User.Title, title)
@@ -0,0 +1,88 @@
+package plex
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// media types sent in the Accept header. Plex Media Server answers both, most legacy plex.tv
+// endpoints only answer xml regardless of what is asked for
+const (
+	mimeJSON = "application/json"
+	mimeXML  = "application/xml"
+)
+
+// decodeResponse decodes the body of resp into v as json or xml according to the Content-Type
+// of the response. When the server does not send one the format is sniffed from the body
+func decodeResponse(resp *http.Response, v interface{}) error {
+	body := bufio.NewReader(resp.Body)
+
+	isXML, err := responseIsXML(resp.Header.Get("Content-Type"), body)
+
+	if err != nil {
+		return err
+	}
+
+	if isXML {
+		return xml.NewDecoder(body).Decode(xmlTarget(v))
+	}
+
+	return json.NewDecoder(body).Decode(v)
+}
+
+func responseIsXML(contentType string, body *bufio.Reader) (bool, error) {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch {
+		case strings.HasSuffix(mediaType, "xml"):
+			return true, nil
+		case strings.HasSuffix(mediaType, "json"):
+			return false, nil
+		}
+	}
+
+	for {
+		b, err := body.Peek(1)
+
+		if err == io.EOF {
+			return false, nil
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			body.Discard(1)
+		case '<':
+			return true, nil
+		default:
+			return false, nil
+		}
+	}
+}
+
+// xmlTarget returns where an xml body should be decoded into. json responses wrap the
+// container in a "MediaContainer" object while in xml it is the root element, so
+// wrapper types such as SearchResults are decoded straight into their MediaContainer field
+func xmlTarget(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return v
+	}
+
+	field, ok := rv.Elem().Type().FieldByName("MediaContainer")
+
+	if !ok || len(field.Index) != 1 || field.Tag.Get("json") != "MediaContainer" {
+		return v
+	}
+
+	return rv.Elem().FieldByIndex(field.Index).Addr().Interface()
+}
@@ -0,0 +1,85 @@
+package plex
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	jsonBody := `{"MediaContainer":{"size":1,"librarySectionID":2,"Metadata":[{"ratingKey":"10","title":"Alien","type":"movie","Media":[{"bitrate":1000}]}]}}`
+
+	xmlBody := `
+<?xml version="1.0" encoding="UTF-8"?>
+<MediaContainer size="1" librarySectionID="2">
+	<Video ratingKey="10" title="Alien" type="movie">
+		<Media bitrate="1000" />
+	</Video>
+</MediaContainer>`
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"json", "application/json", jsonBody},
+		{"xml", "text/xml;charset=utf-8", xmlBody},
+		{"sniffed json", "", jsonBody},
+		{"sniffed xml", "", xmlBody},
+	}
+
+	for _, tt := range tests {
+		resp := &http.Response{
+			Header: http.Header{},
+			Body:   ioutil.NopCloser(strings.NewReader(tt.body)),
+		}
+
+		if tt.contentType != "" {
+			resp.Header.Set("Content-Type", tt.contentType)
+		}
+
+		var results SearchResults
+
+		if err := decodeResponse(resp, &results); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+
+		mc := results.MediaContainer
+
+		if mc.Size != 1 || mc.LibrarySectionID != 2 || len(mc.Metadata) != 1 {
+			t.Errorf("%s: unexpected container %+v", tt.name, mc.MediaContainer)
+			continue
+		}
+
+		item := mc.Metadata[0]
+
+		if item.RatingKey != "10" || item.Title != "Alien" || len(item.Media) != 1 || item.Media[0].Bitrate != 1000 {
+			t.Errorf("%s: unexpected metadata %+v", tt.name, item)
+		}
+	}
+}
+
+func TestDecodeResponseAccount(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{"Content-Type": []string{"application/xml"}},
+		Body: ioutil.NopCloser(strings.NewReader(`<user id="1" uuid="abc" username="jane" authToken="token" hasPassword="true">
+	<subscription active="1" status="Active" plan="lifetime" />
+</user>`)),
+	}
+
+	var account UserPlexTV
+
+	if err := decodeResponse(resp, &account); err != nil {
+		t.Fatal(err)
+	}
+
+	if account.ID != 1 || account.Username != "jane" || account.AuthToken != "token" || !account.HasPassword {
+		t.Errorf("unexpected account %+v", account)
+	}
+
+	if !account.Subscription.Active || account.Subscription.Plan != "lifetime" {
+		t.Errorf("unexpected subscription %+v", account.Subscription)
+	}
+}
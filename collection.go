@@ -0,0 +1,304 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CollectionMode decides whether a collection and its items are shown in the library
+type CollectionMode int
+
+// Collection modes
+const (
+	// CollectionModeDefault uses the setting of the library
+	CollectionModeDefault CollectionMode = -1
+	// CollectionModeHide hides the collection and shows its items
+	CollectionModeHide CollectionMode = 0
+	// CollectionModeHideItems shows the collection instead of its items
+	CollectionModeHideItems CollectionMode = 1
+	// CollectionModeShowItems shows the collection and its items
+	CollectionModeShowItems CollectionMode = 2
+)
+
+// CollectionSort is the order of the items in a collection
+type CollectionSort int
+
+// Collection sort orders
+const (
+	CollectionSortRelease CollectionSort = 0
+	CollectionSortTitle   CollectionSort = 1
+	CollectionSortCustom  CollectionSort = 2
+)
+
+// Collection is a collection of a library
+type Collection struct {
+	RatingKey string `json:"ratingKey" xml:"ratingKey,attr"`
+	Key       string `json:"key" xml:"key,attr"`
+	GUID      string `json:"guid" xml:"guid,attr"`
+	Type      string `json:"type" xml:"type,attr"`
+	Title     string `json:"title" xml:"title,attr"`
+	Summary   string `json:"summary" xml:"summary,attr"`
+	// Subtype is the type of the items in the collection, i.e. movie
+	Subtype string `json:"subtype" xml:"subtype,attr"`
+	// Smart collections are filled by a library filter instead of a list of items
+	Smart FlexBool `json:"smart" xml:"smart,attr"`
+	// Content is the uri of the filter of a smart collection
+	Content          string    `json:"content" xml:"content,attr"`
+	ChildCount       FlexInt   `json:"childCount" xml:"childCount,attr"`
+	CollectionMode   FlexInt   `json:"collectionMode" xml:"collectionMode,attr"`
+	CollectionSort   FlexInt   `json:"collectionSort" xml:"collectionSort,attr"`
+	Thumb            string    `json:"thumb" xml:"thumb,attr"`
+	LibrarySectionID FlexInt   `json:"librarySectionID" xml:"librarySectionID,attr"`
+	AddedAt          Timestamp `json:"addedAt" xml:"addedAt,attr"`
+	UpdatedAt        Timestamp `json:"updatedAt" xml:"updatedAt,attr"`
+}
+
+type collectionsResponse struct {
+	MediaContainer struct {
+		Metadata []Collection `json:"Metadata" xml:",any"`
+	} `json:"MediaContainer" xml:"MediaContainer"`
+}
+
+// CreateCollectionParams describe a new collection
+type CreateCollectionParams struct {
+	Title      string
+	SectionKey string
+	// Type is the type of the items in the collection, i.e. movie or show
+	Type string
+	// RatingKeys are the items of a regular collection
+	RatingKeys []string
+	// Smart collections are filled with the content of the library matching Filter,
+	// i.e. plex.NewLibraryQuery().Type("movie").Genre("Horror").Encode()
+	Smart  bool
+	Filter string
+}
+
+// GetCollections lists the collections of a library
+func (p *Plex) GetCollections(sectionKey string) ([]Collection, error) {
+	return p.GetCollectionsCtx(context.Background(), sectionKey)
+}
+
+// GetCollectionsCtx is like GetCollections but carries ctx for cancellation and deadlines
+func (p *Plex) GetCollectionsCtx(ctx context.Context, sectionKey string) ([]Collection, error) {
+	if sectionKey == "" {
+		return nil, fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.getCollections(ctx, http.MethodGet, fmt.Sprintf("%s/library/sections/%s/collections", p.URL, sectionKey))
+}
+
+func (p *Plex) getCollections(ctx context.Context, method, query string) ([]Collection, error) {
+	resp, err := p.do(ctx, &p.HTTPClient, method, query, nil, p.Headers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var result collectionsResponse
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.MediaContainer.Metadata, nil
+}
+
+// GetCollectionItems lists the items of a collection
+func (p *Plex) GetCollectionItems(ratingKey string) (SearchResults, error) {
+	return p.GetCollectionItemsCtx(context.Background(), ratingKey)
+}
+
+// GetCollectionItemsCtx is like GetCollectionItems but carries ctx for cancellation and deadlines
+func (p *Plex) GetCollectionItemsCtx(ctx context.Context, ratingKey string) (SearchResults, error) {
+	if ratingKey == "" {
+		return SearchResults{}, fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	resp, err := p.get(ctx, fmt.Sprintf("%s/library/collections/%s/children", p.URL, ratingKey), p.Headers)
+
+	if err != nil {
+		return SearchResults{}, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return SearchResults{}, newAPIError(resp)
+	}
+
+	var results SearchResults
+
+	if err := decodeResponse(resp, &results); err != nil {
+		return SearchResults{}, err
+	}
+
+	return results, nil
+}
+
+// CreateCollection creates a regular or smart collection and returns it
+func (p *Plex) CreateCollection(params CreateCollectionParams) (Collection, error) {
+	return p.CreateCollectionCtx(context.Background(), params)
+}
+
+// CreateCollectionCtx is like CreateCollection but carries ctx for cancellation and deadlines
+func (p *Plex) CreateCollectionCtx(ctx context.Context, params CreateCollectionParams) (Collection, error) {
+	if params.Title == "" {
+		return Collection{}, fmt.Errorf(ErrorCommon, ErrorTitleRequired)
+	}
+
+	if params.SectionKey == "" {
+		return Collection{}, fmt.Errorf(ErrorCommon, "section key is required")
+	}
+
+	if params.Type == "" {
+		return Collection{}, fmt.Errorf(ErrorCommon, "type is required")
+	}
+
+	if !params.Smart && len(params.RatingKeys) == 0 {
+		return Collection{}, fmt.Errorf(ErrorCommon, "a regular collection needs rating keys")
+	}
+
+	path := "/library/metadata/" + strings.Join(params.RatingKeys, ",")
+
+	if params.Smart {
+		path = "/library/sections/" + params.SectionKey + "/all"
+
+		if params.Filter != "" {
+			path += "?" + strings.TrimPrefix(params.Filter, "?")
+		}
+	}
+
+	uri, err := p.libraryURI(ctx, path)
+
+	if err != nil {
+		return Collection{}, err
+	}
+
+	vals := url.Values{}
+
+	vals.Set("title", params.Title)
+	vals.Set("sectionId", params.SectionKey)
+	vals.Set("type", GetMediaTypeID(params.Type))
+	vals.Set("uri", uri)
+	vals.Set("smart", "0")
+
+	if params.Smart {
+		vals.Set("smart", "1")
+	}
+
+	collections, err := p.getCollections(ctx, http.MethodPost, p.URL+"/library/collections?"+vals.Encode())
+
+	if err != nil {
+		return Collection{}, err
+	}
+
+	if len(collections) == 0 {
+		return Collection{}, fmt.Errorf(ErrorCommon, "server did not return the new collection")
+	}
+
+	return collections[0], nil
+}
+
+// AddToCollection adds items to a regular collection
+func (p *Plex) AddToCollection(ratingKey string, itemRatingKeys ...string) error {
+	return p.AddToCollectionCtx(context.Background(), ratingKey, itemRatingKeys...)
+}
+
+// AddToCollectionCtx is like AddToCollection but carries ctx for cancellation and deadlines
+func (p *Plex) AddToCollectionCtx(ctx context.Context, ratingKey string, itemRatingKeys ...string) error {
+	if ratingKey == "" || len(itemRatingKeys) == 0 {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	uri, err := p.libraryURI(ctx, "/library/metadata/"+strings.Join(itemRatingKeys, ","))
+
+	if err != nil {
+		return err
+	}
+
+	return p.action(ctx, http.MethodPut, "/library/collections/"+ratingKey+"/items", url.Values{"uri": []string{uri}})
+}
+
+// RemoveFromCollection removes an item from a regular collection
+func (p *Plex) RemoveFromCollection(ratingKey, itemRatingKey string) error {
+	return p.RemoveFromCollectionCtx(context.Background(), ratingKey, itemRatingKey)
+}
+
+// RemoveFromCollectionCtx is like RemoveFromCollection but carries ctx for cancellation and deadlines
+func (p *Plex) RemoveFromCollectionCtx(ctx context.Context, ratingKey, itemRatingKey string) error {
+	if ratingKey == "" || itemRatingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(ctx, http.MethodDelete, "/library/collections/"+ratingKey+"/items/"+itemRatingKey, nil)
+}
+
+// SetCollectionMode changes whether the collection and its items are shown in the library
+func (p *Plex) SetCollectionMode(ratingKey string, mode CollectionMode) error {
+	return p.SetCollectionModeCtx(context.Background(), ratingKey, mode)
+}
+
+// SetCollectionModeCtx is like SetCollectionMode but carries ctx for cancellation and deadlines
+func (p *Plex) SetCollectionModeCtx(ctx context.Context, ratingKey string, mode CollectionMode) error {
+	return p.setCollectionPref(ctx, ratingKey, "collectionMode", int(mode))
+}
+
+// SetCollectionSort changes the order of the items in the collection
+func (p *Plex) SetCollectionSort(ratingKey string, sort CollectionSort) error {
+	return p.SetCollectionSortCtx(context.Background(), ratingKey, sort)
+}
+
+// SetCollectionSortCtx is like SetCollectionSort but carries ctx for cancellation and deadlines
+func (p *Plex) SetCollectionSortCtx(ctx context.Context, ratingKey string, sort CollectionSort) error {
+	return p.setCollectionPref(ctx, ratingKey, "collectionSort", int(sort))
+}
+
+func (p *Plex) setCollectionPref(ctx context.Context, ratingKey, pref string, value int) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(ctx, http.MethodPut, "/library/metadata/"+ratingKey+"/prefs", url.Values{pref: []string{strconv.Itoa(value)}})
+}
+
+// SetCollectionPoster downloads the image at posterURL and makes it the poster of the collection
+func (p *Plex) SetCollectionPoster(ratingKey, posterURL string) error {
+	return p.SetCollectionPosterCtx(context.Background(), ratingKey, posterURL)
+}
+
+// SetCollectionPosterCtx is like SetCollectionPoster but carries ctx for cancellation and deadlines
+func (p *Plex) SetCollectionPosterCtx(ctx context.Context, ratingKey, posterURL string) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	if posterURL == "" {
+		return fmt.Errorf(ErrorCommon, "poster url is required")
+	}
+
+	return p.action(ctx, http.MethodPost, "/library/metadata/"+ratingKey+"/posters", url.Values{"url": []string{posterURL}})
+}
+
+// DeleteCollection removes a collection. The items in it are not affected
+func (p *Plex) DeleteCollection(ratingKey string) error {
+	return p.DeleteCollectionCtx(context.Background(), ratingKey)
+}
+
+// DeleteCollectionCtx is like DeleteCollection but carries ctx for cancellation and deadlines
+func (p *Plex) DeleteCollectionCtx(ctx context.Context, ratingKey string) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(ctx, http.MethodDelete, "/library/collections/"+ratingKey, nil)
+}
@@ -0,0 +1,131 @@
+package plex
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func collectionTitles(t *testing.T, conn *Plex, ratingKey string) string {
+	items, err := conn.GetCollectionItems(ratingKey)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var titles []string
+
+	for _, item := range items.MediaContainer.Metadata {
+		titles = append(titles, item.Title)
+	}
+
+	return strings.Join(titles, ",")
+}
+
+func TestCollections(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	collections, err := conn.GetCollections("1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(collections) != 1 || collections[0].Title != "Alien" || collections[0].ChildCount.Int() != 2 {
+		t.Fatalf("unexpected collections %+v", collections)
+	}
+
+	collection, err := conn.CreateCollection(CreateCollectionParams{
+		Title:      "Ridley Scott",
+		SectionKey: "1",
+		Type:       "movie",
+		RatingKeys: []string{"100"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.AddToCollection(collection.RatingKey, "102"); err != nil {
+		t.Fatal(err)
+	}
+
+	if titles := collectionTitles(t, conn, collection.RatingKey); titles != "Alien,Blade Runner" {
+		t.Errorf("unexpected collection items %s", titles)
+	}
+
+	// membership is the collection tag of the items, so it shows up in their metadata too
+	metadata, err := conn.GetMetadata("102")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tags := metadata.MediaContainer.Metadata[0].Collection; len(tags) != 1 || tags[0].Tag != "Ridley Scott" {
+		t.Errorf("unexpected collection tags %+v", tags)
+	}
+
+	if err := conn.RemoveFromCollection(collection.RatingKey, "100"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.SetCollectionMode(collection.RatingKey, CollectionModeHideItems); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.SetCollectionSort(collection.RatingKey, CollectionSortTitle); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.SetCollectionPoster(collection.RatingKey, "https://example.com/poster.jpg"); err != nil {
+		t.Fatal(err)
+	}
+
+	if collections, err = conn.GetCollections("1"); err != nil {
+		t.Fatal(err)
+	}
+
+	updated := collections[1]
+
+	if updated.ChildCount.Int() != 1 || updated.CollectionMode.Int() != int(CollectionModeHideItems) || updated.CollectionSort.Int() != int(CollectionSortTitle) || updated.Thumb != "https://example.com/poster.jpg" {
+		t.Errorf("unexpected collection %+v", updated)
+	}
+
+	if err := conn.DeleteCollection(collection.RatingKey); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.DeleteCollection(collection.RatingKey); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a deleted collection, got %v", err)
+	}
+}
+
+func TestSmartCollection(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	collection, err := conn.CreateCollection(CreateCollectionParams{
+		Title:      "Eighties",
+		SectionKey: "1",
+		Type:       "movie",
+		Smart:      true,
+		Filter:     NewLibraryQuery().Type("movie").Where("year", OpGreaterThan, "1980").Sort("year", false).Encode(),
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !collection.Smart.Bool() || collection.Content == "" {
+		t.Errorf("expected a smart collection, got %+v", collection)
+	}
+
+	if titles := collectionTitles(t, conn, collection.RatingKey); titles != "Blade Runner,Aliens" {
+		t.Errorf("unexpected smart collection items %s", titles)
+	}
+
+	if err := conn.AddToCollection(collection.RatingKey, "100"); err == nil {
+		t.Error("expected an error when adding items to a smart collection")
+	}
+}
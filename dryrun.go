@@ -0,0 +1,39 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type mutatingKey struct{}
+
+// mutating marks the requests sent with ctx as changing the server, for the endpoints
+// that do so over GET, like /:/scrobble, so dry runs do not send them
+func mutating(ctx context.Context) context.Context {
+	return context.WithValue(ctx, mutatingKey{}, true)
+}
+
+// isReadOnly reports whether req leaves the server unchanged
+func isReadOnly(req *http.Request) bool {
+	if marked, _ := req.Context().Value(mutatingKey{}).(bool); marked {
+		return false
+	}
+
+	return req.Method == http.MethodGet || req.Method == http.MethodHead
+}
+
+// dryRun logs the requests that change the server to w and fails them with ErrDryRun
+// instead of sending them. GET and HEAD requests go through unless marked with mutating
+func dryRun(w io.Writer, next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if isReadOnly(req) {
+			return next.RoundTrip(req)
+		}
+
+		fmt.Fprintf(w, "dry run: %s %s\n", req.Method, req.URL)
+
+		return nil, ErrDryRun
+	})
+}
@@ -0,0 +1,104 @@
+package plex
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func TestDeleteMetadata(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	if err := conn.DeleteMediaVersion("100", "100"); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, err := conn.GetMetadata("100")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if media := metadata.MediaContainer.Metadata[0].Media; len(media) != 0 {
+		t.Errorf("expected the media version to be gone, got %+v", media)
+	}
+
+	// deleting a season deletes its episodes too
+	if err := conn.DeleteMetadata("201"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ratingKey := range []string{"201", "202", "203"} {
+		if _, err := conn.GetMetadata(ratingKey); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected %s to be deleted, got %v", ratingKey, err)
+		}
+	}
+
+	if err := conn.DeleteMetadata("201"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a deleted item, got %v", err)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	var log bytes.Buffer
+
+	conn, server := newTestConn(t, plextest.DefaultFixtures(), WithDryRun(&log))
+
+	if err := conn.DeleteMetadata("100"); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, got %v", err)
+	}
+
+	if err := conn.DeleteMediaVersion("101", "101"); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, got %v", err)
+	}
+
+	// scrobbles, progress and scans change the server over GET
+	if err := conn.MarkWatched("100"); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, got %v", err)
+	}
+
+	if err := conn.SetProgress("100", 60*time.Second); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, got %v", err)
+	}
+
+	if err := conn.ScanLibrary("1"); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, got %v", err)
+	}
+
+	// reads are still sent
+	metadata, err := conn.GetMetadata("100")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if metadata.MediaContainer.Metadata[0].Title != "Alien" {
+		t.Errorf("unexpected metadata %+v", metadata.MediaContainer.Metadata[0])
+	}
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+
+	if len(lines) != 5 || lines[0] != "dry run: DELETE "+server.URL+"/library/metadata/100" || !strings.HasSuffix(lines[1], "/library/metadata/101/media/101") {
+		t.Errorf("unexpected dry run log %q", log.String())
+	}
+
+	for _, line := range lines[2:] {
+		if !strings.HasPrefix(line, "dry run: GET ") {
+			t.Errorf("expected a GET in the dry run log, got %q", line)
+		}
+	}
+
+	for _, request := range server.Requests() {
+		if request.Method != "GET" || request.Path != "/library/metadata/100" && request.Path != "/library/metadata/101" {
+			t.Errorf("expected only reads to reach the server, got %s %s", request.Method, request.Path)
+		}
+	}
+
+	if _, err := conn.GetMetadata("101"); err != nil {
+		t.Errorf("expected the item to be left alone, got %v", err)
+	}
+}
@@ -1,5 +1,17 @@
 package plex
 
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
 // ErrorInvalidToken a constant to help check invalid token errors
 const (
 	ErrorInvalidToken       = "invalid token"
@@ -16,3 +28,141 @@ const (
 	ErrorFailedToSetWebhook = "failed to set webhook"
 	ErrorWebhook            = "webhook error: %s"
 )
+
+// Sentinel errors that an *APIError matches with errors.Is
+var (
+	// ErrUnauthorized the token is missing, invalid or has no access to the resource:
+	// a 401, 403 or 422 response that is not ErrPlexPassRequired
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound the requested resource does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrPlexPassRequired the feature requires an active Plex Pass subscription. It is
+	// recognised by the message of the response mentioning the Plex Pass, whatever its
+	// status code, and returned without a request by the methods that check the server first
+	ErrPlexPassRequired = errors.New("plex pass required")
+	// ErrDryRun the request changes the server and was logged instead of sent, see WithDryRun
+	ErrDryRun = errors.New("dry run: request not sent")
+)
+
+// APIError is returned when Plex Media Server or plex.tv replies with an unexpected status code.
+// Network failures are returned as is, so errors.As(err, &apiErr) tells them apart
+type APIError struct {
+	// StatusCode is the http status code of the response
+	StatusCode int
+	// Status is the http status line of the response, i.e. "404 Not Found"
+	Status string
+	// Method is the http method of the request
+	Method string
+	// Endpoint is the path of the request without the query
+	Endpoint string
+	// Code is the plex error code when the response carried one
+	Code int
+	// Message is the plex error message when the response carried one
+	Message string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: %s", e.Method, e.Endpoint, e.Status)
+
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+
+	return msg
+}
+
+// Is reports whether the error matches one of the sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		switch e.StatusCode {
+		case http.StatusUnauthorized, http.StatusUnprocessableEntity:
+			return true
+		case http.StatusForbidden:
+			// a missing Plex Pass is not a missing permission
+			return !e.Is(ErrPlexPassRequired)
+		}
+
+		return false
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrPlexPassRequired:
+		// there is no status code of its own to go by, so the message is matched
+		return strings.Contains(strings.ToLower(e.Message), "plex pass")
+	}
+
+	return false
+}
+
+// plexErrorXML is the xml error body of plex.tv and Plex Media Server
+type plexErrorXML struct {
+	XMLName xml.Name
+	Code    int    `xml:"code,attr"`
+	Status  string `xml:"status,attr"`
+	Error   []struct {
+		Code    int    `xml:"code,attr"`
+		Message string `xml:"message,attr"`
+	} `xml:"error"`
+}
+
+// newAPIError builds an *APIError from a response with an unexpected status code.
+// The body is read to extract the plex error, closing it is left to the caller
+func newAPIError(resp *http.Response) error {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+
+	if apiErr.Status == "" {
+		apiErr.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.Endpoint = resp.Request.URL.Path
+	}
+
+	if resp.Body == nil {
+		return apiErr
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	if err != nil {
+		return apiErr
+	}
+
+	body = bytes.TrimSpace(body)
+
+	if len(body) == 0 {
+		return apiErr
+	}
+
+	switch body[0] {
+	case '{':
+		var jsonErr webhookErr
+
+		if err := json.Unmarshal(body, &jsonErr); err == nil && len(jsonErr.Err) > 0 {
+			apiErr.Code = jsonErr.Err[0].Code
+			apiErr.Message = jsonErr.Err[0].Message
+		}
+	case '<':
+		var xmlErr plexErrorXML
+
+		if err := xml.Unmarshal(body, &xmlErr); err != nil {
+			break
+		}
+
+		switch {
+		case len(xmlErr.Error) > 0:
+			apiErr.Code = xmlErr.Error[0].Code
+			apiErr.Message = xmlErr.Error[0].Message
+		case xmlErr.XMLName.Local == "Response":
+			apiErr.Code = xmlErr.Code
+			apiErr.Message = xmlErr.Status
+		}
+	}
+
+	return apiErr
+}
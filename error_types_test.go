@@ -0,0 +1,51 @@
+package plex
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		code     int
+		body     string
+		sentinel error
+		plexCode int
+		message  string
+	}{
+		{401, `<html><head><title>Unauthorized</title></head></html>`, ErrUnauthorized, 0, ""},
+		{404, `{"errors":[{"code":1020,"message":"Code not found or expired","status":404}]}`, ErrNotFound, 1020, "Code not found or expired"},
+		{403, `<?xml version="1.0" encoding="UTF-8"?><errors><error code="1999" message="Plex Pass subscription required"/></errors>`, ErrPlexPassRequired, 1999, "Plex Pass subscription required"},
+		{403, `<html><head><title>Forbidden</title></head></html>`, ErrUnauthorized, 0, ""},
+	}
+
+	for _, test := range tests {
+		_, _plex := newTestServer(test.code, test.body)
+
+		_, err := _plex.GetMetadata("1")
+
+		if !errors.Is(err, test.sentinel) {
+			t.Errorf("%d: expected %v, got %v", test.code, test.sentinel, err)
+			continue
+		}
+
+		var apiErr *APIError
+
+		if !errors.As(err, &apiErr) {
+			t.Errorf("%d: expected an *APIError, got %T", test.code, err)
+			continue
+		}
+
+		if apiErr.StatusCode != test.code || apiErr.Code != test.plexCode || apiErr.Message != test.message {
+			t.Errorf("%d: unexpected error fields: %+v", test.code, apiErr)
+		}
+
+		if apiErr.Method != "GET" || apiErr.Endpoint != "/library/metadata/1" {
+			t.Errorf("%d: unexpected request info: %s %s", test.code, apiErr.Method, apiErr.Endpoint)
+		}
+
+		if test.sentinel == ErrPlexPassRequired && errors.Is(err, ErrUnauthorized) {
+			t.Errorf("%d: expected a missing plex pass not to be ErrUnauthorized", test.code)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package plex
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Plex returns the same field as a number, a boolean, a "0"/"1" string or null depending
+// on the server version and the endpoint. The Flex types below decode from any of those
+// representations and encode as their plain go type
+
+// FlexBool is a bool that decodes from true/false, 1/0, "true"/"false", "1"/"0" and null
+type FlexBool bool
+
+// FlexInt is an int64 that decodes from numbers, numeric strings, booleans and null.
+// Floating point values are truncated
+type FlexInt int64
+
+// FlexFloat is a float64 that decodes from numbers, numeric strings, booleans and null
+type FlexFloat float64
+
+// FlexString is a string that decodes from strings, numbers, booleans and null
+type FlexString string
+
+// Bool returns b as a bool
+func (b FlexBool) Bool() bool {
+	return bool(b)
+}
+
+// Int returns i as an int
+func (i FlexInt) Int() int {
+	return int(i)
+}
+
+// Int64 returns i as an int64
+func (i FlexInt) Int64() int64 {
+	return int64(i)
+}
+
+// String returns i in base 10
+func (i FlexInt) String() string {
+	return strconv.FormatInt(int64(i), 10)
+}
+
+// Float64 returns f as a float64
+func (f FlexFloat) Float64() float64 {
+	return float64(f)
+}
+
+// String returns s as a string
+func (s FlexString) String() string {
+	return string(s)
+}
+
+// flexScalar returns the raw text of a json scalar, unquoting strings. ok is false for null
+func flexScalar(data []byte) (value string, ok bool, err error) {
+	data = bytes.TrimSpace(data)
+
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return "", false, nil
+	}
+
+	if data[0] == '"' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return "", false, err
+		}
+
+		return strings.TrimSpace(value), true, nil
+	}
+
+	if data[0] == '{' || data[0] == '[' {
+		return "", false, fmt.Errorf("plex: can not decode %s into a scalar", data)
+	}
+
+	return string(data), true, nil
+}
+
+func parseFlexBool(value string) (FlexBool, error) {
+	switch strings.ToLower(value) {
+	case "", "0", "false", "no", "off":
+		return false, nil
+	case "1", "true", "yes", "on":
+		return true, nil
+	}
+
+	// some servers send other non-zero numbers as truthy values
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f != 0, nil
+	}
+
+	return false, fmt.Errorf("plex: invalid boolean %q", value)
+}
+
+func parseFlexInt(value string) (FlexInt, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return 0, nil
+	case "true":
+		return 1, nil
+	case "false":
+		return 0, nil
+	}
+
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return FlexInt(i), nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("plex: invalid integer %q", value)
+	}
+
+	return FlexInt(f), nil
+}
+
+func parseFlexFloat(value string) (FlexFloat, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return 0, nil
+	case "true":
+		return 1, nil
+	case "false":
+		return 0, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("plex: invalid number %q", value)
+	}
+
+	return FlexFloat(f), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *FlexBool) UnmarshalJSON(data []byte) error {
+	value, ok, err := flexScalar(data)
+
+	if err != nil || !ok {
+		*b = false
+		return err
+	}
+
+	parsed, err := parseFlexBool(value)
+
+	if err != nil {
+		return err
+	}
+
+	*b = parsed
+
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr
+func (b *FlexBool) UnmarshalXMLAttr(attr xml.Attr) error {
+	parsed, err := parseFlexBool(strings.TrimSpace(attr.Value))
+
+	if err != nil {
+		return err
+	}
+
+	*b = parsed
+
+	return nil
+}
+
+// UnmarshalXML implements xml.Unmarshaler
+func (b *FlexBool) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var value string
+
+	if err := d.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+
+	return b.UnmarshalXMLAttr(xml.Attr{Value: value})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (i *FlexInt) UnmarshalJSON(data []byte) error {
+	value, ok, err := flexScalar(data)
+
+	if err != nil || !ok {
+		*i = 0
+		return err
+	}
+
+	parsed, err := parseFlexInt(value)
+
+	if err != nil {
+		return err
+	}
+
+	*i = parsed
+
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr
+func (i *FlexInt) UnmarshalXMLAttr(attr xml.Attr) error {
+	parsed, err := parseFlexInt(strings.TrimSpace(attr.Value))
+
+	if err != nil {
+		return err
+	}
+
+	*i = parsed
+
+	return nil
+}
+
+// UnmarshalXML implements xml.Unmarshaler
+func (i *FlexInt) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var value string
+
+	if err := d.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+
+	return i.UnmarshalXMLAttr(xml.Attr{Value: value})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (f *FlexFloat) UnmarshalJSON(data []byte) error {
+	value, ok, err := flexScalar(data)
+
+	if err != nil || !ok {
+		*f = 0
+		return err
+	}
+
+	parsed, err := parseFlexFloat(value)
+
+	if err != nil {
+		return err
+	}
+
+	*f = parsed
+
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr
+func (f *FlexFloat) UnmarshalXMLAttr(attr xml.Attr) error {
+	parsed, err := parseFlexFloat(strings.TrimSpace(attr.Value))
+
+	if err != nil {
+		return err
+	}
+
+	*f = parsed
+
+	return nil
+}
+
+// UnmarshalXML implements xml.Unmarshaler
+func (f *FlexFloat) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var value string
+
+	if err := d.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+
+	return f.UnmarshalXMLAttr(xml.Attr{Value: value})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *FlexString) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+
+		*s = FlexString(value)
+
+		return nil
+	}
+
+	value, _, err := flexScalar(data)
+
+	if err != nil {
+		return err
+	}
+
+	*s = FlexString(value)
+
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr
+func (s *FlexString) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = FlexString(attr.Value)
+
+	return nil
+}
+
+// UnmarshalXML implements xml.Unmarshaler
+func (s *FlexString) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var value string
+
+	if err := d.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+
+	*s = FlexString(value)
+
+	return nil
+}
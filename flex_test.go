@@ -0,0 +1,73 @@
+package plex
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+)
+
+func TestFlexJSON(t *testing.T) {
+	type flexModel struct {
+		Bool   FlexBool   `json:"bool"`
+		Int    FlexInt    `json:"int"`
+		Float  FlexFloat  `json:"float"`
+		String FlexString `json:"string"`
+	}
+
+	tests := []struct {
+		body string
+		want flexModel
+	}{
+		{`{"bool":true,"int":12,"float":1.5,"string":"abc"}`, flexModel{true, 12, 1.5, "abc"}},
+		{`{"bool":1,"int":"12","float":"1.5","string":12}`, flexModel{true, 12, 1.5, "12"}},
+		{`{"bool":"1","int":12.0,"float":2,"string":true}`, flexModel{true, 12, 2, "true"}},
+		{`{"bool":"false","int":true,"float":false,"string":""}`, flexModel{false, 1, 0, ""}},
+		{`{"bool":null,"int":null,"float":null,"string":null}`, flexModel{}},
+		{`{"bool":0,"int":"","float":"","string":" padded "}`, flexModel{false, 0, 0, " padded "}},
+	}
+
+	for _, tt := range tests {
+		var got flexModel
+
+		if err := json.Unmarshal([]byte(tt.body), &got); err != nil {
+			t.Errorf("%s: %v", tt.body, err)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.body, got, tt.want)
+		}
+	}
+
+	for _, body := range []string{`{"bool":"maybe"}`, `{"int":"abc"}`, `{"float":[1]}`} {
+		var got flexModel
+
+		if err := json.Unmarshal([]byte(body), &got); err == nil {
+			t.Errorf("%s: expected an error", body)
+		}
+	}
+}
+
+func TestFlexXML(t *testing.T) {
+	type flexModel struct {
+		Bool    FlexBool   `xml:"bool,attr"`
+		Int     FlexInt    `xml:"int,attr"`
+		Float   FlexFloat  `xml:"float,attr"`
+		String  FlexString `xml:"string,attr"`
+		Element FlexInt    `xml:"element"`
+	}
+
+	var got flexModel
+
+	body := `<Container bool="1" int="7" float="0.5" string="x"><element> 3 </element></Container>`
+
+	if err := xml.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := flexModel{true, 7, 0.5, "x", 3}
+
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
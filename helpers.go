@@ -2,6 +2,19 @@ package plex
 
 import "errors"
 
+// Agents and scanners of the libraries created by LibraryParamsFromMediaType
+const (
+	AgentPlexMovie        = "tv.plex.agents.movie"
+	AgentPlexSeries       = "tv.plex.agents.series"
+	AgentPlexMusic        = "tv.plex.agents.music"
+	AgentNone             = "com.plexapp.agents.none"
+	ScannerPlexMovie      = "Plex Movie"
+	ScannerPlexTVSeries   = "Plex TV Series"
+	ScannerPlexMusic      = "Plex Music"
+	ScannerPlexPhoto      = "Plex Photo Scanner"
+	ScannerPlexVideoFiles = "Plex Video Files Scanner"
+)
+
 // GetMediaTypeID returns plex's media type id
 func GetMediaTypeID(mediaType string) string {
 	switch mediaType {
@@ -53,7 +66,8 @@ func GetMediaType(info MediaMetadata) string {
 	return ""
 }
 
-// LibraryParamsFromMediaType is a helper for CreateLibraryParams
+// LibraryParamsFromMediaType is a helper for CreateLibraryParams. It uses the
+// Plex Movie, Plex TV Series and Plex Music agents with their scanners
 func LibraryParamsFromMediaType(mediaType string) (CreateLibraryParams, error) {
 	var params CreateLibraryParams
 
@@ -61,28 +75,28 @@ func LibraryParamsFromMediaType(mediaType string) (CreateLibraryParams, error) {
 
 	switch mediaType {
 	case "movie":
-		params.Agent = "com.plexapp.agents.imdb"
-		params.Scanner = "Plex Movie Scanner"
+		params.Agent = AgentPlexMovie
+		params.Scanner = ScannerPlexMovie
 
 		return params, nil
 	case "show":
-		params.Agent = "com.plexapp.agents.thetvdb"
-		params.Scanner = "Plex Series Scanner"
+		params.Agent = AgentPlexSeries
+		params.Scanner = ScannerPlexTVSeries
 
 		return params, nil
 	case "music":
-		params.Agent = "com.plexapp.agents.lastfm"
-		params.Scanner = "Plex Music Scanner"
+		params.Agent = AgentPlexMusic
+		params.Scanner = ScannerPlexMusic
 
 		return params, nil
 	case "photo":
-		params.Agent = "com.plexapp.agents.none"
-		params.Scanner = "Plex Photo Scanner"
+		params.Agent = AgentNone
+		params.Scanner = ScannerPlexPhoto
 
 		return params, nil
 	case "homevideo":
-		params.Agent = "com.plexapp.agents.none"
-		params.Scanner = "Plex Video Files Scanner"
+		params.Agent = AgentNone
+		params.Scanner = ScannerPlexVideoFiles
 
 		return params, nil
 	default:
@@ -0,0 +1,136 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// HistoryEntry is a single play of an item
+type HistoryEntry struct {
+	HistoryKey            string    `json:"historyKey" xml:"historyKey,attr"`
+	Key                   string    `json:"key" xml:"key,attr"`
+	RatingKey             string    `json:"ratingKey" xml:"ratingKey,attr"`
+	ParentKey             string    `json:"parentKey" xml:"parentKey,attr"`
+	GrandparentKey        string    `json:"grandparentKey" xml:"grandparentKey,attr"`
+	Title                 string    `json:"title" xml:"title,attr"`
+	ParentTitle           string    `json:"parentTitle" xml:"parentTitle,attr"`
+	GrandparentTitle      string    `json:"grandparentTitle" xml:"grandparentTitle,attr"`
+	Type                  string    `json:"type" xml:"type,attr"`
+	Thumb                 string    `json:"thumb" xml:"thumb,attr"`
+	Index                 FlexInt   `json:"index" xml:"index,attr"`
+	ParentIndex           FlexInt   `json:"parentIndex" xml:"parentIndex,attr"`
+	OriginallyAvailableAt string    `json:"originallyAvailableAt" xml:"originallyAvailableAt,attr"`
+	LibrarySectionID      FlexInt   `json:"librarySectionID" xml:"librarySectionID,attr"`
+	ViewedAt              Timestamp `json:"viewedAt" xml:"viewedAt,attr"`
+	// AccountID is the server account that played the item, 1 being the owner
+	AccountID FlexInt `json:"accountID" xml:"accountID,attr"`
+	// DeviceID is the server id of the device the item was played on
+	DeviceID FlexInt `json:"deviceID" xml:"deviceID,attr"`
+}
+
+// History is the response of GetHistory
+type History struct {
+	MediaContainer struct {
+		Metadata []HistoryEntry `json:"Metadata" xml:",any"`
+		Size     FlexInt        `json:"size" xml:"size,attr"`
+		// TotalSize and Offset are set when paging, see HistoryParams
+		TotalSize FlexInt `json:"totalSize" xml:"totalSize,attr"`
+		Offset    FlexInt `json:"offset" xml:"offset,attr"`
+	} `json:"MediaContainer" xml:"MediaContainer"`
+}
+
+// HistoryParams filter the watch history. The zero value returns the whole history, newest first
+type HistoryParams struct {
+	AccountID  string
+	SectionKey string
+	RatingKey  string
+	// ViewedAfter and ViewedBefore limit the history to a date range when they are not zero
+	ViewedAfter  time.Time
+	ViewedBefore time.Time
+	// Start and Size return a single page of the history when Size is not 0
+	Start int
+	Size  int
+	// Ascending lists the oldest plays first
+	Ascending bool
+}
+
+// encode returns the query string of the params
+func (params HistoryParams) encode() string {
+	vals := url.Values{}
+
+	if params.AccountID != "" {
+		vals.Set("accountID", params.AccountID)
+	}
+
+	if params.SectionKey != "" {
+		vals.Set("librarySectionID", params.SectionKey)
+	}
+
+	if params.RatingKey != "" {
+		vals.Set("metadataItemID", params.RatingKey)
+	}
+
+	vals.Set("sort", "viewedAt:desc")
+
+	if params.Ascending {
+		vals.Set("sort", "viewedAt")
+	}
+
+	query := vals.Encode()
+
+	// the comparison is part of the key, plex reads viewedAt>=1600000000 as the key "viewedAt>"
+	if !params.ViewedAfter.IsZero() {
+		query += "&viewedAt>=" + strconv.FormatInt(params.ViewedAfter.Unix(), 10)
+	}
+
+	if !params.ViewedBefore.IsZero() {
+		query += "&viewedAt<=" + strconv.FormatInt(params.ViewedBefore.Unix(), 10)
+	}
+
+	return query
+}
+
+// GetHistory returns the plays recorded by the server that match params
+func (p *Plex) GetHistory(params HistoryParams) (History, error) {
+	return p.GetHistoryCtx(context.Background(), params)
+}
+
+// GetHistoryCtx is like GetHistory but carries ctx for cancellation and deadlines
+func (p *Plex) GetHistoryCtx(ctx context.Context, params HistoryParams) (History, error) {
+	if params.Start < 0 || params.Size < 0 {
+		return History{}, fmt.Errorf(ErrorCommon, "start and size must not be negative")
+	}
+
+	query := p.URL + "/status/sessions/history/all?" + params.encode()
+
+	var resp *http.Response
+	var err error
+
+	if params.Size > 0 {
+		resp, err = p.getPage(ctx, query, params.Start, params.Size)
+	} else {
+		resp, err = p.get(ctx, query, p.Headers)
+	}
+
+	if err != nil {
+		return History{}, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return History{}, newAPIError(resp)
+	}
+
+	var history History
+
+	if err := decodeResponse(resp, &history); err != nil {
+		return History{}, err
+	}
+
+	return history, nil
+}
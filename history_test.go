@@ -0,0 +1,95 @@
+package plex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func historyKeys(history History) []string {
+	var keys []string
+
+	for _, entry := range history.MediaContainer.Metadata {
+		keys = append(keys, entry.HistoryKey)
+	}
+
+	return keys
+}
+
+func TestGetHistory(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	history, err := conn.GetHistory(HistoryParams{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := history.MediaContainer.Metadata
+
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 history entries, got %d", len(entries))
+	}
+
+	// newest first by default
+	latest := entries[0]
+
+	if latest.Title != "Blade Runner" || latest.AccountID.Int() != 1 || latest.DeviceID.Int() != 1 || latest.ViewedAt.Unix() != 1600300000 {
+		t.Errorf("unexpected latest entry %+v", latest)
+	}
+
+	episode := entries[1]
+
+	if episode.GrandparentTitle != "The Expanse" || episode.ParentTitle != "Season 1" || episode.LibrarySectionID.Int() != 2 || episode.AccountID.Int() != 2 {
+		t.Errorf("unexpected episode entry %+v", episode)
+	}
+
+	tests := []struct {
+		name   string
+		params HistoryParams
+		keys   []string
+	}{
+		{"account", HistoryParams{AccountID: "2"}, []string{"/status/sessions/history/2"}},
+		{"section", HistoryParams{SectionKey: "1", Ascending: true}, []string{"/status/sessions/history/1", "/status/sessions/history/3"}},
+		{"rating key", HistoryParams{RatingKey: "102"}, []string{"/status/sessions/history/3", "/status/sessions/history/1"}},
+		{"date range", HistoryParams{ViewedAfter: time.Unix(1600150000, 0), ViewedBefore: time.Unix(1600250000, 0)}, []string{"/status/sessions/history/2"}},
+		{"page", HistoryParams{Start: 1, Size: 1}, []string{"/status/sessions/history/2"}},
+	}
+
+	for _, test := range tests {
+		history, err := conn.GetHistory(test.params)
+
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		keys := historyKeys(history)
+
+		if len(keys) != len(test.keys) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.keys, keys)
+			continue
+		}
+
+		for ii := range keys {
+			if keys[ii] != test.keys[ii] {
+				t.Errorf("%s: expected %v, got %v", test.name, test.keys, keys)
+				break
+			}
+		}
+	}
+
+	page, err := conn.GetHistory(HistoryParams{Size: 2})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if page.MediaContainer.TotalSize.Int() != 3 || page.MediaContainer.Size.Int() != 2 {
+		t.Errorf("unexpected page sizes %d of %d", page.MediaContainer.Size.Int(), page.MediaContainer.TotalSize.Int())
+	}
+
+	if _, err := conn.GetHistory(HistoryParams{Start: -1}); err == nil {
+		t.Error("expected an error for a negative start")
+	}
+}
@@ -0,0 +1,152 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Hub is a group of results of a search or a row of the home screen, like
+// Recently Added or Continue Watching. Type tells what it holds: movie, show, episode
+// and so on for media, actor, director, genre and other tag types for tags
+type Hub struct {
+	HubIdentifier string   `json:"hubIdentifier" xml:"hubIdentifier,attr"`
+	Title         string   `json:"title" xml:"title,attr"`
+	Type          string   `json:"type" xml:"type,attr"`
+	Context       string   `json:"context" xml:"context,attr"`
+	Key           string   `json:"key" xml:"key,attr"`
+	HubKey        string   `json:"hubKey" xml:"hubKey,attr"`
+	Size          FlexInt  `json:"size" xml:"size,attr"`
+	More          FlexBool `json:"more" xml:"more,attr"`
+	Promoted      FlexBool `json:"promoted" xml:"promoted,attr"`
+	Style         string   `json:"style" xml:"style,attr"`
+	// Metadata holds the media of the hub. In xml it holds the tags too
+	Metadata []HubItem `json:"Metadata" xml:",any"`
+	// Directory holds the tags of tag hubs in json
+	Directory []HubItem `json:"Directory" xml:"-"`
+}
+
+// Items returns the media and tags of the hub
+func (h Hub) Items() []HubItem {
+	return append(append([]HubItem{}, h.Metadata...), h.Directory...)
+}
+
+// HubItem is an entry of a hub. Tags, like an actor or a genre, only set the tag fields
+type HubItem struct {
+	Metadata
+	Tag     string  `json:"tag" xml:"tag,attr"`
+	TagType FlexInt `json:"tagType" xml:"tagType,attr"`
+	// Count is the number of items with the tag
+	Count FlexInt `json:"count" xml:"count,attr"`
+}
+
+type hubsResponse struct {
+	MediaContainer struct {
+		Hub []Hub `json:"Hub" xml:"Hub"`
+	} `json:"MediaContainer" xml:"MediaContainer"`
+}
+
+// SearchHubsParams narrow down a hub search
+type SearchHubsParams struct {
+	Query string
+	// SectionKey only searches the library with this key
+	SectionKey string
+	// Limit is the maximum number of results of each hub. 0 uses the server default
+	Limit int
+	// Types only keeps the hubs of these types, i.e. movie, show, episode, actor or genre
+	Types []string
+}
+
+// SearchHubs searches the server for media, people and tags. Results are grouped in hubs by type
+func (p *Plex) SearchHubs(params SearchHubsParams) ([]Hub, error) {
+	return p.SearchHubsCtx(context.Background(), params)
+}
+
+// SearchHubsCtx is like SearchHubs but carries ctx for cancellation and deadlines
+func (p *Plex) SearchHubsCtx(ctx context.Context, params SearchHubsParams) ([]Hub, error) {
+	if params.Query == "" {
+		return nil, fmt.Errorf(ErrorCommon, "a query is required")
+	}
+
+	vals := url.Values{}
+
+	vals.Set("query", params.Query)
+
+	if params.SectionKey != "" {
+		vals.Set("sectionId", params.SectionKey)
+	}
+
+	if params.Limit > 0 {
+		vals.Set("limit", strconv.Itoa(params.Limit))
+	}
+
+	hubs, err := p.getHubs(ctx, "/hubs/search?"+vals.Encode())
+
+	if err != nil || len(params.Types) == 0 {
+		return hubs, err
+	}
+
+	types := make(map[string]bool, len(params.Types))
+
+	for _, t := range params.Types {
+		types[t] = true
+	}
+
+	var filtered []Hub
+
+	for _, hub := range hubs {
+		if types[hub.Type] {
+			filtered = append(filtered, hub)
+		}
+	}
+
+	return filtered, nil
+}
+
+// GetHubs returns the hubs of the home screen, like Continue Watching and the recently added media of every library
+func (p *Plex) GetHubs() ([]Hub, error) {
+	return p.GetHubsCtx(context.Background())
+}
+
+// GetHubsCtx is like GetHubs but carries ctx for cancellation and deadlines
+func (p *Plex) GetHubsCtx(ctx context.Context) ([]Hub, error) {
+	return p.getHubs(ctx, "/hubs")
+}
+
+// GetSectionHubs returns the hubs of a library, like its recently added and in progress media
+func (p *Plex) GetSectionHubs(sectionKey string) ([]Hub, error) {
+	return p.GetSectionHubsCtx(context.Background(), sectionKey)
+}
+
+// GetSectionHubsCtx is like GetSectionHubs but carries ctx for cancellation and deadlines
+func (p *Plex) GetSectionHubsCtx(ctx context.Context, sectionKey string) ([]Hub, error) {
+	if sectionKey == "" {
+		return nil, fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.getHubs(ctx, "/hubs/sections/"+sectionKey)
+}
+
+func (p *Plex) getHubs(ctx context.Context, path string) ([]Hub, error) {
+	resp, err := p.get(ctx, p.URL+path, p.Headers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var result hubsResponse
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.MediaContainer.Hub, nil
+}
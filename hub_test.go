@@ -0,0 +1,79 @@
+package plex
+
+import (
+	"testing"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func TestSearchHubs(t *testing.T) {
+	fixtures := plextest.DefaultFixtures()
+	fixtures.Items[0].Directors = []string{"Ridley Scott"}
+	fixtures.Items[2].Directors = []string{"Ridley Scott"}
+
+	conn, server := newTestConn(t, fixtures)
+
+	hubs, err := conn.SearchHubs(SearchHubsParams{Query: "alien", Limit: 1})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hubs) != 1 || hubs[0].Type != "movie" || len(hubs[0].Items()) != 1 || !hubs[0].More.Bool() {
+		t.Fatalf("expected a movie hub limited to 1 item, got %+v", hubs)
+	}
+
+	requests := server.Requests()
+
+	if last := requests[len(requests)-1]; last.Query.Get("limit") != "1" || last.Query.Get("query") != "alien" {
+		t.Errorf("unexpected search request %+v", last)
+	}
+
+	hubs, err = conn.SearchHubs(SearchHubsParams{Query: "ridley", Types: []string{"director"}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hubs) != 1 || hubs[0].Items()[0].Tag != "Ridley Scott" || hubs[0].Items()[0].Count.Int() != 2 {
+		t.Errorf("expected a director hub, got %+v", hubs)
+	}
+
+	hubs, err = conn.SearchHubs(SearchHubsParams{Query: "the", SectionKey: "2", Types: []string{"show", "episode"}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hubs) != 2 || hubs[0].Items()[0].Title != "The Expanse" || hubs[1].Items()[0].Title != "The Big Empty" {
+		t.Errorf("unexpected hubs of library 2 %+v", hubs)
+	}
+
+	if _, err := conn.SearchHubs(SearchHubsParams{}); err == nil {
+		t.Error("expected an error without a query")
+	}
+}
+
+func TestHubs(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	hubs, err := conn.GetHubs()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hubs) != 3 || hubs[0].HubIdentifier != "home.continue" || hubs[0].Items()[0].RatingKey != "101" {
+		t.Fatalf("unexpected home hubs %+v", hubs)
+	}
+
+	hubs, err = conn.GetSectionHubs("1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hubs) != 2 || hubs[1].Title != "Recently Added" || hubs[1].Items()[0].Title != "Blade Runner" {
+		t.Errorf("unexpected library hubs %+v", hubs)
+	}
+}
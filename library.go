@@ -0,0 +1,206 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// UpdateLibraryParams change a library. Empty fields keep their current value
+type UpdateLibraryParams struct {
+	Name     string
+	Agent    string
+	Language string
+	// Locations replaces the folders of the library, see AddLibraryLocations to add a few
+	Locations []string
+	// Prefs are advanced settings of the library by id, see GetLibraryPreferences
+	Prefs map[string]string
+}
+
+// addLibraryPrefs adds prefs to vals the way the library endpoints expect them, i.e. prefs[showOrdering]=aired
+func addLibraryPrefs(vals url.Values, prefs map[string]string) {
+	for id, value := range prefs {
+		vals.Set("prefs["+id+"]", value)
+	}
+}
+
+// getLibrary returns the library with sectionKey
+func (p *Plex) getLibrary(ctx context.Context, sectionKey string) (Directory, error) {
+	if sectionKey == "" {
+		return Directory{}, fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	libraries, err := p.GetLibrariesCtx(ctx)
+
+	if err != nil {
+		return Directory{}, err
+	}
+
+	for _, library := range libraries.MediaContainer.Directory {
+		if library.Key == sectionKey {
+			return library, nil
+		}
+	}
+
+	return Directory{}, fmt.Errorf("plex: library %s: %w", sectionKey, ErrNotFound)
+}
+
+// UpdateLibrary changes the name, agent, language, folders or advanced settings of a library
+func (p *Plex) UpdateLibrary(sectionKey string, params UpdateLibraryParams) error {
+	return p.UpdateLibraryCtx(context.Background(), sectionKey, params)
+}
+
+// UpdateLibraryCtx is like UpdateLibrary but carries ctx for cancellation and deadlines
+func (p *Plex) UpdateLibraryCtx(ctx context.Context, sectionKey string, params UpdateLibraryParams) error {
+	// the server resets what is left out, so the current values fill the gaps
+	library, err := p.getLibrary(ctx, sectionKey)
+
+	if err != nil {
+		return err
+	}
+
+	if params.Name == "" {
+		params.Name = library.Title
+	}
+
+	if params.Agent == "" {
+		params.Agent = library.Agent
+	}
+
+	if params.Language == "" {
+		params.Language = library.Language
+	}
+
+	if len(params.Locations) == 0 {
+		for _, location := range library.Location {
+			params.Locations = append(params.Locations, location.Path)
+		}
+	}
+
+	vals := url.Values{}
+
+	vals.Set("name", params.Name)
+	vals.Set("agent", params.Agent)
+	vals.Set("language", params.Language)
+
+	for _, location := range params.Locations {
+		vals.Add("location", location)
+	}
+
+	addLibraryPrefs(vals, params.Prefs)
+
+	return p.action(ctx, http.MethodPut, "/library/sections/"+sectionKey, vals)
+}
+
+// AddLibraryLocations adds folders to a library. Folders it already has are skipped
+func (p *Plex) AddLibraryLocations(sectionKey string, paths ...string) error {
+	return p.AddLibraryLocationsCtx(context.Background(), sectionKey, paths...)
+}
+
+// AddLibraryLocationsCtx is like AddLibraryLocations but carries ctx for cancellation and deadlines
+func (p *Plex) AddLibraryLocationsCtx(ctx context.Context, sectionKey string, paths ...string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf(ErrorCommon, "a path is required")
+	}
+
+	library, err := p.getLibrary(ctx, sectionKey)
+
+	if err != nil {
+		return err
+	}
+
+	var locations []string
+
+	seen := map[string]bool{}
+
+	for _, location := range library.Location {
+		locations = append(locations, location.Path)
+		seen[location.Path] = true
+	}
+
+	for _, path := range paths {
+		if !seen[path] {
+			locations = append(locations, path)
+			seen[path] = true
+		}
+	}
+
+	return p.UpdateLibraryCtx(ctx, sectionKey, UpdateLibraryParams{Locations: locations})
+}
+
+// RemoveLibraryLocations removes folders from a library. A library keeps at least one folder
+func (p *Plex) RemoveLibraryLocations(sectionKey string, paths ...string) error {
+	return p.RemoveLibraryLocationsCtx(context.Background(), sectionKey, paths...)
+}
+
+// RemoveLibraryLocationsCtx is like RemoveLibraryLocations but carries ctx for cancellation and deadlines
+func (p *Plex) RemoveLibraryLocationsCtx(ctx context.Context, sectionKey string, paths ...string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf(ErrorCommon, "a path is required")
+	}
+
+	library, err := p.getLibrary(ctx, sectionKey)
+
+	if err != nil {
+		return err
+	}
+
+	current := map[string]bool{}
+
+	for _, location := range library.Location {
+		current[location.Path] = true
+	}
+
+	removed := map[string]bool{}
+
+	for _, path := range paths {
+		if !current[path] {
+			return fmt.Errorf("plex: %s is not a folder of library %s", path, sectionKey)
+		}
+
+		removed[path] = true
+	}
+
+	var locations []string
+
+	for _, location := range library.Location {
+		if !removed[location.Path] {
+			locations = append(locations, location.Path)
+		}
+	}
+
+	if len(locations) == 0 {
+		return fmt.Errorf(ErrorCommon, "a library needs at least one folder")
+	}
+
+	return p.UpdateLibraryCtx(ctx, sectionKey, UpdateLibraryParams{Locations: locations})
+}
+
+// GetLibraryPreferences returns the advanced settings of a library, which depend on its type and agent
+func (p *Plex) GetLibraryPreferences(sectionKey string) (Preferences, error) {
+	return p.GetLibraryPreferencesCtx(context.Background(), sectionKey)
+}
+
+// GetLibraryPreferencesCtx is like GetLibraryPreferences but carries ctx for cancellation and deadlines
+func (p *Plex) GetLibraryPreferencesCtx(ctx context.Context, sectionKey string) (Preferences, error) {
+	if sectionKey == "" {
+		return nil, fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.getPreferences(ctx, "/library/sections/"+sectionKey+"/prefs")
+}
+
+// SetLibraryPreferences changes advanced settings of a library by id. Settings missing from values are left alone
+func (p *Plex) SetLibraryPreferences(sectionKey string, values map[string]string) error {
+	return p.SetLibraryPreferencesCtx(context.Background(), sectionKey, values)
+}
+
+// SetLibraryPreferencesCtx is like SetLibraryPreferences but carries ctx for cancellation and deadlines
+func (p *Plex) SetLibraryPreferencesCtx(ctx context.Context, sectionKey string, values map[string]string) error {
+	if sectionKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.setPreferences(ctx, "/library/sections/"+sectionKey+"/prefs", values)
+}
@@ -0,0 +1,432 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FilterOperator compares a library field to a value. What an operator means depends on
+// the type of the field, i.e. OpEquals is "is" for integers but "contains" for strings
+type FilterOperator string
+
+// Filter operators understood by Plex Media Server
+const (
+	// OpEquals is "is", or "contains" for string fields
+	OpEquals FilterOperator = "="
+	// OpNotEquals is "is not", or "does not contain" for string fields
+	OpNotEquals FilterOperator = "!="
+	// OpIs matches a string field exactly
+	OpIs FilterOperator = "=="
+	// OpIsNot excludes an exact string match
+	OpIsNot FilterOperator = "!=="
+	// OpBeginsWith matches the start of a string field
+	OpBeginsWith FilterOperator = "<="
+	// OpEndsWith matches the end of a string field
+	OpEndsWith FilterOperator = ">="
+	// OpGreaterThan is "greater than", or "after" for date fields
+	OpGreaterThan FilterOperator = ">>="
+	// OpLessThan is "less than", or "before" for date fields
+	OpLessThan FilterOperator = "<<="
+)
+
+// operators allowed for each filterType reported by /library/sections/{key}/filters
+var filterTypeOperators = map[string][]FilterOperator{
+	"boolean": {OpEquals, OpNotEquals},
+	"tag":     {OpEquals, OpNotEquals},
+	"integer": {OpEquals, OpNotEquals, OpGreaterThan, OpLessThan},
+	"date":    {OpEquals, OpNotEquals, OpGreaterThan, OpLessThan},
+	"string":  {OpEquals, OpNotEquals, OpIs, OpIsNot, OpBeginsWith, OpEndsWith},
+}
+
+type libraryFilter struct {
+	field    string
+	operator FilterOperator
+	value    string
+}
+
+type librarySort struct {
+	field      string
+	descending bool
+}
+
+// LibraryQuery builds the query of a library listing. The zero value, like a nil
+// *LibraryQuery, lists everything
+//
+//	query := plex.NewLibraryQuery().
+//		Type("movie").
+//		Where("year", plex.OpGreaterThan, "2000").
+//		Genre("Horror").
+//		Unwatched().
+//		Sort("addedAt", true).
+//		Limit(10)
+//
+//	results, err := plexConn.QueryLibrary("1", query)
+type LibraryQuery struct {
+	mediaType string
+	filters   []libraryFilter
+	sorts     []librarySort
+	limit     int
+}
+
+// NewLibraryQuery returns an empty LibraryQuery
+func NewLibraryQuery() *LibraryQuery {
+	return &LibraryQuery{}
+}
+
+// Type selects the media type to list, i.e. "episode" to list the episodes of a show library
+// instead of the shows. Both names and ids are accepted, see GetMediaTypeID
+func (q *LibraryQuery) Type(mediaType string) *LibraryQuery {
+	q.mediaType = GetMediaTypeID(mediaType)
+
+	return q
+}
+
+// Where adds a filter on field. Several filters are combined with "and"
+func (q *LibraryQuery) Where(field string, operator FilterOperator, value string) *LibraryQuery {
+	q.filters = append(q.filters, libraryFilter{field: field, operator: operator, value: value})
+
+	return q
+}
+
+// Label only lists items with the label, by tag id or title
+func (q *LibraryQuery) Label(label string) *LibraryQuery {
+	return q.Where("label", OpEquals, label)
+}
+
+// Genre only lists items of the genre, by tag id or title
+func (q *LibraryQuery) Genre(genre string) *LibraryQuery {
+	return q.Where("genre", OpEquals, genre)
+}
+
+// Collection only lists items in the collection, by tag id or title
+func (q *LibraryQuery) Collection(collection string) *LibraryQuery {
+	return q.Where("collection", OpEquals, collection)
+}
+
+// Unwatched only lists items that have not been watched
+func (q *LibraryQuery) Unwatched() *LibraryQuery {
+	return q.Where("unwatched", OpEquals, "1")
+}
+
+// InProgress only lists items that have been partially watched
+func (q *LibraryQuery) InProgress() *LibraryQuery {
+	return q.Where("inProgress", OpEquals, "1")
+}
+
+// Sort orders the results by field. Calling Sort again adds a secondary order
+func (q *LibraryQuery) Sort(field string, descending bool) *LibraryQuery {
+	q.sorts = append(q.sorts, librarySort{field: field, descending: descending})
+
+	return q
+}
+
+// Limit caps the number of items returned. 0 returns everything
+func (q *LibraryQuery) Limit(limit int) *LibraryQuery {
+	q.limit = limit
+
+	return q
+}
+
+// Encode returns the query string, i.e. "type=1&year>>=2000&sort=addedAt:desc".
+// Limit is sent as a container size header and is not part of it
+func (q *LibraryQuery) Encode() string {
+	if q == nil {
+		return ""
+	}
+
+	var params []string
+
+	if q.mediaType != "" {
+		params = append(params, "type="+url.QueryEscape(q.mediaType))
+	}
+
+	for _, f := range q.filters {
+		// the operator is part of the key, plex reads year>>=2000 as the key "year>>"
+		params = append(params, url.QueryEscape(f.field)+string(f.operator)+url.QueryEscape(f.value))
+	}
+
+	if len(q.sorts) > 0 {
+		sorts := make([]string, len(q.sorts))
+
+		for ii, s := range q.sorts {
+			sorts[ii] = s.field
+
+			if s.descending {
+				sorts[ii] += ":desc"
+			}
+		}
+
+		params = append(params, "sort="+url.QueryEscape(strings.Join(sorts, ",")))
+	}
+
+	return strings.Join(params, "&")
+}
+
+// LibraryFilter is a field a library can be filtered by
+type LibraryFilter struct {
+	Filter     string `json:"filter" xml:"filter,attr"`
+	FilterType string `json:"filterType" xml:"filterType,attr"`
+	Key        string `json:"key" xml:"key,attr"`
+	Title      string `json:"title" xml:"title,attr"`
+	Type       string `json:"type" xml:"type,attr"`
+}
+
+// LibraryFilters is the response of /library/sections/{key}/filters
+type LibraryFilters struct {
+	MediaContainer struct {
+		Directory []LibraryFilter `json:"Directory" xml:"Directory"`
+	} `json:"MediaContainer" xml:"MediaContainer"`
+}
+
+// LibrarySort is a field a library can be sorted by
+type LibrarySort struct {
+	DefaultDirection string `json:"defaultDirection" xml:"defaultDirection,attr"`
+	DescKey          string `json:"descKey" xml:"descKey,attr"`
+	Key              string `json:"key" xml:"key,attr"`
+	Title            string `json:"title" xml:"title,attr"`
+}
+
+// LibrarySorts is the response of /library/sections/{key}/sorts
+type LibrarySorts struct {
+	MediaContainer struct {
+		Directory []LibrarySort `json:"Directory" xml:"Directory"`
+	} `json:"MediaContainer" xml:"MediaContainer"`
+}
+
+// LibraryQueryError is returned when a LibraryQuery uses a field or operator the library does not support
+type LibraryQueryError struct {
+	// Field is the filter or sort field that was rejected
+	Field string
+	// Reason describes why the field was rejected
+	Reason string
+	// Allowed lists the fields or operators the library accepts instead
+	Allowed []string
+}
+
+// Error implements the error interface
+func (e *LibraryQueryError) Error() string {
+	return fmt.Sprintf("invalid library query %q: %s (allowed: %s)", e.Field, e.Reason, strings.Join(e.Allowed, ", "))
+}
+
+// GetLibraryFilters lists the fields a library can be filtered by. mediaType is optional
+// and defaults to the type of the library
+func (p *Plex) GetLibraryFilters(sectionKey, mediaType string) (LibraryFilters, error) {
+	return p.GetLibraryFiltersCtx(context.Background(), sectionKey, mediaType)
+}
+
+// GetLibraryFiltersCtx is like GetLibraryFilters but carries ctx for cancellation and deadlines
+func (p *Plex) GetLibraryFiltersCtx(ctx context.Context, sectionKey, mediaType string) (LibraryFilters, error) {
+	var result LibraryFilters
+
+	if err := p.getSectionDirectory(ctx, sectionKey, "filters", mediaType, &result); err != nil {
+		return LibraryFilters{}, err
+	}
+
+	return result, nil
+}
+
+// GetLibrarySorts lists the fields a library can be sorted by. mediaType is optional
+// and defaults to the type of the library
+func (p *Plex) GetLibrarySorts(sectionKey, mediaType string) (LibrarySorts, error) {
+	return p.GetLibrarySortsCtx(context.Background(), sectionKey, mediaType)
+}
+
+// GetLibrarySortsCtx is like GetLibrarySorts but carries ctx for cancellation and deadlines
+func (p *Plex) GetLibrarySortsCtx(ctx context.Context, sectionKey, mediaType string) (LibrarySorts, error) {
+	var result LibrarySorts
+
+	if err := p.getSectionDirectory(ctx, sectionKey, "sorts", mediaType, &result); err != nil {
+		return LibrarySorts{}, err
+	}
+
+	return result, nil
+}
+
+func (p *Plex) getSectionDirectory(ctx context.Context, sectionKey, directory, mediaType string, v interface{}) error {
+	query := fmt.Sprintf("%s/library/sections/%s/%s", p.URL, sectionKey, directory)
+
+	if mediaType != "" {
+		query += "?type=" + url.QueryEscape(GetMediaTypeID(mediaType))
+	}
+
+	resp, err := p.get(ctx, query, p.Headers)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+
+	return decodeResponse(resp, v)
+}
+
+// ValidateLibraryQuery checks the filters and sorts of q against the ones the library supports.
+// It returns a *LibraryQueryError for the first field or operator that is not supported
+func (p *Plex) ValidateLibraryQuery(sectionKey string, q *LibraryQuery) error {
+	return p.ValidateLibraryQueryCtx(context.Background(), sectionKey, q)
+}
+
+// ValidateLibraryQueryCtx is like ValidateLibraryQuery but carries ctx for cancellation and deadlines
+func (p *Plex) ValidateLibraryQueryCtx(ctx context.Context, sectionKey string, q *LibraryQuery) error {
+	if q == nil {
+		return nil
+	}
+
+	if len(q.filters) > 0 {
+		filters, err := p.GetLibraryFiltersCtx(ctx, sectionKey, q.mediaType)
+
+		if err != nil {
+			return err
+		}
+
+		if err := q.validateFilters(filters.MediaContainer.Directory); err != nil {
+			return err
+		}
+	}
+
+	if len(q.sorts) > 0 {
+		sorts, err := p.GetLibrarySortsCtx(ctx, sectionKey, q.mediaType)
+
+		if err != nil {
+			return err
+		}
+
+		if err := q.validateSorts(sorts.MediaContainer.Directory); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (q *LibraryQuery) validateFilters(filters []LibraryFilter) error {
+	filterTypes := make(map[string]string, len(filters))
+	fields := make([]string, 0, len(filters))
+
+	for _, f := range filters {
+		filterTypes[f.Filter] = f.FilterType
+		fields = append(fields, f.Filter)
+	}
+
+	sort.Strings(fields)
+
+	for _, f := range q.filters {
+		filterType, ok := filterTypes[f.field]
+
+		if !ok {
+			return &LibraryQueryError{Field: f.field, Reason: "the library cannot be filtered by this field", Allowed: fields}
+		}
+
+		operators, known := filterTypeOperators[filterType]
+
+		// let the server decide on filter types this client does not know about
+		if !known {
+			continue
+		}
+
+		allowed := make([]string, len(operators))
+		valid := false
+
+		for ii, op := range operators {
+			allowed[ii] = string(op)
+
+			if op == f.operator {
+				valid = true
+			}
+		}
+
+		if !valid {
+			return &LibraryQueryError{
+				Field:   f.field,
+				Reason:  fmt.Sprintf("operator %q is not supported by %s fields", f.operator, filterType),
+				Allowed: allowed,
+			}
+		}
+
+		if filterType == "boolean" && f.value != "0" && f.value != "1" {
+			return &LibraryQueryError{Field: f.field, Reason: fmt.Sprintf("%q is not a boolean", f.value), Allowed: []string{"0", "1"}}
+		}
+
+		if filterType == "integer" {
+			if _, err := strconv.Atoi(f.value); err != nil {
+				return &LibraryQueryError{Field: f.field, Reason: fmt.Sprintf("%q is not an integer", f.value)}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (q *LibraryQuery) validateSorts(sorts []LibrarySort) error {
+	known := make(map[string]bool, len(sorts))
+	fields := make([]string, 0, len(sorts))
+
+	for _, s := range sorts {
+		known[s.Key] = true
+		fields = append(fields, s.Key)
+	}
+
+	sort.Strings(fields)
+
+	for _, s := range q.sorts {
+		if !known[s.field] {
+			return &LibraryQueryError{Field: s.field, Reason: "the library cannot be sorted by this field", Allowed: fields}
+		}
+	}
+
+	return nil
+}
+
+// QueryLibrary validates q against the filters and sorts of the library and lists the
+// matching content. See ValidateLibraryQuery
+func (p *Plex) QueryLibrary(sectionKey string, q *LibraryQuery) (SearchResults, error) {
+	return p.QueryLibraryCtx(context.Background(), sectionKey, q)
+}
+
+// QueryLibraryCtx is like QueryLibrary but carries ctx for cancellation and deadlines
+func (p *Plex) QueryLibraryCtx(ctx context.Context, sectionKey string, q *LibraryQuery) (SearchResults, error) {
+	if q == nil {
+		q = &LibraryQuery{}
+	}
+
+	if err := p.ValidateLibraryQueryCtx(ctx, sectionKey, q); err != nil {
+		return SearchResults{}, err
+	}
+
+	filter := q.filter()
+
+	if q.limit > 0 {
+		return p.GetLibraryContentPageCtx(ctx, sectionKey, filter, 0, q.limit)
+	}
+
+	return p.GetLibraryContentCtx(ctx, sectionKey, filter)
+}
+
+// IterateLibraryQuery is like IterateLibraryContent but lists the content matching q.
+// q is not validated and its limit is ignored
+func (p *Plex) IterateLibraryQuery(sectionKey string, q *LibraryQuery, pageSize int) *LibraryContentIterator {
+	return p.IterateLibraryQueryCtx(context.Background(), sectionKey, q, pageSize)
+}
+
+// IterateLibraryQueryCtx is like IterateLibraryQuery but carries ctx for cancellation and deadlines
+func (p *Plex) IterateLibraryQueryCtx(ctx context.Context, sectionKey string, q *LibraryQuery, pageSize int) *LibraryContentIterator {
+	return p.IterateLibraryContentCtx(ctx, sectionKey, q.filter(), pageSize)
+}
+
+// filter returns the query in the form GetLibraryContent expects
+func (q *LibraryQuery) filter() string {
+	if encoded := q.Encode(); encoded != "" {
+		return "?" + encoded
+	}
+
+	return ""
+}
@@ -0,0 +1,133 @@
+package plex
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func TestLibraryQueryEncode(t *testing.T) {
+	query := NewLibraryQuery().
+		Type("movie").
+		Where("year", OpGreaterThan, "2000").
+		Where("title", OpIs, "Alien & Co").
+		Unwatched().
+		Sort("addedAt", true).
+		Sort("titleSort", false).
+		Limit(5)
+
+	expected := "type=1&year>>=2000&title==Alien+%26+Co&unwatched=1&sort=addedAt%3Adesc%2CtitleSort"
+
+	if encoded := query.Encode(); encoded != expected {
+		t.Errorf("expected %s, got %s", expected, encoded)
+	}
+
+	if encoded := NewLibraryQuery().Encode(); encoded != "" {
+		t.Errorf("expected an empty query, got %s", encoded)
+	}
+}
+
+func TestQueryLibrary(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	results, err := conn.QueryLibrary("1", NewLibraryQuery().
+		Type("movie").
+		Where("year", OpGreaterThan, "1980").
+		Sort("year", true))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var titles []string
+
+	for _, item := range results.MediaContainer.Metadata {
+		titles = append(titles, item.Title)
+	}
+
+	if strings.Join(titles, ",") != "Aliens,Blade Runner" {
+		t.Errorf("unexpected results %v", titles)
+	}
+
+	results, err = conn.QueryLibrary("1", NewLibraryQuery().Unwatched().Sort("addedAt", true).Limit(1))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results.MediaContainer.Metadata) != 1 || results.MediaContainer.Metadata[0].Title != "Aliens" {
+		t.Errorf("expected only Aliens, got %+v", results.MediaContainer.Metadata)
+	}
+}
+
+func TestQueryLibraryValidation(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	tests := []struct {
+		name  string
+		query *LibraryQuery
+		field string
+	}{
+		{"unknown filter", NewLibraryQuery().Where("mood", OpEquals, "happy"), "mood"},
+		{"operator", NewLibraryQuery().Where("year", OpBeginsWith, "19"), "year"},
+		{"integer value", NewLibraryQuery().Where("year", OpEquals, "last year"), "year"},
+		{"unknown sort", NewLibraryQuery().Sort("rating", true), "rating"},
+	}
+
+	for _, test := range tests {
+		_, err := conn.QueryLibrary("1", test.query)
+
+		var queryErr *LibraryQueryError
+
+		if !errors.As(err, &queryErr) || queryErr.Field != test.field {
+			t.Errorf("%s: expected a LibraryQueryError for %s, got %v", test.name, test.field, err)
+		}
+	}
+
+	for _, request := range server.Requests() {
+		if strings.HasSuffix(request.Path, "/all") {
+			t.Errorf("invalid queries should not be sent, got %s", request.Path)
+		}
+	}
+}
+
+func TestQueryLibraryNil(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	if err := conn.ValidateLibraryQuery("1", nil); err != nil {
+		t.Errorf("expected a nil query to be valid, got %v", err)
+	}
+
+	all, err := conn.GetLibraryContent("1", "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := conn.QueryLibrary("1", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results.MediaContainer.Metadata) == 0 || len(results.MediaContainer.Metadata) != len(all.MediaContainer.Metadata) {
+		t.Errorf("expected a nil query to list everything, got %d of %d", len(results.MediaContainer.Metadata), len(all.MediaContainer.Metadata))
+	}
+
+	iterator := conn.IterateLibraryQuery("1", nil, 1)
+	count := 0
+
+	for iterator.Next() {
+		count++
+	}
+
+	if err := iterator.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count != len(all.MediaContainer.Metadata) {
+		t.Errorf("expected to iterate over %d items, got %d", len(all.MediaContainer.Metadata), count)
+	}
+}
@@ -0,0 +1,134 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func libraryLocations(t *testing.T, conn *Plex, sectionKey string) string {
+	library, err := conn.getLibrary(context.Background(), sectionKey)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+
+	for _, location := range library.Location {
+		paths = append(paths, location.Path)
+	}
+
+	return strings.Join(paths, ",")
+}
+
+func TestCreateLibraryWithPrefs(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	params, err := LibraryParamsFromMediaType("show")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if params.Agent != AgentPlexSeries || params.Scanner != ScannerPlexTVSeries {
+		t.Errorf("expected the Plex TV Series agent, got %s and %s", params.Agent, params.Scanner)
+	}
+
+	params.Name = "Anime"
+	params.Locations = []string{"/data/anime", "/mnt/anime"}
+	params.Prefs = map[string]string{"showOrdering": "absolute", "enableCreditsMarkerGeneration": "0"}
+
+	if err := conn.CreateLibrary(params); err != nil {
+		t.Fatal(err)
+	}
+
+	libraries := server.Fixtures().Libraries
+	sectionKey := libraries[len(libraries)-1].Key
+
+	if locations := libraryLocations(t, conn, sectionKey); locations != "/data/anime,/mnt/anime" {
+		t.Errorf("unexpected locations %s", locations)
+	}
+
+	prefs, err := conn.GetLibraryPreferences(sectionKey)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := prefs.Values()
+
+	if values["showOrdering"] != "absolute" || values["enableCreditsMarkerGeneration"] != "0" || values["includeInGlobal"] != "true" {
+		t.Errorf("unexpected library preferences %v", values)
+	}
+
+	params.Prefs = map[string]string{"noSuchSetting": "1"}
+
+	if err := conn.CreateLibrary(params); err == nil {
+		t.Error("expected an error for an unknown library setting")
+	}
+}
+
+func TestUpdateLibrary(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	if err := conn.AddLibraryLocations("1", "/mnt/movies", "/data/movies"); err != nil {
+		t.Fatal(err)
+	}
+
+	if locations := libraryLocations(t, conn, "1"); locations != "/data/movies,/mnt/movies" {
+		t.Errorf("unexpected locations after adding %s", locations)
+	}
+
+	if err := conn.RemoveLibraryLocations("1", "/data/movies"); err != nil {
+		t.Fatal(err)
+	}
+
+	if locations := libraryLocations(t, conn, "1"); locations != "/mnt/movies" {
+		t.Errorf("unexpected locations after removing %s", locations)
+	}
+
+	if err := conn.RemoveLibraryLocations("1", "/mnt/movies"); err == nil {
+		t.Error("expected an error when removing the last folder")
+	}
+
+	if err := conn.RemoveLibraryLocations("1", "/not/a/folder"); err == nil {
+		t.Error("expected an error when removing a folder the library does not have")
+	}
+
+	// the name changes while the agent, language and folders are kept
+	if err := conn.UpdateLibrary("2", UpdateLibraryParams{Name: "Series", Prefs: map[string]string{"episodeSort": "1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	library := server.Fixtures().Libraries[1]
+
+	if library.Title != "Series" || library.Agent != AgentPlexSeries || len(library.Locations) != 1 || library.Prefs["episodeSort"] != "1" {
+		t.Errorf("unexpected library %+v", library)
+	}
+
+	if err := conn.SetLibraryPreferences("2", map[string]string{"showOrdering": "dvd"}); err != nil {
+		t.Fatal(err)
+	}
+
+	prefs, err := conn.GetLibraryPreferences("2")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changes := prefs.Diff(map[string]string{"showOrdering": "dvd", "episodeSort": "1"}); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+
+	if err := conn.SetLibraryPreferences("2", map[string]string{"showOrdering": "random"}); err == nil {
+		t.Error("expected an error for a value outside the enum")
+	}
+
+	if err := conn.UpdateLibrary("99", UpdateLibraryParams{Name: "Nope"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown library, got %v", err)
+	}
+}
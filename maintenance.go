@@ -0,0 +1,121 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ButlerTask is a scheduled maintenance task of the server, like BackupDatabase or CleanOldBundles
+type ButlerTask struct {
+	Name        string   `json:"name" xml:"name,attr"`
+	Title       string   `json:"title" xml:"title,attr"`
+	Description string   `json:"description" xml:"description,attr"`
+	Enabled     FlexBool `json:"enabled" xml:"enabled,attr"`
+	// Interval is the number of days between two runs
+	Interval           FlexInt  `json:"interval" xml:"interval,attr"`
+	ScheduleRandomized FlexBool `json:"scheduleRandomized" xml:"scheduleRandomized,attr"`
+}
+
+// butlerTasksResponse is the only response without a MediaContainer: json wraps
+// the tasks in a ButlerTasks object, which is the root element in xml
+type butlerTasksResponse struct {
+	ButlerTasks struct {
+		ButlerTask []ButlerTask `json:"ButlerTask"`
+	} `json:"ButlerTasks" xml:"-"`
+	ButlerTask []ButlerTask `json:"-" xml:"ButlerTask"`
+}
+
+// EmptyTrash deletes the items of a library whose files are gone. The server does it in
+// the background, wait for it with ActivityWatcher.Run
+func (p *Plex) EmptyTrash(sectionKey string) error {
+	return p.EmptyTrashCtx(context.Background(), sectionKey)
+}
+
+// EmptyTrashCtx is like EmptyTrash but carries ctx for cancellation and deadlines
+func (p *Plex) EmptyTrashCtx(ctx context.Context, sectionKey string) error {
+	if sectionKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(ctx, http.MethodPut, "/library/sections/"+sectionKey+"/emptyTrash", nil)
+}
+
+// CleanBundles deletes the posters, art and other metadata files no item uses anymore.
+// The server does it in the background, wait for it with ActivityWatcher.Run
+func (p *Plex) CleanBundles() error {
+	return p.CleanBundlesCtx(context.Background())
+}
+
+// CleanBundlesCtx is like CleanBundles but carries ctx for cancellation and deadlines
+func (p *Plex) CleanBundlesCtx(ctx context.Context) error {
+	return p.action(ctx, http.MethodPut, "/library/clean/bundles", nil)
+}
+
+// OptimizeDatabase compacts the database of the server in the background, wait for it with ActivityWatcher.Run
+func (p *Plex) OptimizeDatabase() error {
+	return p.OptimizeDatabaseCtx(context.Background())
+}
+
+// OptimizeDatabaseCtx is like OptimizeDatabase but carries ctx for cancellation and deadlines
+func (p *Plex) OptimizeDatabaseCtx(ctx context.Context) error {
+	return p.action(ctx, http.MethodPut, "/library/optimize", url.Values{"async": []string{"1"}})
+}
+
+// GetButlerTasks returns the scheduled maintenance tasks of the server
+func (p *Plex) GetButlerTasks() ([]ButlerTask, error) {
+	return p.GetButlerTasksCtx(context.Background())
+}
+
+// GetButlerTasksCtx is like GetButlerTasks but carries ctx for cancellation and deadlines
+func (p *Plex) GetButlerTasksCtx(ctx context.Context) ([]ButlerTask, error) {
+	resp, err := p.get(ctx, p.URL+"/butler", p.Headers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var result butlerTasksResponse
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return append(result.ButlerTasks.ButlerTask, result.ButlerTask...), nil
+}
+
+// StartButlerTask runs a butler task now instead of waiting for its schedule.
+// Wait for it with ActivityWatcher.Run
+func (p *Plex) StartButlerTask(name string) error {
+	return p.StartButlerTaskCtx(context.Background(), name)
+}
+
+// StartButlerTaskCtx is like StartButlerTask but carries ctx for cancellation and deadlines
+func (p *Plex) StartButlerTaskCtx(ctx context.Context, name string) error {
+	if name == "" {
+		return fmt.Errorf(ErrorCommon, "a task name is required")
+	}
+
+	return p.action(ctx, http.MethodPost, "/butler/"+name, nil)
+}
+
+// StopButlerTask stops a running butler task
+func (p *Plex) StopButlerTask(name string) error {
+	return p.StopButlerTaskCtx(context.Background(), name)
+}
+
+// StopButlerTaskCtx is like StopButlerTask but carries ctx for cancellation and deadlines
+func (p *Plex) StopButlerTaskCtx(ctx context.Context, name string) error {
+	if name == "" {
+		return fmt.Errorf(ErrorCommon, "a task name is required")
+	}
+
+	return p.action(ctx, http.MethodDelete, "/butler/"+name, nil)
+}
@@ -0,0 +1,187 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func TestMaintenance(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	watcher, err := conn.WatchActivitiesCtx(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer watcher.Close()
+
+	if err := server.WaitForSubscribers(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// run the operations in order, waiting for each one like a nightly job would
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"empty trash", func() error { return conn.EmptyTrash("1") }},
+		{"clean bundles", conn.CleanBundles},
+		{"optimize", conn.OptimizeDatabase},
+		{"backup", func() error { return conn.StartButlerTask("BackupDatabase") }},
+	}
+
+	for _, step := range steps {
+		if err := watcher.Run(ctx, step.run); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+	}
+
+	for _, request := range server.Requests() {
+		if request.Path == "/library/optimize" && request.Query.Get("async") != "1" {
+			t.Errorf("unexpected optimize request %+v", request)
+		}
+	}
+
+	if countRequests(server, "/library/optimize") != 1 {
+		t.Error("expected a single optimize request")
+	}
+
+	tasks, err := conn.GetButlerTasks()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tasks) != 4 || tasks[0].Name != "BackupDatabase" || !tasks[0].Enabled.Bool() || tasks[0].Interval.Int() != 3 || tasks[3].Enabled.Bool() {
+		t.Errorf("unexpected butler tasks %+v", tasks)
+	}
+
+	if err := conn.StopButlerTask("BackupDatabase"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.StartButlerTask("NoSuchTask"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown task, got %v", err)
+	}
+}
+
+func TestGetButlerTasksXML(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	conn.Headers.Accept = "application/xml"
+
+	tasks, err := conn.GetButlerTasks()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tasks) != 4 || tasks[1].Name != "CleanOldBundles" || tasks[1].Interval.Int() != 7 {
+		t.Errorf("unexpected butler tasks %+v", tasks)
+	}
+}
+
+func TestWaitForActivityPolls(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	interval := activityPollInterval
+	activityPollInterval = 50 * time.Millisecond
+
+	defer func() { activityPollInterval = interval }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	watcher, err := conn.WatchActivitiesCtx(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer watcher.Close()
+
+	// an operation the server reports under another type, or not at all, is never seen to end
+	shortCtx, shortCancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer shortCancel()
+
+	if err := watcher.WaitForActivity(shortCtx, "library.unreported"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the wait for an unreported activity to time out, got %v", err)
+	}
+
+	// the optimization in the fixtures keeps running
+	shortCtx, shortCancel = context.WithTimeout(ctx, 300*time.Millisecond)
+	defer shortCancel()
+
+	if err := watcher.WaitForActivity(shortCtx, "media.generate.optimized"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the wait to time out, got %v", err)
+	}
+
+	if polls := countRequests(server, "/activities"); polls < 2 {
+		t.Errorf("expected the waits to poll /activities, got %d requests", polls)
+	}
+}
+
+// countRequests returns how many requests for path the server has received
+func countRequests(server *plextest.Server, path string) int {
+	count := 0
+
+	for _, request := range server.Requests() {
+		if request.Path == path {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestWaitForActivityEndsOnPoll(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	interval := activityPollInterval
+	activityPollInterval = 50 * time.Millisecond
+
+	defer func() { activityPollInterval = interval }()
+
+	// the optimization is listed once, then it is gone without an ended notification
+	var polls int32
+
+	server.Handle(http.MethodGet, "/activities", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if atomic.AddInt32(&polls, 1) == 1 {
+			w.Write([]byte(`{"MediaContainer":{"size":1,"Activity":[{"uuid":"optimize","type":"media.generate.optimized"}]}}`))
+			return
+		}
+
+		w.Write([]byte(`{"MediaContainer":{"size":0}}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	watcher, err := conn.WatchActivitiesCtx(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer watcher.Close()
+
+	if err := watcher.WaitForActivity(ctx, "media.generate.optimized"); err != nil {
+		t.Errorf("expected the poll to end the wait, got %v", err)
+	}
+
+	if n := atomic.LoadInt32(&polls); n != 2 {
+		t.Errorf("expected the wait to end on the second poll, got %d polls", n)
+	}
+}
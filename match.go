@@ -0,0 +1,165 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MetadataMatch is metadata an agent found for an item, see GetMatches
+type MetadataMatch struct {
+	GUID    string   `json:"guid" xml:"guid,attr"`
+	Name    string   `json:"name" xml:"name,attr"`
+	Year    FlexInt  `json:"year" xml:"year,attr"`
+	Summary string   `json:"summary" xml:"summary,attr"`
+	Thumb   string   `json:"thumb" xml:"thumb,attr"`
+	Type    string   `json:"type" xml:"type,attr"`
+	Score   FlexInt  `json:"score" xml:"score,attr"`
+	Matched FlexBool `json:"matched" xml:"matched,attr"`
+}
+
+type matchesResponse struct {
+	MediaContainer struct {
+		SearchResult []MetadataMatch `json:"SearchResult" xml:"SearchResult"`
+	} `json:"MediaContainer" xml:"MediaContainer"`
+}
+
+// GetMatchesParams narrow down the search of GetMatches. Without a title or a year the
+// search uses the ones of the item, like the automatic match of a scan
+type GetMatchesParams struct {
+	Title string
+	Year  int
+	// Agent and Language default to the ones of the library
+	Agent    string
+	Language string
+}
+
+// ExternalID returns the id of the item at a metadata provider, like imdb, tmdb or tvdb,
+// read from its AltGUIDs. It is empty when the item has none
+func (m Metadata) ExternalID(provider string) string {
+	prefix := provider + "://"
+
+	for _, guid := range m.AltGUIDs {
+		if strings.HasPrefix(guid.ID, prefix) {
+			return strings.TrimPrefix(guid.ID, prefix)
+		}
+	}
+
+	return ""
+}
+
+// GetMatches searches the agent for the metadata an item could be matched to, best match first
+func (p *Plex) GetMatches(ratingKey string, params GetMatchesParams) ([]MetadataMatch, error) {
+	return p.GetMatchesCtx(context.Background(), ratingKey, params)
+}
+
+// GetMatchesCtx is like GetMatches but carries ctx for cancellation and deadlines
+func (p *Plex) GetMatchesCtx(ctx context.Context, ratingKey string, params GetMatchesParams) ([]MetadataMatch, error) {
+	if ratingKey == "" {
+		return nil, fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	vals := url.Values{}
+
+	if params.Title != "" || params.Year != 0 {
+		vals.Set("manual", "1")
+		vals.Set("title", params.Title)
+	}
+
+	if params.Year != 0 {
+		vals.Set("year", strconv.Itoa(params.Year))
+	}
+
+	if params.Agent != "" {
+		vals.Set("agent", params.Agent)
+	}
+
+	if params.Language != "" {
+		vals.Set("language", params.Language)
+	}
+
+	query := p.URL + "/library/metadata/" + ratingKey + "/matches"
+
+	if len(vals) > 0 {
+		query += "?" + vals.Encode()
+	}
+
+	resp, err := p.get(ctx, query, p.Headers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var result matchesResponse
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.MediaContainer.SearchResult, nil
+}
+
+// Match matches an item to metadata found by GetMatches, replacing its title, guid and AltGUIDs
+func (p *Plex) Match(ratingKey string, match MetadataMatch) error {
+	return p.MatchCtx(context.Background(), ratingKey, match)
+}
+
+// MatchCtx is like Match but carries ctx for cancellation and deadlines
+func (p *Plex) MatchCtx(ctx context.Context, ratingKey string, match MetadataMatch) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	if match.GUID == "" {
+		return fmt.Errorf(ErrorCommon, "the guid of the match is required")
+	}
+
+	vals := url.Values{}
+
+	vals.Set("guid", match.GUID)
+	vals.Set("name", match.Name)
+
+	if match.Year.Int() != 0 {
+		vals.Set("year", strconv.Itoa(match.Year.Int()))
+	}
+
+	return p.action(ctx, http.MethodPut, "/library/metadata/"+ratingKey+"/match", vals)
+}
+
+// Unmatch removes the match of an item. It keeps the metadata taken from its files until it is matched again
+func (p *Plex) Unmatch(ratingKey string) error {
+	return p.UnmatchCtx(context.Background(), ratingKey)
+}
+
+// UnmatchCtx is like Unmatch but carries ctx for cancellation and deadlines
+func (p *Plex) UnmatchCtx(ctx context.Context, ratingKey string) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(ctx, http.MethodPut, "/library/metadata/"+ratingKey+"/unmatch", nil)
+}
+
+// RefreshMetadata downloads the metadata of an item and its children again from its agent.
+// Locked fields are left alone. The refresh runs in the background
+func (p *Plex) RefreshMetadata(ratingKey string) error {
+	return p.RefreshMetadataCtx(context.Background(), ratingKey)
+}
+
+// RefreshMetadataCtx is like RefreshMetadata but carries ctx for cancellation and deadlines
+func (p *Plex) RefreshMetadataCtx(ctx context.Context, ratingKey string) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(ctx, http.MethodPut, "/library/metadata/"+ratingKey+"/refresh", nil)
+}
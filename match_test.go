@@ -0,0 +1,80 @@
+package plex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func TestFixMatch(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	// the fixtures match Blade Runner to Blade Runner 2049
+	metadata, err := conn.GetMetadata("102")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := metadata.MediaContainer.Metadata[0].ExternalID("imdb"); id != "tt1856101" {
+		t.Fatalf("expected the imdb id of the wrong match, got %q", id)
+	}
+
+	matches, err := conn.GetMatches("102", GetMatchesParams{Title: "Blade Runner", Year: 1982})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 2 || matches[0].GUID != "plex://movie/blade-runner" || matches[0].Matched.Bool() || !matches[1].Matched.Bool() {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+
+	if err := conn.Match("102", matches[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if metadata, err = conn.GetMetadata("102"); err != nil {
+		t.Fatal(err)
+	}
+
+	fixed := metadata.MediaContainer.Metadata[0]
+
+	if fixed.GUID != "plex://movie/blade-runner" || fixed.ExternalID("imdb") != "tt0083658" || fixed.ExternalID("tmdb") != "78" {
+		t.Errorf("unexpected metadata after matching %+v", fixed)
+	}
+
+	// without params the search uses the title and year of the item
+	if matches, err = conn.GetMatches("102", GetMatchesParams{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) == 0 || !matches[0].Matched.Bool() {
+		t.Errorf("expected the current match first, got %+v", matches)
+	}
+
+	if err := conn.RefreshMetadata("102"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.Unmatch("102"); err != nil {
+		t.Fatal(err)
+	}
+
+	if metadata, err = conn.GetMetadata("102"); err != nil {
+		t.Fatal(err)
+	}
+
+	if unmatched := metadata.MediaContainer.Metadata[0]; unmatched.GUID != "local://102" || unmatched.ExternalID("imdb") != "" {
+		t.Errorf("unexpected metadata after unmatching %+v", unmatched)
+	}
+
+	if err := conn.Match("102", MetadataMatch{GUID: "plex://movie/unknown"}); err == nil {
+		t.Error("expected an error for an unknown guid")
+	}
+
+	if err := conn.RefreshMetadata("999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
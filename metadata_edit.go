@@ -0,0 +1,276 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetadataField is a field of an item that can be edited with EditMetadata
+type MetadataField string
+
+// Fields that hold a single value, see MetadataEdits.Set
+const (
+	FieldTitle                 MetadataField = "title"
+	FieldTitleSort             MetadataField = "titleSort"
+	FieldOriginalTitle         MetadataField = "originalTitle"
+	FieldSummary               MetadataField = "summary"
+	FieldYear                  MetadataField = "year"
+	FieldContentRating         MetadataField = "contentRating"
+	FieldStudio                MetadataField = "studio"
+	FieldOriginallyAvailableAt MetadataField = "originallyAvailableAt"
+	FieldTagline               MetadataField = "tagline"
+)
+
+// Fields that hold a list of tags, see MetadataEdits.AddTags
+const (
+	FieldGenre      MetadataField = "genre"
+	FieldDirector   MetadataField = "director"
+	FieldWriter     MetadataField = "writer"
+	FieldCollection MetadataField = "collection"
+	FieldLabel      MetadataField = "label"
+)
+
+var valueFields = map[MetadataField]bool{
+	FieldTitle:                 true,
+	FieldTitleSort:             true,
+	FieldOriginalTitle:         true,
+	FieldSummary:               true,
+	FieldYear:                  true,
+	FieldContentRating:         true,
+	FieldStudio:                true,
+	FieldOriginallyAvailableAt: true,
+	FieldTagline:               true,
+}
+
+var tagFields = map[MetadataField]bool{
+	FieldGenre:      true,
+	FieldDirector:   true,
+	FieldWriter:     true,
+	FieldCollection: true,
+	FieldLabel:      true,
+}
+
+// MetadataEdits are the changes EditMetadata applies to an item. A locked field keeps its
+// value when the server refreshes the metadata of the item from its agent.
+// The zero value is an empty set of edits
+//
+//	edits := plex.NewMetadataEdits().
+//		Set(plex.FieldTitle, "Alien: Director's Cut").
+//		SetYear(2003).
+//		AddTags(plex.FieldGenre, "Horror", "Sci-Fi").
+//		RemoveTags(plex.FieldCollection, "Old Favorites").
+//		Lock(plex.FieldTitle, plex.FieldGenre)
+//
+//	err := plexConn.EditMetadata("100", edits)
+type MetadataEdits struct {
+	values  map[MetadataField]string
+	added   map[MetadataField][]string
+	removed map[MetadataField][]string
+	locks   map[MetadataField]bool
+}
+
+// NewMetadataEdits returns an empty set of edits
+func NewMetadataEdits() *MetadataEdits {
+	return &MetadataEdits{}
+}
+
+// Set changes the value of a single value field, like FieldTitle
+func (e *MetadataEdits) Set(field MetadataField, value string) *MetadataEdits {
+	if e.values == nil {
+		e.values = map[MetadataField]string{}
+	}
+
+	e.values[field] = value
+
+	return e
+}
+
+// SetYear changes the year of the item
+func (e *MetadataEdits) SetYear(year int) *MetadataEdits {
+	return e.Set(FieldYear, strconv.Itoa(year))
+}
+
+// SetOriginallyAvailableAt changes the release date of the item
+func (e *MetadataEdits) SetOriginallyAvailableAt(date time.Time) *MetadataEdits {
+	return e.Set(FieldOriginallyAvailableAt, date.Format("2006-01-02"))
+}
+
+// AddTags adds tags to a tag field, like FieldGenre
+func (e *MetadataEdits) AddTags(field MetadataField, tags ...string) *MetadataEdits {
+	if e.added == nil {
+		e.added = map[MetadataField][]string{}
+	}
+
+	e.added[field] = append(e.added[field], tags...)
+
+	return e
+}
+
+// RemoveTags removes tags from a tag field, like FieldGenre
+func (e *MetadataEdits) RemoveTags(field MetadataField, tags ...string) *MetadataEdits {
+	if e.removed == nil {
+		e.removed = map[MetadataField][]string{}
+	}
+
+	e.removed[field] = append(e.removed[field], tags...)
+
+	return e
+}
+
+// Lock keeps the fields from being changed by metadata refreshes
+func (e *MetadataEdits) Lock(fields ...MetadataField) *MetadataEdits {
+	if e.locks == nil {
+		e.locks = map[MetadataField]bool{}
+	}
+
+	for _, field := range fields {
+		e.locks[field] = true
+	}
+
+	return e
+}
+
+// Unlock lets metadata refreshes change the fields again
+func (e *MetadataEdits) Unlock(fields ...MetadataField) *MetadataEdits {
+	if e.locks == nil {
+		e.locks = map[MetadataField]bool{}
+	}
+
+	for _, field := range fields {
+		e.locks[field] = false
+	}
+
+	return e
+}
+
+// validate checks every field is used the way its kind allows
+func (e *MetadataEdits) validate() error {
+	if e == nil {
+		return fmt.Errorf("plex: edits are required")
+	}
+
+	for field := range e.values {
+		if !valueFields[field] {
+			return fmt.Errorf("plex: %q is not a single value field", field)
+		}
+	}
+
+	for _, tags := range []map[MetadataField][]string{e.added, e.removed} {
+		for field := range tags {
+			if !tagFields[field] {
+				return fmt.Errorf("plex: %q is not a tag field", field)
+			}
+		}
+	}
+
+	for field := range e.locks {
+		if !valueFields[field] && !tagFields[field] {
+			return fmt.Errorf("plex: %q is not an editable field", field)
+		}
+	}
+
+	return nil
+}
+
+// touches reports whether the edits add, remove, lock or unlock tags of field
+func (e *MetadataEdits) touches(field MetadataField) bool {
+	_, added := e.added[field]
+	_, removed := e.removed[field]
+	_, locked := e.locks[field]
+
+	return added || removed || locked
+}
+
+// encode adds the edits to vals the way the plex web app sends them, i.e.
+// title.value=Alien&title.locked=1&genre[0].tag.tag=Horror&genre[].tag.tag-=Drama
+func (e *MetadataEdits) encode(vals url.Values) {
+	for field, value := range e.values {
+		vals.Set(string(field)+".value", value)
+	}
+
+	for field, tags := range e.added {
+		for ii, tag := range tags {
+			vals.Set(fmt.Sprintf("%s[%d].tag.tag", field, ii), tag)
+		}
+	}
+
+	for field, tags := range e.removed {
+		vals.Set(string(field)+"[].tag.tag-", strings.Join(tags, ","))
+	}
+
+	for field, locked := range e.locks {
+		if locked {
+			vals.Set(string(field)+".locked", "1")
+		} else {
+			vals.Set(string(field)+".locked", "0")
+		}
+	}
+}
+
+// EditMetadata applies edits to the item with ratingKey. The library and type of the
+// item are looked up first, the edit itself uses the same request as AddLabelToMedia.
+// Like AddLabelToMedia, edits of FieldLabel fail with ErrPlexPassRequired without a Plex Pass
+func (p *Plex) EditMetadata(ratingKey string, edits *MetadataEdits) error {
+	return p.EditMetadataCtx(context.Background(), ratingKey, edits)
+}
+
+// EditMetadataCtx is like EditMetadata but carries ctx for cancellation and deadlines
+func (p *Plex) EditMetadataCtx(ctx context.Context, ratingKey string, edits *MetadataEdits) error {
+	if err := edits.validate(); err != nil {
+		return err
+	}
+
+	if edits.touches(FieldLabel) {
+		if err := p.requirePlexPass(ctx, "edit labels"); err != nil {
+			return err
+		}
+	}
+
+	metadata, err := p.GetMetadataCtx(ctx, ratingKey)
+
+	if err != nil {
+		return err
+	}
+
+	if len(metadata.MediaContainer.Metadata) == 0 {
+		return ErrNotFound
+	}
+
+	item := metadata.MediaContainer.Metadata[0]
+
+	query := fmt.Sprintf("%s/library/sections/%d/all", p.URL, item.LibrarySectionID.Int())
+
+	parsedQuery, err := url.Parse(query)
+
+	if err != nil {
+		return err
+	}
+
+	vals := parsedQuery.Query()
+
+	vals.Add("type", GetMediaTypeID(item.Type))
+	vals.Add("id", ratingKey)
+
+	edits.encode(vals)
+
+	parsedQuery.RawQuery = vals.Encode()
+
+	resp, err := p.put(ctx, parsedQuery.String(), nil, p.Headers)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package plex
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func TestEditMetadata(t *testing.T) {
+	fixtures := plextest.DefaultFixtures()
+	fixtures.Items[0].Genres = []string{"Drama", "Horror"}
+
+	conn, server := newTestConn(t, fixtures)
+
+	edits := NewMetadataEdits().
+		Set(FieldTitle, "Alien: Director's Cut").
+		Set(FieldStudio, "20th Century Fox").
+		SetYear(2003).
+		SetOriginallyAvailableAt(time.Date(2003, time.October, 31, 0, 0, 0, 0, time.UTC)).
+		AddTags(FieldGenre, "Sci-Fi").
+		RemoveTags(FieldGenre, "Drama").
+		AddTags(FieldDirector, "Ridley Scott").
+		Lock(FieldTitle, FieldGenre)
+
+	if err := conn.EditMetadata("100", edits); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := server.Requests()
+
+	if last := requests[len(requests)-1]; last.Method != "PUT" || last.Path != "/library/sections/1/all" || last.Query.Get("type") != "1" {
+		t.Errorf("unexpected edit request %+v", last)
+	}
+
+	metadata, err := conn.GetMetadata("100")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item := metadata.MediaContainer.Metadata[0]
+
+	if item.Title != "Alien: Director's Cut" || item.Year.Int() != 2003 || item.Studio != "20th Century Fox" || item.OriginallyAvailableAt != "2003-10-31" {
+		t.Errorf("unexpected metadata %+v", item)
+	}
+
+	var genres []string
+
+	for _, genre := range item.Genre {
+		genres = append(genres, genre.Tag)
+	}
+
+	if strings.Join(genres, ",") != "Horror,Sci-Fi" {
+		t.Errorf("unexpected genres %v", genres)
+	}
+
+	if len(item.Director) != 1 || item.Director[0].Tag != "Ridley Scott" {
+		t.Errorf("unexpected directors %+v", item.Director)
+	}
+
+	if len(item.Fields) != 2 || !item.Fields[0].Locked.Bool() {
+		t.Errorf("expected title and genre to be locked, got %+v", item.Fields)
+	}
+
+	if err := conn.EditMetadata("100", NewMetadataEdits().Unlock(FieldTitle, FieldGenre)); err != nil {
+		t.Fatal(err)
+	}
+
+	if locked := server.Fixtures().Items[0].LockedFields; len(locked) != 0 {
+		t.Errorf("expected every field to be unlocked, got %v", locked)
+	}
+
+	// the zero value is usable without NewMetadataEdits
+	var summary MetadataEdits
+
+	if err := conn.EditMetadata("100", summary.Set(FieldSummary, "In space no one can hear you scream")); err != nil {
+		t.Fatal(err)
+	}
+
+	if item := server.Fixtures().Items[0]; item.Summary != "In space no one can hear you scream" {
+		t.Errorf("unexpected summary %q", item.Summary)
+	}
+}
+
+func TestEditMetadataValidation(t *testing.T) {
+	conn := &Plex{}
+
+	tests := []*MetadataEdits{
+		NewMetadataEdits().Set(FieldGenre, "Horror"),
+		NewMetadataEdits().AddTags(FieldTitle, "Alien"),
+		NewMetadataEdits().Lock("rating"),
+		new(MetadataEdits).RemoveTags(FieldYear, "1979"),
+		nil,
+	}
+
+	for _, edits := range tests {
+		if err := conn.EditMetadata("100", edits); err == nil || !strings.HasPrefix(err.Error(), "plex:") {
+			t.Errorf("expected a validation error, got %v", err)
+		}
+	}
+}
@@ -1,8 +1,8 @@
 package plex
 
 import (
-	"encoding/json"
 	"encoding/xml"
+	"io"
 	"net/http"
 	"time"
 )
@@ -10,12 +10,25 @@ import (
 // Plex contains fields that are required to make
 // an api call to your plex server
 type Plex struct {
-	URL              string
-	Token            string
+	URL   string
+	Token string
+	// PlexTVURL is the base url used for plex.tv requests. It defaults to https://plex.tv
+	PlexTVURL        string
 	ClientIdentifier string
-	Headers          headers
-	HTTPClient       http.Client
-	DownloadClient   http.Client
+	// MachineIdentifier identifies the server in playlist uris. It is looked up from /identity when empty
+	MachineIdentifier string
+	Headers           headers
+	HTTPClient        http.Client
+	DownloadClient    http.Client
+	// Middleware wraps every request sent by this instance. See Use
+	Middleware []Middleware
+	// RetryPolicy retries idempotent requests on transient errors. nil disables retries
+	RetryPolicy *RetryPolicy
+	// DryRun logs the requests that change the server to the writer instead of sending them.
+	// They fail with ErrDryRun. nil sends every request
+	DryRun io.Writer
+	// capabilities caches the server capabilities the Plex Pass features check
+	capabilities *capabilityCache
 }
 
 // SearchResults a list of media returned when searching
@@ -23,9 +36,9 @@ type Plex struct {
 
 // Provider ...
 type Provider struct {
-	Key   string `json:"key"`
-	Title string `json:"title"`
-	Type  string `json:"type"`
+	Key   string `json:"key" xml:"key,attr"`
+	Title string `json:"title" xml:"title,attr"`
+	Type  string `json:"type" xml:"type,attr"`
 }
 
 // SearchMediaContainer ...
@@ -41,89 +54,112 @@ type SearchResults struct {
 
 // Metadata ...
 type Metadata struct {
-	Player                Player       `json:"Player"`
-	Session               Session      `json:"Session"`
-	User                  User         `json:"User"`
-	AddedAt               int          `json:"addedAt"`
-	Art                   string       `json:"art"`
-	ContentRating         string       `json:"contentRating"`
-	Duration              int          `json:"duration"`
-	GrandparentArt        string       `json:"grandparentArt"`
-	GrandparentKey        string       `json:"grandparentKey"`
-	GrandparentRatingKey  string       `json:"grandparentRatingKey"`
-	GrandparentTheme      string       `json:"grandparentTheme"`
-	GrandparentThumb      string       `json:"grandparentThumb"`
-	GrandparentTitle      string       `json:"grandparentTitle"`
-	GUID                  string       `json:"guid"`
-	AltGUIDs              []AltGUID    `json:"Guid"`
-	Index                 int64        `json:"index"`
-	Key                   string       `json:"key"`
-	LastViewedAt          int          `json:"lastViewedAt"`
-	LibrarySectionID      json.Number  `json:"librarySectionID"`
-	LibrarySectionKey     string       `json:"librarySectionKey"`
-	LibrarySectionTitle   string       `json:"librarySectionTitle"`
-	OriginallyAvailableAt string       `json:"originallyAvailableAt"`
-	ParentIndex           int64        `json:"parentIndex"`
-	ParentKey             string       `json:"parentKey"`
-	ParentRatingKey       string       `json:"parentRatingKey"`
-	ParentThumb           string       `json:"parentThumb"`
-	ParentTitle           string       `json:"parentTitle"`
-	RatingCount           string       `json:"ratingCount"`
-	Rating                float64      `json:"rating"`
-	RatingKey             string       `json:"ratingKey"`
-	SessionKey            string       `json:"sessionKey"`
-	Summary               string       `json:"summary"`
-	Thumb                 string       `json:"thumb"`
-	Media                 []Media      `json:"Media"`
-	Title                 string       `json:"title"`
-	TitleSort             string       `json:"titleSort"`
-	Type                  string       `json:"type"`
-	UpdatedAt             int          `json:"updatedAt"`
-	ViewCount             json.Number  `json:"viewCount"`
-	ViewOffset            int          `json:"viewOffset"`
-	Year                  int          `json:"year"`
-	Director              []TaggedData `json:"Director"`
-	Writer                []TaggedData `json:"Writer"`
+	Player                Player       `json:"Player" xml:"Player"`
+	Session               Session      `json:"Session" xml:"Session"`
+	User                  User         `json:"User" xml:"User"`
+	AddedAt               Timestamp    `json:"addedAt" xml:"addedAt,attr"`
+	Art                   string       `json:"art" xml:"art,attr"`
+	ContentRating         string       `json:"contentRating" xml:"contentRating,attr"`
+	Duration              Millis       `json:"duration" xml:"duration,attr"`
+	GrandparentArt        string       `json:"grandparentArt" xml:"grandparentArt,attr"`
+	GrandparentKey        string       `json:"grandparentKey" xml:"grandparentKey,attr"`
+	GrandparentRatingKey  string       `json:"grandparentRatingKey" xml:"grandparentRatingKey,attr"`
+	GrandparentTheme      string       `json:"grandparentTheme" xml:"grandparentTheme,attr"`
+	GrandparentThumb      string       `json:"grandparentThumb" xml:"grandparentThumb,attr"`
+	GrandparentTitle      string       `json:"grandparentTitle" xml:"grandparentTitle,attr"`
+	GUID                  string       `json:"guid" xml:"guid,attr"`
+	AltGUIDs              []AltGUID    `json:"Guid" xml:"Guid"`
+	Index                 FlexInt      `json:"index" xml:"index,attr"`
+	Key                   string       `json:"key" xml:"key,attr"`
+	LastViewedAt          Timestamp    `json:"lastViewedAt" xml:"lastViewedAt,attr"`
+	LibrarySectionID      FlexInt      `json:"librarySectionID" xml:"librarySectionID,attr"`
+	LibrarySectionKey     string       `json:"librarySectionKey" xml:"librarySectionKey,attr"`
+	LibrarySectionTitle   string       `json:"librarySectionTitle" xml:"librarySectionTitle,attr"`
+	OriginallyAvailableAt string       `json:"originallyAvailableAt" xml:"originallyAvailableAt,attr"`
+	ParentIndex           FlexInt      `json:"parentIndex" xml:"parentIndex,attr"`
+	ParentKey             string       `json:"parentKey" xml:"parentKey,attr"`
+	ParentRatingKey       string       `json:"parentRatingKey" xml:"parentRatingKey,attr"`
+	ParentThumb           string       `json:"parentThumb" xml:"parentThumb,attr"`
+	ParentTitle           string       `json:"parentTitle" xml:"parentTitle,attr"`
+	RatingCount           FlexInt      `json:"ratingCount" xml:"ratingCount,attr"`
+	Rating                FlexFloat    `json:"rating" xml:"rating,attr"`
+	RatingKey             string       `json:"ratingKey" xml:"ratingKey,attr"`
+	SessionKey            string       `json:"sessionKey" xml:"sessionKey,attr"`
+	Summary               string       `json:"summary" xml:"summary,attr"`
+	Thumb                 string       `json:"thumb" xml:"thumb,attr"`
+	Media                 []Media      `json:"Media" xml:"Media"`
+	Title                 string       `json:"title" xml:"title,attr"`
+	TitleSort             string       `json:"titleSort" xml:"titleSort,attr"`
+	Type                  string       `json:"type" xml:"type,attr"`
+	UpdatedAt             Timestamp    `json:"updatedAt" xml:"updatedAt,attr"`
+	ViewCount             FlexInt      `json:"viewCount" xml:"viewCount,attr"`
+	ViewOffset            Millis       `json:"viewOffset" xml:"viewOffset,attr"`
+	Year                  FlexInt      `json:"year" xml:"year,attr"`
+	Director              []TaggedData `json:"Director" xml:"Director"`
+	Writer                []TaggedData `json:"Writer" xml:"Writer"`
+	Genre                 []TaggedData `json:"Genre" xml:"Genre"`
+	Collection            []TaggedData `json:"Collection" xml:"Collection"`
+	Label                 []TaggedData `json:"Label" xml:"Label"`
+	OriginalTitle         string       `json:"originalTitle" xml:"originalTitle,attr"`
+	Studio                string       `json:"studio" xml:"studio,attr"`
+	Tagline               string       `json:"tagline" xml:"tagline,attr"`
+	UserRating            FlexFloat    `json:"userRating" xml:"userRating,attr"`
+	LeafCount             FlexInt      `json:"leafCount" xml:"leafCount,attr"`
+	ViewedLeafCount       FlexInt      `json:"viewedLeafCount" xml:"viewedLeafCount,attr"`
+	// PlaylistItemID identifies the entry of a playlist item, see GetPlaylistItems
+	PlaylistItemID FlexInt `json:"playlistItemID" xml:"playlistItemID,attr"`
+	// Fields lists the locked fields, see EditMetadata
+	Fields []LockedField `json:"Field" xml:"Field"`
+}
+
+// LockedField is a field of an item that metadata refreshes do not change
+type LockedField struct {
+	Name   string   `json:"name" xml:"name,attr"`
+	Locked FlexBool `json:"locked" xml:"locked,attr"`
 }
 
 // AltGUID represents a Globally Unique Identifier for a metadata provider that is not actively being used.
 type AltGUID struct {
-	ID string `json:"id"`
+	ID string `json:"id" xml:"id,attr"`
 }
 
 // Media media info
 type Media struct {
-	AspectRatio           json.Number `json:"aspectRatio"`
-	AudioChannels         int         `json:"audioChannels"`
-	AudioCodec            string      `json:"audioCodec"`
-	AudioProfile          string      `json:"audioProfile"`
-	Bitrate               int         `json:"bitrate"`
-	Container             string      `json:"container"`
-	Duration              int         `json:"duration"`
-	Has64bitOffsets       bool        `json:"has64bitOffsets"`
-	Height                int         `json:"height"`
-	ID                    json.Number `json:"id"`
-	OptimizedForStreaming bool        `json:"optimizedForStreaming"` // plex can return int or boolean: 0 or 1; true or false
-	Selected              bool        `json:"selected"`
-	VideoCodec            string      `json:"videoCodec"`
-	VideoFrameRate        string      `json:"videoFrameRate"`
-	VideoProfile          string      `json:"videoProfile"`
-	VideoResolution       string      `json:"videoResolution"`
-	Width                 int         `json:"width"`
-	Part                  []Part      `json:"Part"`
+	AspectRatio           FlexFloat `json:"aspectRatio" xml:"aspectRatio,attr"`
+	AudioChannels         FlexInt   `json:"audioChannels" xml:"audioChannels,attr"`
+	AudioCodec            string    `json:"audioCodec" xml:"audioCodec,attr"`
+	AudioProfile          string    `json:"audioProfile" xml:"audioProfile,attr"`
+	Bitrate               FlexInt   `json:"bitrate" xml:"bitrate,attr"`
+	Container             string    `json:"container" xml:"container,attr"`
+	Duration              Millis    `json:"duration" xml:"duration,attr"`
+	Has64bitOffsets       FlexBool  `json:"has64bitOffsets" xml:"has64bitOffsets,attr"`
+	Height                FlexInt   `json:"height" xml:"height,attr"`
+	ID                    FlexInt   `json:"id" xml:"id,attr"`
+	OptimizedForStreaming FlexBool  `json:"optimizedForStreaming" xml:"optimizedForStreaming,attr"` // plex can return int or boolean: 0 or 1; true or false
+	Selected              FlexBool  `json:"selected" xml:"selected,attr"`
+	VideoCodec            string    `json:"videoCodec" xml:"videoCodec,attr"`
+	VideoFrameRate        string    `json:"videoFrameRate" xml:"videoFrameRate,attr"`
+	VideoProfile          string    `json:"videoProfile" xml:"videoProfile,attr"`
+	VideoResolution       string    `json:"videoResolution" xml:"videoResolution,attr"`
+	Width                 FlexInt   `json:"width" xml:"width,attr"`
+	Part                  []Part    `json:"Part" xml:"Part"`
 }
 
 // MediaContainer contains media info
 type MediaContainer struct {
-	Metadata            []Metadata `json:"Metadata"`
-	AllowSync           bool       `json:"allowSync"`
-	Identifier          string     `json:"identifier"`
-	LibrarySectionID    int        `json:"librarySectionID"`
-	LibrarySectionTitle string     `json:"librarySectionTitle"`
-	LibrarySectionUUID  string     `json:"librarySectionUUID"`
-	MediaTagPrefix      string     `json:"mediaTagPrefix"`
-	MediaTagVersion     int        `json:"mediaTagVersion"`
-	Size                int        `json:"size"`
+	// xml responses name each item after its type (Video, Track, Directory...) so any child element is metadata
+	Metadata            []Metadata `json:"Metadata" xml:",any"`
+	AllowSync           FlexBool   `json:"allowSync" xml:"allowSync,attr"`
+	Identifier          string     `json:"identifier" xml:"identifier,attr"`
+	LibrarySectionID    FlexInt    `json:"librarySectionID" xml:"librarySectionID,attr"`
+	LibrarySectionTitle string     `json:"librarySectionTitle" xml:"librarySectionTitle,attr"`
+	LibrarySectionUUID  string     `json:"librarySectionUUID" xml:"librarySectionUUID,attr"`
+	MediaTagPrefix      string     `json:"mediaTagPrefix" xml:"mediaTagPrefix,attr"`
+	MediaTagVersion     FlexInt    `json:"mediaTagVersion" xml:"mediaTagVersion,attr"`
+	Size                FlexInt    `json:"size" xml:"size,attr"`
+	// TotalSize and Offset are set on paged responses. See GetLibraryContentPage
+	TotalSize FlexInt `json:"totalSize" xml:"totalSize,attr"`
+	Offset    FlexInt `json:"offset" xml:"offset,attr"`
 }
 
 // MediaMetadata ...
@@ -133,49 +169,49 @@ type MediaMetadata struct {
 
 // Location is the path of a plex server directory
 type Location struct {
-	ID   int    `json:"id"`
-	Path string `json:"path"`
+	ID   FlexInt `json:"id" xml:"id,attr"`
+	Path string  `json:"path" xml:"path,attr"`
 }
 
 // Directory shows plex directory metadata
 type Directory struct {
-	Location   []Location `json:"Location"`
-	Agent      string     `json:"agent"`
-	AllowSync  bool       `json:"allowSync"`
-	Art        string     `json:"art"`
-	Composite  string     `json:"composite"`
-	CreatedAt  int        `json:"createdAt"`
-	Filter     bool       `json:"filters"`
-	Key        string     `json:"key"`
-	Language   string     `json:"language"`
-	Refreshing bool       `json:"refreshing"`
-	Scanner    string     `json:"scanner"`
-	Thumb      string     `json:"thumb"`
-	Title      string     `json:"title"`
-	Type       string     `json:"type"`
-	UpdatedAt  int        `json:"updatedAt"`
-	UUID       string     `json:"uuid"`
+	Location   []Location `json:"Location" xml:"Location"`
+	Agent      string     `json:"agent" xml:"agent,attr"`
+	AllowSync  FlexBool   `json:"allowSync" xml:"allowSync,attr"`
+	Art        string     `json:"art" xml:"art,attr"`
+	Composite  string     `json:"composite" xml:"composite,attr"`
+	CreatedAt  Timestamp  `json:"createdAt" xml:"createdAt,attr"`
+	Filter     FlexBool   `json:"filters" xml:"filters,attr"`
+	Key        string     `json:"key" xml:"key,attr"`
+	Language   string     `json:"language" xml:"language,attr"`
+	Refreshing FlexBool   `json:"refreshing" xml:"refreshing,attr"`
+	Scanner    string     `json:"scanner" xml:"scanner,attr"`
+	Thumb      string     `json:"thumb" xml:"thumb,attr"`
+	Title      string     `json:"title" xml:"title,attr"`
+	Type       string     `json:"type" xml:"type,attr"`
+	UpdatedAt  Timestamp  `json:"updatedAt" xml:"updatedAt,attr"`
+	UUID       string     `json:"uuid" xml:"uuid,attr"`
 }
 
 // LibrarySections metadata of your library contents
 type LibrarySections struct {
 	MediaContainer struct {
-		Directory []Directory `json:"Directory"`
-	} `json:"MediaContainer"`
+		Directory []Directory `json:"Directory" xml:"Directory"`
+	} `json:"MediaContainer" xml:"MediaContainer"`
 }
 
 // TaggedData ...
 type TaggedData struct {
-	Tag    string      `json:"tag"`
-	Filter string      `json:"filter"`
-	ID     json.Number `json:"id"`
+	Tag    string  `json:"tag" xml:"tag,attr"`
+	Filter string  `json:"filter" xml:"filter,attr"`
+	ID     FlexInt `json:"id" xml:"id,attr"`
 }
 
 // Role ...
 type Role struct {
 	TaggedData
-	Role  string `json:"role"`
-	Thumb string `json:"thumb"`
+	Role  string `json:"role" xml:"role,attr"`
+	Thumb string `json:"thumb" xml:"thumb,attr"`
 }
 
 // MetadataChildren returns metadata about a piece of media (tv show, movie, music, etc)
@@ -188,128 +224,93 @@ type SearchResultsEpisode struct {
 	MediaContainer MediaContainer `json:"MediaContainer"`
 }
 
-type plexResponse struct {
-	Children []struct {
-		ElementType string `json:"_elementType"`
-		Count       string `json:"count"`
-		Key         string `json:"key"`
-		Title       string `json:"title"`
-	} `json:"_children"`
-	ElementType                   string `json:"_elementType"`
-	AllowCameraUpload             string `json:"allowCameraUpload"`
-	AllowChannelAccess            string `json:"allowChannelAccess"`
-	AllowSync                     string `json:"allowSync"`
-	BackgroundProcessing          string `json:"backgroundProcessing"`
-	Certificate                   string `json:"certificate"`
-	CompanionProxy                string `json:"companionProxy"`
-	FriendlyName                  string `json:"friendlyName"`
-	HubSearch                     string `json:"hubSearch"`
-	MachineIdentifier             string `json:"machineIdentifier"`
-	Multiuser                     string `json:"multiuser"`
-	MyPlex                        string `json:"myPlex"`
-	MyPlexMappingState            string `json:"myPlexMappingState"`
-	MyPlexSigninState             string `json:"myPlexSigninState"`
-	MyPlexSubscription            string `json:"myPlexSubscription"`
-	MyPlexUsername                string `json:"myPlexUsername"`
-	Platform                      string `json:"platform"`
-	PlatformVersion               string `json:"platformVersion"`
-	RequestParametersInCookie     string `json:"requestParametersInCookie"`
-	Sync                          string `json:"sync"`
-	TranscoderActiveVideoSessions string `json:"transcoderActiveVideoSessions"`
-	TranscoderAudio               string `json:"transcoderAudio"`
-	TranscoderLyrics              string `json:"transcoderLyrics"`
-	TranscoderPhoto               string `json:"transcoderPhoto"`
-	TranscoderSubtitles           string `json:"transcoderSubtitles"`
-	TranscoderVideo               string `json:"transcoderVideo"`
-	TranscoderVideoBitrates       string `json:"transcoderVideoBitrates"`
-	TranscoderVideoQualities      string `json:"transcoderVideoQualities"`
-	TranscoderVideoResolutions    string `json:"transcoderVideoResolutions"`
-	UpdatedAt                     string `json:"updatedAt"`
-	Version                       string `json:"version"`
-}
-
 type killTranscodeResponse struct {
 	Children []struct {
-		ElementType   string  `json:"_elementType"`
-		AudioChannels int     `json:"audioChannels"`
-		AudioCodec    string  `json:"audioCodec"`
-		AudioDecision string  `json:"audioDecision"`
-		Container     string  `json:"container"`
-		Context       string  `json:"context"`
-		Duration      int     `json:"duration"`
-		Height        int     `json:"height"`
-		Key           string  `json:"key"`
-		Progress      float64 `json:"progress"`
-		Protocol      string  `json:"protocol"`
-		Remaining     int     `json:"remaining"`
-		Speed         float64 `json:"speed"`
-		Throttled     bool    `json:"throttled"`
-		VideoCodec    string  `json:"videoCodec"`
-		VideoDecision string  `json:"videoDecision"`
-		Width         int     `json:"width"`
+		ElementType   string    `json:"_elementType"`
+		AudioChannels FlexInt   `json:"audioChannels"`
+		AudioCodec    string    `json:"audioCodec"`
+		AudioDecision string    `json:"audioDecision"`
+		Container     string    `json:"container"`
+		Context       string    `json:"context"`
+		Duration      Millis    `json:"duration"`
+		Height        FlexInt   `json:"height"`
+		Key           string    `json:"key"`
+		Progress      FlexFloat `json:"progress"`
+		Protocol      string    `json:"protocol"`
+		Remaining     FlexInt   `json:"remaining"`
+		Speed         FlexFloat `json:"speed"`
+		Throttled     FlexBool  `json:"throttled"`
+		VideoCodec    string    `json:"videoCodec"`
+		VideoDecision string    `json:"videoDecision"`
+		Width         FlexInt   `json:"width"`
 	} `json:"_children"`
 	ElementType string `json:"_elementType"`
 }
 
 // CreateLibraryParams params required to create a library
 type CreateLibraryParams struct {
-	Name        string
+	Name string
+	// Location is the folder of the library. Locations adds more folders
 	Location    string
+	Locations   []string
 	LibraryType string
 	Agent       string
 	Scanner     string
 	Language    string
+	// Prefs are advanced settings of the library by id, like showOrdering or
+	// enableCreditsMarkerGeneration, see GetLibraryPreferences
+	Prefs map[string]string
 }
 
 // DevicesResponse  metadata of a device that has connected to your server
 type DevicesResponse struct {
-	ID         int    `json:"id"`
-	LastSeenAt string `json:"lastSeenAt"`
-	Name       string `json:"name"`
-	Product    string `json:"product"`
-	Version    string `json:"version"`
+	ID         FlexInt `json:"id"`
+	LastSeenAt string  `json:"lastSeenAt"`
+	Name       string  `json:"name"`
+	Product    string  `json:"product"`
+	Version    string  `json:"version"`
 }
 
 // Friends are the plex accounts that have access to your server
 type Friends struct {
-	ID                        int    `xml:"id,attr"`
-	Title                     string `xml:"title,attr"`
-	Thumb                     string `xml:"thumb,attr"`
-	Protected                 string `xml:"protected,attr"`
-	Home                      string `xml:"home,attr"`
-	AllowSync                 string `xml:"allowSync,attr"`
-	AllowCameraUpload         string `xml:"allowCameraUpload,attr"`
-	AllowChannels             string `xml:"allowChannels,attr"`
-	FilterAll                 string `xml:"filterAll,attr"`
-	FilterMovies              string `xml:"filterMovies,attr"`
-	FilterMusic               string `xml:"filterMusic,attr"`
-	FilterPhotos              string `xml:"filterPhotos,attr"`
-	FilterTelevision          string `xml:"filterTelevision,attr"`
-	Restricted                string `xml:"restricted,attr"`
-	Username                  string `xml:"username,attr"`
-	Email                     string `xml:"email,attr"`
-	RecommendationsPlaylistID string `xml:"recommendationsPlaylistId,attr"`
+	ID                        FlexInt `json:"id" xml:"id,attr"`
+	Title                     string  `json:"title" xml:"title,attr"`
+	Thumb                     string  `json:"thumb" xml:"thumb,attr"`
+	Protected                 string  `json:"protected" xml:"protected,attr"`
+	Home                      string  `json:"home" xml:"home,attr"`
+	AllowSync                 string  `json:"allowSync" xml:"allowSync,attr"`
+	AllowCameraUpload         string  `json:"allowCameraUpload" xml:"allowCameraUpload,attr"`
+	AllowChannels             string  `json:"allowChannels" xml:"allowChannels,attr"`
+	FilterAll                 string  `json:"filterAll" xml:"filterAll,attr"`
+	FilterMovies              string  `json:"filterMovies" xml:"filterMovies,attr"`
+	FilterMusic               string  `json:"filterMusic" xml:"filterMusic,attr"`
+	FilterPhotos              string  `json:"filterPhotos" xml:"filterPhotos,attr"`
+	FilterTelevision          string  `json:"filterTelevision" xml:"filterTelevision,attr"`
+	Restricted                string  `json:"restricted" xml:"restricted,attr"`
+	Username                  string  `json:"username" xml:"username,attr"`
+	Email                     string  `json:"email" xml:"email,attr"`
+	RecommendationsPlaylistID string  `json:"recommendationsPlaylistId" xml:"recommendationsPlaylistId,attr"`
 	Server                    struct {
-		ID                string `xml:"id,attr"`
-		ServerID          string `xml:"serverId,attr"`
-		MachineIdentifier string `xml:"machineIdentifier,attr"`
-		Name              string `xml:"name,attr"`
-		LastSeenAt        string `xml:"lastSeenAt,attr"`
-		NumLibraries      string `xml:"numLibraries,attr"`
-		AllLibraries      string `xml:"allLibraries,attr"`
-		Owned             string `xml:"owned,attr"`
-		Pending           string `xml:"pending,attr"`
-	} `xml:"Server"`
+		ID                string `json:"id" xml:"id,attr"`
+		ServerID          string `json:"serverId" xml:"serverId,attr"`
+		MachineIdentifier string `json:"machineIdentifier" xml:"machineIdentifier,attr"`
+		Name              string `json:"name" xml:"name,attr"`
+		LastSeenAt        string `json:"lastSeenAt" xml:"lastSeenAt,attr"`
+		NumLibraries      string `json:"numLibraries" xml:"numLibraries,attr"`
+		AllLibraries      string `json:"allLibraries" xml:"allLibraries,attr"`
+		Owned             string `json:"owned" xml:"owned,attr"`
+		Pending           string `json:"pending" xml:"pending,attr"`
+	} `json:"Server" xml:"Server"`
 }
 
 type friendsResponse struct {
-	XMLName           xml.Name  `xml:"MediaContainer"`
-	FriendlyName      string    `xml:"friendlyName,attr"`
-	Identifier        string    `xml:"identifier,attr"`
-	MachineIdentifier string    `xml:"machineIdentifier,attr"`
-	TotalSize         string    `xml:"totalSize,attr"`
-	Size              int       `xml:"size,attr"`
-	User              []Friends `xml:"User"`
+	XMLName           xml.Name  `json:"-" xml:"MediaContainer"`
+	FriendlyName      string    `json:"friendlyName" xml:"friendlyName,attr"`
+	Identifier        string    `json:"identifier" xml:"identifier,attr"`
+	MachineIdentifier string    `json:"machineIdentifier" xml:"machineIdentifier,attr"`
+	TotalSize         string    `json:"totalSize" xml:"totalSize,attr"`
+	Size              FlexInt   `json:"size" xml:"size,attr"`
+	User              []Friends `json:"User" xml:"User"`
 }
 
 type resultResponse struct {
@@ -321,50 +322,50 @@ type resultResponse struct {
 }
 
 type inviteFriendResponse struct {
-	ID                json.Number `json:"id"`
-	Name              string      `json:"name"`
-	OwnerID           json.Number `json:"ownerId"`
-	InvitedID         json.Number `json:"invitedId"`
-	InvitedEmail      string      `json:"invitedEmail"`
-	ServerID          json.Number `json:"serverId"`
-	Accepted          bool        `json:"accepted"`
-	AcceptedAt        string      `json:"acceptedAt"`
-	DeletedAt         string      `json:"deletedAt"`
-	LeftAt            string      `json:"leftAt"`
-	Owned             bool        `json:"owned"`
-	InviteToken       string      `json:"inviteToken"`
-	MachineIdentifier string      `json:"machineIdentifier"`
-	LastSeenAt        time.Time   `json:"lastSeenAt"`
-	NumLibraries      json.Number `json:"numLibraries"`
+	ID                FlexInt   `json:"id"`
+	Name              string    `json:"name"`
+	OwnerID           FlexInt   `json:"ownerId"`
+	InvitedID         FlexInt   `json:"invitedId"`
+	InvitedEmail      string    `json:"invitedEmail"`
+	ServerID          FlexInt   `json:"serverId"`
+	Accepted          FlexBool  `json:"accepted"`
+	AcceptedAt        string    `json:"acceptedAt"`
+	DeletedAt         string    `json:"deletedAt"`
+	LeftAt            string    `json:"leftAt"`
+	Owned             FlexBool  `json:"owned"`
+	InviteToken       string    `json:"inviteToken"`
+	MachineIdentifier string    `json:"machineIdentifier"`
+	LastSeenAt        time.Time `json:"lastSeenAt"`
+	NumLibraries      FlexInt   `json:"numLibraries"`
 	Invited           struct {
-		ID         json.Number `json:"id"`
-		UUID       string      `json:"uuid"`
-		Title      string      `json:"title"`
-		Username   string      `json:"username"`
-		Restricted bool        `json:"restricted"`
-		Thumb      string      `json:"thumb"`
-		Status     string      `json:"status"`
+		ID         FlexInt  `json:"id"`
+		UUID       string   `json:"uuid"`
+		Title      string   `json:"title"`
+		Username   string   `json:"username"`
+		Restricted FlexBool `json:"restricted"`
+		Thumb      string   `json:"thumb"`
+		Status     string   `json:"status"`
 	} `json:"invited"`
 	SharingSettings struct {
-		AllowChannels    bool   `json:"allowChannels"`
-		FilterMovies     string `json:"filterMovies"`
-		FilterMusic      string `json:"filterMusic"`
-		FilterPhotos     string `json:"filterPhotos"`
-		FilterTelevision string `json:"filterTelevision"`
+		AllowChannels    FlexBool `json:"allowChannels"`
+		FilterMovies     string   `json:"filterMovies"`
+		FilterMusic      string   `json:"filterMusic"`
+		FilterPhotos     string   `json:"filterPhotos"`
+		FilterTelevision string   `json:"filterTelevision"`
 		// FilterAll ??? I get null when testing. idk the true type
 		FilterAll          interface{} `json:"filterAll"`
-		AllowSync          bool        `json:"allowSync"`
-		AllowCameraUpload  bool        `json:"allowCameraUpload"`
-		AllowSubtitleAdmin bool        `json:"allowSubtitleAdmin"`
-		AllowTuners        json.Number `json:"allowTuners"`
+		AllowSync          FlexBool    `json:"allowSync"`
+		AllowCameraUpload  FlexBool    `json:"allowCameraUpload"`
+		AllowSubtitleAdmin FlexBool    `json:"allowSubtitleAdmin"`
+		AllowTuners        FlexInt     `json:"allowTuners"`
 	} `json:"sharingSettings"`
 	Libraries []struct {
-		ID    json.Number `json:"id"`
-		Key   json.Number `json:"key"`
-		Title string      `json:"title"`
-		Type  string      `json:"type"`
+		ID    FlexInt `json:"id"`
+		Key   FlexInt `json:"key"`
+		Title string  `json:"title"`
+		Type  string  `json:"type"`
 	} `json:"libraries"`
-	AllLibraries bool `json:"allLibraries"`
+	AllLibraries FlexBool `json:"allLibraries"`
 }
 
 // InviteFriendParams are the params to invite a friend
@@ -420,14 +421,14 @@ type PMSDevices struct {
 	PlatformVersion      string       `json:"platformVersion" xml:"platformVersion,attr"`
 	Device               string       `json:"device" xml:"device,attr"`
 	ClientIdentifier     string       `json:"clientIdentifier" xml:"clientIdentifier,attr"`
-	CreatedAt            string       `json:"createdAt" xml:"createdAt,attr"`
-	LastSeenAt           string       `json:"lastSeenAt" xml:"lastSeenAt,attr"`
+	CreatedAt            Timestamp    `json:"createdAt" xml:"createdAt,attr"`
+	LastSeenAt           Timestamp    `json:"lastSeenAt" xml:"lastSeenAt,attr"`
 	Provides             string       `json:"provides" xml:"provides,attr"`
 	Owned                string       `json:"owned" xml:"owned,attr"`
 	AccessToken          string       `json:"accessToken" xml:"accessToken,attr"`
-	HTTPSRequired        int          `json:"httpsRequired" xml:"httpsRequired,attr"`
+	HTTPSRequired        FlexInt      `json:"httpsRequired" xml:"httpsRequired,attr"`
 	Synced               string       `json:"synced" xml:"synced,attr"`
-	Relay                int          `json:"relay" xml:"relay,attr"`
+	Relay                FlexInt      `json:"relay" xml:"relay,attr"`
 	PublicAddressMatches string       `json:"publicAddressMatches" xml:"publicAddressMatches,attr"`
 	PublicAddress        string       `json:"publicAddress" xml:"publicAddress,attr"`
 	Presence             string       `json:"presence" xml:"presence,attr"`
@@ -436,178 +437,178 @@ type PMSDevices struct {
 
 // Connection lists options to connect to a device
 type Connection struct {
-	Protocol string `json:"protocol" xml:"protocol,attr"`
-	Address  string `json:"address" xml:"address,attr"`
-	Port     string `json:"port" xml:"port,attr"`
-	URI      string `json:"uri" xml:"uri,attr"`
-	Local    int    `json:"local" xml:"local,attr"`
+	Protocol string  `json:"protocol" xml:"protocol,attr"`
+	Address  string  `json:"address" xml:"address,attr"`
+	Port     string  `json:"port" xml:"port,attr"`
+	URI      string  `json:"uri" xml:"uri,attr"`
+	Local    FlexInt `json:"local" xml:"local,attr"`
 }
 
 // BaseAPIResponse info about the Plex Media Server
 type BaseAPIResponse struct {
 	MediaContainer struct {
 		Directory []struct {
-			Count int64  `json:"count"`
-			Key   string `json:"key"`
-			Title string `json:"title"`
-		} `json:"Directory"`
-		AllowCameraUpload             bool   `json:"allowCameraUpload"`
-		AllowChannelAccess            bool   `json:"allowChannelAccess"`
-		AllowSharing                  bool   `json:"allowSharing"`
-		AllowSync                     bool   `json:"allowSync"`
-		BackgroundProcessing          bool   `json:"backgroundProcessing"`
-		Certificate                   bool   `json:"certificate"`
-		CompanionProxy                bool   `json:"companionProxy"`
-		CountryCode                   string `json:"countryCode"`
-		Diagnostics                   string `json:"diagnostics"`
-		EventStream                   bool   `json:"eventStream"`
-		FriendlyName                  string `json:"friendlyName"`
-		HubSearch                     bool   `json:"hubSearch"`
-		ItemClusters                  bool   `json:"itemClusters"`
-		Livetv                        int64  `json:"livetv"`
-		MachineIdentifier             string `json:"machineIdentifier"`
-		MediaProviders                bool   `json:"mediaProviders"`
-		Multiuser                     bool   `json:"multiuser"`
-		MyPlex                        bool   `json:"myPlex"`
-		MyPlexMappingState            string `json:"myPlexMappingState"`
-		MyPlexSigninState             string `json:"myPlexSigninState"`
-		MyPlexSubscription            bool   `json:"myPlexSubscription"`
-		MyPlexUsername                string `json:"myPlexUsername"`
-		OwnerFeatures                 string `json:"ownerFeatures"`
-		PhotoAutoTag                  bool   `json:"photoAutoTag"`
-		Platform                      string `json:"platform"`
-		PlatformVersion               string `json:"platformVersion"`
-		PluginHost                    bool   `json:"pluginHost"`
-		ReadOnlyLibraries             bool   `json:"readOnlyLibraries"`
-		RequestParametersInCookie     bool   `json:"requestParametersInCookie"`
-		Size                          int64  `json:"size"`
-		StreamingBrainABRVersion      int64  `json:"streamingBrainABRVersion"`
-		StreamingBrainVersion         int64  `json:"streamingBrainVersion"`
-		Sync                          bool   `json:"sync"`
-		TranscoderActiveVideoSessions int64  `json:"transcoderActiveVideoSessions"`
-		TranscoderAudio               bool   `json:"transcoderAudio"`
-		TranscoderLyrics              bool   `json:"transcoderLyrics"`
-		TranscoderPhoto               bool   `json:"transcoderPhoto"`
-		TranscoderSubtitles           bool   `json:"transcoderSubtitles"`
-		TranscoderVideo               bool   `json:"transcoderVideo"`
-		TranscoderVideoBitrates       string `json:"transcoderVideoBitrates"`
-		TranscoderVideoQualities      string `json:"transcoderVideoQualities"`
-		TranscoderVideoResolutions    string `json:"transcoderVideoResolutions"`
-		UpdatedAt                     int64  `json:"updatedAt"`
-		Updater                       bool   `json:"updater"`
-		Version                       string `json:"version"`
-		VoiceSearch                   bool   `json:"voiceSearch"`
-	} `json:"MediaContainer"`
+			Count FlexInt `json:"count" xml:"count,attr"`
+			Key   string  `json:"key" xml:"key,attr"`
+			Title string  `json:"title" xml:"title,attr"`
+		} `json:"Directory" xml:"Directory"`
+		AllowCameraUpload             FlexBool  `json:"allowCameraUpload" xml:"allowCameraUpload,attr"`
+		AllowChannelAccess            FlexBool  `json:"allowChannelAccess" xml:"allowChannelAccess,attr"`
+		AllowSharing                  FlexBool  `json:"allowSharing" xml:"allowSharing,attr"`
+		AllowSync                     FlexBool  `json:"allowSync" xml:"allowSync,attr"`
+		BackgroundProcessing          FlexBool  `json:"backgroundProcessing" xml:"backgroundProcessing,attr"`
+		Certificate                   FlexBool  `json:"certificate" xml:"certificate,attr"`
+		CompanionProxy                FlexBool  `json:"companionProxy" xml:"companionProxy,attr"`
+		CountryCode                   string    `json:"countryCode" xml:"countryCode,attr"`
+		Diagnostics                   string    `json:"diagnostics" xml:"diagnostics,attr"`
+		EventStream                   FlexBool  `json:"eventStream" xml:"eventStream,attr"`
+		FriendlyName                  string    `json:"friendlyName" xml:"friendlyName,attr"`
+		HubSearch                     FlexBool  `json:"hubSearch" xml:"hubSearch,attr"`
+		ItemClusters                  FlexBool  `json:"itemClusters" xml:"itemClusters,attr"`
+		Livetv                        FlexInt   `json:"livetv" xml:"livetv,attr"`
+		MachineIdentifier             string    `json:"machineIdentifier" xml:"machineIdentifier,attr"`
+		MediaProviders                FlexBool  `json:"mediaProviders" xml:"mediaProviders,attr"`
+		Multiuser                     FlexBool  `json:"multiuser" xml:"multiuser,attr"`
+		MyPlex                        FlexBool  `json:"myPlex" xml:"myPlex,attr"`
+		MyPlexMappingState            string    `json:"myPlexMappingState" xml:"myPlexMappingState,attr"`
+		MyPlexSigninState             string    `json:"myPlexSigninState" xml:"myPlexSigninState,attr"`
+		MyPlexSubscription            FlexBool  `json:"myPlexSubscription" xml:"myPlexSubscription,attr"`
+		MyPlexUsername                string    `json:"myPlexUsername" xml:"myPlexUsername,attr"`
+		OwnerFeatures                 string    `json:"ownerFeatures" xml:"ownerFeatures,attr"`
+		PhotoAutoTag                  FlexBool  `json:"photoAutoTag" xml:"photoAutoTag,attr"`
+		Platform                      string    `json:"platform" xml:"platform,attr"`
+		PlatformVersion               string    `json:"platformVersion" xml:"platformVersion,attr"`
+		PluginHost                    FlexBool  `json:"pluginHost" xml:"pluginHost,attr"`
+		ReadOnlyLibraries             FlexBool  `json:"readOnlyLibraries" xml:"readOnlyLibraries,attr"`
+		RequestParametersInCookie     FlexBool  `json:"requestParametersInCookie" xml:"requestParametersInCookie,attr"`
+		Size                          FlexInt   `json:"size" xml:"size,attr"`
+		StreamingBrainABRVersion      FlexInt   `json:"streamingBrainABRVersion" xml:"streamingBrainABRVersion,attr"`
+		StreamingBrainVersion         FlexInt   `json:"streamingBrainVersion" xml:"streamingBrainVersion,attr"`
+		Sync                          FlexBool  `json:"sync" xml:"sync,attr"`
+		TranscoderActiveVideoSessions FlexInt   `json:"transcoderActiveVideoSessions" xml:"transcoderActiveVideoSessions,attr"`
+		TranscoderAudio               FlexBool  `json:"transcoderAudio" xml:"transcoderAudio,attr"`
+		TranscoderLyrics              FlexBool  `json:"transcoderLyrics" xml:"transcoderLyrics,attr"`
+		TranscoderPhoto               FlexBool  `json:"transcoderPhoto" xml:"transcoderPhoto,attr"`
+		TranscoderSubtitles           FlexBool  `json:"transcoderSubtitles" xml:"transcoderSubtitles,attr"`
+		TranscoderVideo               FlexBool  `json:"transcoderVideo" xml:"transcoderVideo,attr"`
+		TranscoderVideoBitrates       string    `json:"transcoderVideoBitrates" xml:"transcoderVideoBitrates,attr"`
+		TranscoderVideoQualities      string    `json:"transcoderVideoQualities" xml:"transcoderVideoQualities,attr"`
+		TranscoderVideoResolutions    string    `json:"transcoderVideoResolutions" xml:"transcoderVideoResolutions,attr"`
+		UpdatedAt                     Timestamp `json:"updatedAt" xml:"updatedAt,attr"`
+		Updater                       FlexBool  `json:"updater" xml:"updater,attr"`
+		Version                       string    `json:"version" xml:"version,attr"`
+		VoiceSearch                   FlexBool  `json:"voiceSearch" xml:"voiceSearch,attr"`
+	} `json:"MediaContainer" xml:"MediaContainer"`
 }
 
 // UserPlexTV plex.tv user. should be used when interacting with plex.tv as the id is an int
 type UserPlexTV struct {
 	// ID is an int when signing in to Plex.tv but a string when access own server
-	ID                int    `json:"id"`
-	UUID              string `json:"uuid"`
-	Email             string `json:"email"`
-	FriendlyName      string `json:"friendlyName"`
-	Locale            string `json:"locale"` // can be null
-	Confirmed         bool   `json:"confirmed"`
-	EmailOnlyAuth     bool   `json:"emailOnlyAuth"`
-	Protected         bool   `json:"protected"`
-	MailingListStatus string `json:"mailingListStatus"`
-	MailingListActive bool   `json:"mailingListActive"`
-	ScrobbleTypes     string `json:"scrobbleTypes"`
-	Country           string `json:"country"`
-	JoinedAt          string `json:"joined_at"`
-	Username          string `json:"username"`
-	Thumb             string `json:"thumb"`
-	HasPassword       bool   `json:"hasPassword"`
-	AuthToken         string `json:"authToken"`
+	ID                FlexInt  `json:"id" xml:"id,attr"`
+	UUID              string   `json:"uuid" xml:"uuid,attr"`
+	Email             string   `json:"email" xml:"email,attr"`
+	FriendlyName      string   `json:"friendlyName" xml:"friendlyName,attr"`
+	Locale            string   `json:"locale" xml:"locale,attr"` // can be null
+	Confirmed         FlexBool `json:"confirmed" xml:"confirmed,attr"`
+	EmailOnlyAuth     FlexBool `json:"emailOnlyAuth" xml:"emailOnlyAuth,attr"`
+	Protected         FlexBool `json:"protected" xml:"protected,attr"`
+	MailingListStatus string   `json:"mailingListStatus" xml:"mailingListStatus,attr"`
+	MailingListActive FlexBool `json:"mailingListActive" xml:"mailingListActive,attr"`
+	ScrobbleTypes     string   `json:"scrobbleTypes" xml:"scrobbleTypes,attr"`
+	Country           string   `json:"country" xml:"country,attr"`
+	JoinedAt          string   `json:"joined_at" xml:"joined_at,attr"`
+	Username          string   `json:"username" xml:"username,attr"`
+	Thumb             string   `json:"thumb" xml:"thumb,attr"`
+	HasPassword       FlexBool `json:"hasPassword" xml:"hasPassword,attr"`
+	AuthToken         string   `json:"authToken" xml:"authToken,attr"`
 	// AuthenticationToken string `json:"authenticationToken"`
 	Subscription struct {
-		Active         bool     `json:"active"`
-		Status         string   `json:"Active"`
-		Plan           string   `json:"lifetime"`       // can be null
-		SubscribedAt   string   `json:"subscribedAt"`   // can be null
-		PaymentService string   `json:"paymentService"` // can be null
-		Features       []string `json:"features"`
-	} `json:"subscription"`
-	SubscriptionDescription string `json:"subscriptionDescription"` // can be null
-	Restricted              bool   `json:"restricted"`
-	Anonymous               string `json:"anonymous"` // can be null
-	Home                    bool   `json:"home"`
-	Guest                   bool   `json:"guest"`
-	HomeSize                int64  `json:"homeSize"` // type may be wrong
-	HomeAdmin               bool   `json:"homeAdmin"`
-	MaxHomeSize             int64  `json:"maxHomeSize"` // type may be wrong
-	CertificateVersion      int64  `json:"certificateVersion"`
-	RememberExpiresAt       int64  `json:"rememberExpiresAt"`
+		Active         FlexBool `json:"active" xml:"active,attr"`
+		Status         string   `json:"Active" xml:"status,attr"`
+		Plan           string   `json:"lifetime" xml:"plan,attr"`                 // can be null
+		SubscribedAt   string   `json:"subscribedAt" xml:"subscribedAt,attr"`     // can be null
+		PaymentService string   `json:"paymentService" xml:"paymentService,attr"` // can be null
+		Features       []string `json:"features" xml:"-"`
+	} `json:"subscription" xml:"subscription"`
+	SubscriptionDescription string    `json:"subscriptionDescription" xml:"subscriptionDescription,attr"` // can be null
+	Restricted              FlexBool  `json:"restricted" xml:"restricted,attr"`
+	Anonymous               string    `json:"anonymous" xml:"anonymous,attr"` // can be null
+	Home                    FlexBool  `json:"home" xml:"home,attr"`
+	Guest                   FlexBool  `json:"guest" xml:"guest,attr"`
+	HomeSize                FlexInt   `json:"homeSize" xml:"homeSize,attr"` // type may be wrong
+	HomeAdmin               FlexBool  `json:"homeAdmin" xml:"homeAdmin,attr"`
+	MaxHomeSize             FlexInt   `json:"maxHomeSize" xml:"maxHomeSize,attr"` // type may be wrong
+	CertificateVersion      FlexInt   `json:"certificateVersion" xml:"certificateVersion,attr"`
+	RememberExpiresAt       Timestamp `json:"rememberExpiresAt" xml:"rememberExpiresAt,attr"`
 	Profile                 struct {
-		AutoSelectAudio              bool   `json:"autoSelectAudio"`
-		DefaultAudioLanguage         string `json:"defaultAudioLanguage"`
-		DefaultSubtitleLanguage      string `json:"defaultSubtitleLanguage"`
-		AutoSelectSubtitle           int64  `json:"autoSelectSubtitle"`
-		DefaultSubtitleAccessibility int64  `json:"defaultSubtitleAccessibility"`
-		DefaultSubtitleForced        int64  `json:"defaultSubtitleForced"`
-	} `json:"profile"`
+		AutoSelectAudio              FlexBool `json:"autoSelectAudio" xml:"autoSelectAudio,attr"`
+		DefaultAudioLanguage         string   `json:"defaultAudioLanguage" xml:"defaultAudioLanguage,attr"`
+		DefaultSubtitleLanguage      string   `json:"defaultSubtitleLanguage" xml:"defaultSubtitleLanguage,attr"`
+		AutoSelectSubtitle           FlexInt  `json:"autoSelectSubtitle" xml:"autoSelectSubtitle,attr"`
+		DefaultSubtitleAccessibility FlexInt  `json:"defaultSubtitleAccessibility" xml:"defaultSubtitleAccessibility,attr"`
+		DefaultSubtitleForced        FlexInt  `json:"defaultSubtitleForced" xml:"defaultSubtitleForced,attr"`
+	} `json:"profile" xml:"profile"`
 	Subscriptions []struct {
-		ID       int64  `json:"id"`
-		Mode     string `json:"mode"`
-		RenewsAt string `json:"renewsAt"` // can be null; not sure of type as I have lifetime membership
-		EndsAt   string `json:"endsAt"`   // can be null; not sure of type as I have lifetime membership
-		Type     string `json:"type"`
-		Transfer string `json:"transfer"` // can be null; not sure of type
-		State    string `json:"state"`
-	} `json:"subscriptions"`
-	PastSubscriptions    []string   `json:"pastSubscriptions"`
-	Trials               []string   `json:"trials"`
-	Services             []Services `json:"services"`
-	AdsConsent           string     `json:"adsConsent"`           // can be null
-	AdsConsentSetAt      string     `json:"adsConsentSetAt"`      // can be null
-	AdsConsentReminderAt string     `json:"adsConsentReminderAt"` // can be null
-	ExperimentalFeatures bool       `json:"experimentalFeatures"`
-	TwoFactorEnabled     bool       `json:"twoFactorEnabled"`
-	BackupCodesCreated   bool       `json:"backupCodesCreated"`
+		ID       FlexInt `json:"id" xml:"id,attr"`
+		Mode     string  `json:"mode" xml:"mode,attr"`
+		RenewsAt string  `json:"renewsAt" xml:"renewsAt,attr"` // can be null; not sure of type as I have lifetime membership
+		EndsAt   string  `json:"endsAt" xml:"endsAt,attr"`     // can be null; not sure of type as I have lifetime membership
+		Type     string  `json:"type" xml:"type,attr"`
+		Transfer string  `json:"transfer" xml:"transfer,attr"` // can be null; not sure of type
+		State    string  `json:"state" xml:"state,attr"`
+	} `json:"subscriptions" xml:"subscriptions"`
+	PastSubscriptions    []string   `json:"pastSubscriptions" xml:"-"`
+	Trials               []string   `json:"trials" xml:"-"`
+	Services             []Services `json:"services" xml:"services"`
+	AdsConsent           string     `json:"adsConsent" xml:"adsConsent,attr"`                     // can be null
+	AdsConsentSetAt      string     `json:"adsConsentSetAt" xml:"adsConsentSetAt,attr"`           // can be null
+	AdsConsentReminderAt string     `json:"adsConsentReminderAt" xml:"adsConsentReminderAt,attr"` // can be null
+	ExperimentalFeatures FlexBool   `json:"experimentalFeatures" xml:"experimentalFeatures,attr"`
+	TwoFactorEnabled     FlexBool   `json:"twoFactorEnabled" xml:"twoFactorEnabled,attr"`
+	BackupCodesCreated   FlexBool   `json:"backupCodesCreated" xml:"backupCodesCreated,attr"`
 	// Roles                struct {
 	// 	Roles []string `json:"roles"`
 	// } `json:"roles"`
-	Entitlements []string `json:"entitlements"`
+	Entitlements []string `json:"entitlements" xml:"-"`
 	// ConfirmedAt  string      `json:"confirmedAt"`
 	// ForumID    json.Number `json:"forumId"`
 	// RememberMe bool   `json:"rememberMe"`
-	Title string `json:"title"`
+	Title string `json:"title" xml:"title,attr"`
 }
 
 type Services struct {
-	Identifier string `json:"identifier"`
-	Endpoint   string `json:"endpoint"`
-	Token      string `json:"token"`
-	Status     string `json:"status"`
+	Identifier string `json:"identifier" xml:"identifier,attr"`
+	Endpoint   string `json:"endpoint" xml:"endpoint,attr"`
+	Token      string `json:"token" xml:"token,attr"`
+	Status     string `json:"status" xml:"status,attr"`
 }
 
 // User plex server user. only difference is id is a string
 type User struct {
 	// ID is an int when signing in to Plex.tv but a string when access own server
-	ID                  string `json:"id"`
-	UUID                string `json:"uuid"`
-	Email               string `json:"email"`
-	JoinedAt            string `json:"joined_at"`
-	Username            string `json:"username"`
-	Thumb               string `json:"thumb"`
-	HasPassword         bool   `json:"hasPassword"`
-	AuthToken           string `json:"authToken"`
-	AuthenticationToken string `json:"authenticationToken"`
+	ID                  FlexString `json:"id" xml:"id,attr"`
+	UUID                string     `json:"uuid" xml:"uuid,attr"`
+	Email               string     `json:"email" xml:"email,attr"`
+	JoinedAt            string     `json:"joined_at" xml:"joined_at,attr"`
+	Username            string     `json:"username" xml:"username,attr"`
+	Thumb               string     `json:"thumb" xml:"thumb,attr"`
+	HasPassword         FlexBool   `json:"hasPassword" xml:"hasPassword,attr"`
+	AuthToken           string     `json:"authToken" xml:"authToken,attr"`
+	AuthenticationToken string     `json:"authenticationToken" xml:"authenticationToken,attr"`
 	Subscription        struct {
-		Active   bool     `json:"active"`
-		Status   string   `json:"Active"`
-		Plan     string   `json:"lifetime"`
-		Features []string `json:"features"`
-	} `json:"subscription"`
+		Active   FlexBool `json:"active" xml:"active,attr"`
+		Status   string   `json:"Active" xml:"status,attr"`
+		Plan     string   `json:"lifetime" xml:"plan,attr"`
+		Features []string `json:"features" xml:"-"`
+	} `json:"subscription" xml:"subscription"`
 	Roles struct {
-		Roles []string `json:"roles"`
-	} `json:"roles"`
-	Entitlements []string `json:"entitlements"`
-	ConfirmedAt  string   `json:"confirmedAt"`
-	ForumID      string   `json:"forumId"`
-	RememberMe   bool     `json:"rememberMe"`
-	Title        string   `json:"title"`
+		Roles []string `json:"roles" xml:"-"`
+	} `json:"roles" xml:"roles"`
+	Entitlements []string `json:"entitlements" xml:"-"`
+	ConfirmedAt  string   `json:"confirmedAt" xml:"confirmedAt,attr"`
+	ForumID      string   `json:"forumId" xml:"forumId,attr"`
+	RememberMe   FlexBool `json:"rememberMe" xml:"rememberMe,attr"`
+	Title        string   `json:"title" xml:"title,attr"`
 }
 
 // SignInResponse response from plex.tv sign in
@@ -615,59 +616,59 @@ type SignInResponse UserPlexTV
 
 // ServerInfo is the result of the https://plex.tv/api/servers endpoint
 type ServerInfo struct {
-	XMLName           xml.Name `xml:"MediaContainer"`
-	FriendlyName      string   `xml:"friendlyName,attr"`
-	Identifier        string   `xml:"identifier,attr"`
-	MachineIdentifier string   `xml:"machineIdentifier,attr"`
-	Size              int      `xml:"size,attr"`
+	XMLName           xml.Name `json:"-" xml:"MediaContainer"`
+	FriendlyName      string   `json:"friendlyName" xml:"friendlyName,attr"`
+	Identifier        string   `json:"identifier" xml:"identifier,attr"`
+	MachineIdentifier string   `json:"machineIdentifier" xml:"machineIdentifier,attr"`
+	Size              FlexInt  `json:"size" xml:"size,attr"`
 	Server            []struct {
-		AccessToken       string `xml:"accessToken,attr"`
-		Name              string `xml:"name,attr"`
-		Address           string `xml:"address,attr"`
-		Port              string `xml:"port,attr"`
-		Version           string `xml:"version,attr"`
-		Scheme            string `xml:"scheme,attr"`
-		Host              string `xml:"host,attr"`
-		LocalAddresses    string `xml:"localAddresses,attr"`
-		MachineIdentifier string `xml:"machineIdentifier,attr"`
-		CreatedAt         string `xml:"createdAt,attr"`
-		UpdatedAt         string `xml:"updatedAt,attr"`
-		Owned             string `xml:"owned,attr"`
-		Synced            string `xml:"synced,attr"`
-	} `xml:"Server"`
+		AccessToken       string    `json:"accessToken" xml:"accessToken,attr"`
+		Name              string    `json:"name" xml:"name,attr"`
+		Address           string    `json:"address" xml:"address,attr"`
+		Port              string    `json:"port" xml:"port,attr"`
+		Version           string    `json:"version" xml:"version,attr"`
+		Scheme            string    `json:"scheme" xml:"scheme,attr"`
+		Host              string    `json:"host" xml:"host,attr"`
+		LocalAddresses    string    `json:"localAddresses" xml:"localAddresses,attr"`
+		MachineIdentifier string    `json:"machineIdentifier" xml:"machineIdentifier,attr"`
+		CreatedAt         Timestamp `json:"createdAt" xml:"createdAt,attr"`
+		UpdatedAt         Timestamp `json:"updatedAt" xml:"updatedAt,attr"`
+		Owned             string    `json:"owned" xml:"owned,attr"`
+		Synced            string    `json:"synced" xml:"synced,attr"`
+	} `json:"Server" xml:"Server"`
 }
 
 // SectionIDResponse the section id (or library id) of your server
 // useful when inviting a user to the server
 type SectionIDResponse struct {
-	XMLName           xml.Name `xml:"MediaContainer"`
-	FriendlyName      string   `xml:"friendlyName,attr"`
-	Identifier        string   `xml:"identifier,attr"`
-	MachineIdentifier string   `xml:"machineIdentifier,attr"`
-	Size              int      `xml:"size,attr"`
+	XMLName           xml.Name `json:"-" xml:"MediaContainer"`
+	FriendlyName      string   `json:"friendlyName" xml:"friendlyName,attr"`
+	Identifier        string   `json:"identifier" xml:"identifier,attr"`
+	MachineIdentifier string   `json:"machineIdentifier" xml:"machineIdentifier,attr"`
+	Size              FlexInt  `json:"size" xml:"size,attr"`
 	Server            []struct {
-		Name              string           `xml:"name,attr"`
-		Address           string           `xml:"address,attr"`
-		Port              string           `xml:"port,attr"`
-		Version           string           `xml:"version,attr"`
-		Scheme            string           `xml:"scheme,attr"`
-		Host              string           `xml:"host,attr"`
-		LocalAddresses    string           `xml:"localAddresses,attr"`
-		MachineIdentifier string           `xml:"machineIdentifier,attr"`
-		CreatedAt         int              `xml:"createdAt,attr"`
-		UpdatedAt         int              `xml:"updatedAt,attr"`
-		Owned             int              `xml:"owned,attr"`
-		Synced            string           `xml:"synced,attr"`
-		Section           []ServerSections `xml:"Section"`
-	} `xml:"Server"`
+		Name              string           `json:"name" xml:"name,attr"`
+		Address           string           `json:"address" xml:"address,attr"`
+		Port              string           `json:"port" xml:"port,attr"`
+		Version           string           `json:"version" xml:"version,attr"`
+		Scheme            string           `json:"scheme" xml:"scheme,attr"`
+		Host              string           `json:"host" xml:"host,attr"`
+		LocalAddresses    string           `json:"localAddresses" xml:"localAddresses,attr"`
+		MachineIdentifier string           `json:"machineIdentifier" xml:"machineIdentifier,attr"`
+		CreatedAt         Timestamp        `json:"createdAt" xml:"createdAt,attr"`
+		UpdatedAt         Timestamp        `json:"updatedAt" xml:"updatedAt,attr"`
+		Owned             FlexInt          `json:"owned" xml:"owned,attr"`
+		Synced            string           `json:"synced" xml:"synced,attr"`
+		Section           []ServerSections `json:"Section" xml:"Section"`
+	} `json:"Server" xml:"Server"`
 }
 
 // ServerSections contains information of your library sections
 type ServerSections struct {
-	ID    int    `xml:"id,attr"`
-	Key   string `xml:"key,attr"`
-	Type  string `xml:"type,attr"`
-	Title string `xml:"title,attr"`
+	ID    FlexInt `json:"id" xml:"id,attr"`
+	Key   string  `json:"key" xml:"key,attr"`
+	Type  string  `json:"type" xml:"type,attr"`
+	Title string  `json:"title" xml:"title,attr"`
 }
 
 // LibraryLabels are the existing labels set on your server
@@ -699,6 +700,7 @@ type headers struct {
 	Product                string
 	Version                string
 	Device                 string
+	DeviceName             string
 	ContainerSize          string
 	ContainerStart         string
 	Token                  string
@@ -717,122 +719,122 @@ type request struct {
 // TranscodeSessionsResponse is the result for transcode session endpoint /transcode/sessions
 type TranscodeSessionsResponse struct {
 	Children []struct {
-		ElementType   string  `json:"_elementType"`
-		AudioChannels int     `json:"audioChannels"`
-		AudioCodec    string  `json:"audioCodec"`
-		AudioDecision string  `json:"audioDecision"`
-		Container     string  `json:"container"`
-		Context       string  `json:"context"`
-		Duration      int     `json:"duration"`
-		Height        int     `json:"height"`
-		Key           string  `json:"key"`
-		Progress      float64 `json:"progress"`
-		Protocol      string  `json:"protocol"`
-		Remaining     int     `json:"remaining"`
-		Speed         float64 `json:"speed"`
-		Throttled     bool    `json:"throttled"`
-		VideoCodec    string  `json:"videoCodec"`
-		VideoDecision string  `json:"videoDecision"`
-		Width         int     `json:"width"`
+		ElementType   string    `json:"_elementType"`
+		AudioChannels FlexInt   `json:"audioChannels"`
+		AudioCodec    string    `json:"audioCodec"`
+		AudioDecision string    `json:"audioDecision"`
+		Container     string    `json:"container"`
+		Context       string    `json:"context"`
+		Duration      Millis    `json:"duration"`
+		Height        FlexInt   `json:"height"`
+		Key           string    `json:"key"`
+		Progress      FlexFloat `json:"progress"`
+		Protocol      string    `json:"protocol"`
+		Remaining     FlexInt   `json:"remaining"`
+		Speed         FlexFloat `json:"speed"`
+		Throttled     FlexBool  `json:"throttled"`
+		VideoCodec    string    `json:"videoCodec"`
+		VideoDecision string    `json:"videoDecision"`
+		Width         FlexInt   `json:"width"`
 	} `json:"_children"`
 	ElementType string `json:"_elementType"`
 }
 
 // Stream ...
 type Stream struct {
-	AlbumGain          string      `json:"albumGain"`
-	AlbumPeak          string      `json:"albumPeak"`
-	AlbumRange         string      `json:"albumRange"`
-	Anamorphic         bool        `json:"anamorphic"`
-	AudioChannelLayout string      `json:"audioChannelLayout"`
-	BitDepth           int         `json:"bitDepth"`
-	Bitrate            int         `json:"bitrate"`
-	BitrateMode        string      `json:"bitrateMode"`
-	Cabac              string      `json:"cabac"`
-	Channels           int         `json:"channels"`
-	ChromaLocation     string      `json:"chromaLocation"`
-	ChromaSubsampling  string      `json:"chromaSubsampling"`
-	Codec              string      `json:"codec"`
-	CodecID            string      `json:"codecID"`
-	ColorRange         string      `json:"colorRange"`
-	ColorSpace         string      `json:"colorSpace"`
-	Default            bool        `json:"default"`
-	DisplayTitle       string      `json:"displayTitle"`
-	Duration           string      `json:"duration"`
-	FrameRate          float64     `json:"frameRate"`
-	FrameRateMode      string      `json:"frameRateMode"`
-	Gain               string      `json:"gain"`
-	HasScalingMatrix   bool        `json:"hasScalingMatrix"`
-	Height             int         `json:"height"`
-	ID                 json.Number `json:"id"`
-	Index              int         `json:"index"`
-	Language           string      `json:"language"`
-	LanguageCode       string      `json:"languageCode"`
-	Level              int         `json:"level"`
-	Location           string      `json:"location"`
-	Loudness           string      `json:"loudness"`
-	Lra                string      `json:"lra"`
-	Peak               string      `json:"peak"`
-	PixelAspectRatio   string      `json:"pixelAspectRatio"`
-	PixelFormat        string      `json:"pixelFormat"`
-	Profile            string      `json:"profile"`
-	RefFrames          int         `json:"refFrames"`
-	SamplingRate       int         `json:"samplingRate"`
-	ScanType           string      `json:"scanType"`
-	Selected           bool        `json:"selected"`
-	StreamIdentifier   string      `json:"streamIdentifier"`
-	StreamType         int         `json:"streamType"`
-	Width              int         `json:"width"`
+	AlbumGain          string    `json:"albumGain" xml:"albumGain,attr"`
+	AlbumPeak          string    `json:"albumPeak" xml:"albumPeak,attr"`
+	AlbumRange         string    `json:"albumRange" xml:"albumRange,attr"`
+	Anamorphic         FlexBool  `json:"anamorphic" xml:"anamorphic,attr"`
+	AudioChannelLayout string    `json:"audioChannelLayout" xml:"audioChannelLayout,attr"`
+	BitDepth           FlexInt   `json:"bitDepth" xml:"bitDepth,attr"`
+	Bitrate            FlexInt   `json:"bitrate" xml:"bitrate,attr"`
+	BitrateMode        string    `json:"bitrateMode" xml:"bitrateMode,attr"`
+	Cabac              string    `json:"cabac" xml:"cabac,attr"`
+	Channels           FlexInt   `json:"channels" xml:"channels,attr"`
+	ChromaLocation     string    `json:"chromaLocation" xml:"chromaLocation,attr"`
+	ChromaSubsampling  string    `json:"chromaSubsampling" xml:"chromaSubsampling,attr"`
+	Codec              string    `json:"codec" xml:"codec,attr"`
+	CodecID            string    `json:"codecID" xml:"codecID,attr"`
+	ColorRange         string    `json:"colorRange" xml:"colorRange,attr"`
+	ColorSpace         string    `json:"colorSpace" xml:"colorSpace,attr"`
+	Default            FlexBool  `json:"default" xml:"default,attr"`
+	DisplayTitle       string    `json:"displayTitle" xml:"displayTitle,attr"`
+	Duration           string    `json:"duration" xml:"duration,attr"`
+	FrameRate          FlexFloat `json:"frameRate" xml:"frameRate,attr"`
+	FrameRateMode      string    `json:"frameRateMode" xml:"frameRateMode,attr"`
+	Gain               string    `json:"gain" xml:"gain,attr"`
+	HasScalingMatrix   FlexBool  `json:"hasScalingMatrix" xml:"hasScalingMatrix,attr"`
+	Height             FlexInt   `json:"height" xml:"height,attr"`
+	ID                 FlexInt   `json:"id" xml:"id,attr"`
+	Index              FlexInt   `json:"index" xml:"index,attr"`
+	Language           string    `json:"language" xml:"language,attr"`
+	LanguageCode       string    `json:"languageCode" xml:"languageCode,attr"`
+	Level              FlexInt   `json:"level" xml:"level,attr"`
+	Location           string    `json:"location" xml:"location,attr"`
+	Loudness           string    `json:"loudness" xml:"loudness,attr"`
+	Lra                string    `json:"lra" xml:"lra,attr"`
+	Peak               string    `json:"peak" xml:"peak,attr"`
+	PixelAspectRatio   string    `json:"pixelAspectRatio" xml:"pixelAspectRatio,attr"`
+	PixelFormat        string    `json:"pixelFormat" xml:"pixelFormat,attr"`
+	Profile            string    `json:"profile" xml:"profile,attr"`
+	RefFrames          FlexInt   `json:"refFrames" xml:"refFrames,attr"`
+	SamplingRate       FlexInt   `json:"samplingRate" xml:"samplingRate,attr"`
+	ScanType           string    `json:"scanType" xml:"scanType,attr"`
+	Selected           FlexBool  `json:"selected" xml:"selected,attr"`
+	StreamIdentifier   string    `json:"streamIdentifier" xml:"streamIdentifier,attr"`
+	StreamType         FlexInt   `json:"streamType" xml:"streamType,attr"`
+	Width              FlexInt   `json:"width" xml:"width,attr"`
 }
 
 // Part ...
 type Part struct {
-	AudioProfile          string      `json:"audioProfile"`
-	Container             string      `json:"container"`
-	Decision              string      `json:"decision"`
-	Duration              int         `json:"duration"`
-	File                  string      `json:"file"`
-	Has64bitOffsets       bool        `json:"has64bitOffsets"`
-	HasThumbnail          string      `json:"hasThumbnail"`
-	ID                    json.Number `json:"id"`
-	Key                   string      `json:"key"`
-	OptimizedForStreaming bool        `json:"optimizedForStreaming"`
-	Selected              bool        `json:"selected"`
-	Size                  int         `json:"size"`
-	Stream                []Stream    `json:"Stream"`
-	VideoProfile          string      `json:"videoProfile"`
+	AudioProfile          string   `json:"audioProfile" xml:"audioProfile,attr"`
+	Container             string   `json:"container" xml:"container,attr"`
+	Decision              string   `json:"decision" xml:"decision,attr"`
+	Duration              Millis   `json:"duration" xml:"duration,attr"`
+	File                  string   `json:"file" xml:"file,attr"`
+	Has64bitOffsets       FlexBool `json:"has64bitOffsets" xml:"has64bitOffsets,attr"`
+	HasThumbnail          string   `json:"hasThumbnail" xml:"hasThumbnail,attr"`
+	ID                    FlexInt  `json:"id" xml:"id,attr"`
+	Key                   string   `json:"key" xml:"key,attr"`
+	OptimizedForStreaming FlexBool `json:"optimizedForStreaming" xml:"optimizedForStreaming,attr"`
+	Selected              FlexBool `json:"selected" xml:"selected,attr"`
+	Size                  FlexInt  `json:"size" xml:"size,attr"`
+	Stream                []Stream `json:"Stream" xml:"Stream"`
+	VideoProfile          string   `json:"videoProfile" xml:"videoProfile,attr"`
 }
 
 // Player ...
 type Player struct {
-	Address             string `json:"address"`
-	Device              string `json:"device"`
-	Local               bool   `json:"local"`
-	MachineIdentifier   string `json:"machineIdentifier"`
-	Model               string `json:"model"`
-	Platform            string `json:"platform"`
-	PlatformVersion     string `json:"platformVersion"`
-	Product             string `json:"product"`
-	Profile             string `json:"profile"`
-	RemotePublicAddress string `json:"remotePublicAddress"`
-	State               string `json:"state"`
-	Title               string `json:"title"`
-	UserID              int    `json:"userID"`
-	Vendor              string `json:"vendor"`
-	Version             string `json:"version"`
+	Address             string   `json:"address" xml:"address,attr"`
+	Device              string   `json:"device" xml:"device,attr"`
+	Local               FlexBool `json:"local" xml:"local,attr"`
+	MachineIdentifier   string   `json:"machineIdentifier" xml:"machineIdentifier,attr"`
+	Model               string   `json:"model" xml:"model,attr"`
+	Platform            string   `json:"platform" xml:"platform,attr"`
+	PlatformVersion     string   `json:"platformVersion" xml:"platformVersion,attr"`
+	Product             string   `json:"product" xml:"product,attr"`
+	Profile             string   `json:"profile" xml:"profile,attr"`
+	RemotePublicAddress string   `json:"remotePublicAddress" xml:"remotePublicAddress,attr"`
+	State               string   `json:"state" xml:"state,attr"`
+	Title               string   `json:"title" xml:"title,attr"`
+	UserID              FlexInt  `json:"userID" xml:"userID,attr"`
+	Vendor              string   `json:"vendor" xml:"vendor,attr"`
+	Version             string   `json:"version" xml:"version,attr"`
 }
 
 // Session ...
 type Session struct {
-	Bandwidth int    `json:"bandwidth"`
-	ID        string `json:"id"`
-	Location  string `json:"location"`
+	Bandwidth FlexInt `json:"bandwidth" xml:"bandwidth,attr"`
+	ID        string  `json:"id" xml:"id,attr"`
+	Location  string  `json:"location" xml:"location,attr"`
 }
 
 // CurrentSessions metadata of users consuming media
 type CurrentSessions struct {
 	MediaContainer struct {
-		Metadata []Metadata `json:"Metadata"`
-		Size     int        `json:"size"`
-	} `json:"MediaContainer"`
+		Metadata []Metadata `json:"Metadata" xml:",any"`
+		Size     FlexInt    `json:"size" xml:"size,attr"`
+	} `json:"MediaContainer" xml:"MediaContainer"`
 }
@@ -0,0 +1,124 @@
+package plex
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Option configures a plex instance created by New, SignIn, RequestPIN or CheckPIN
+type Option func(p *Plex)
+
+// WithProduct sets the X-Plex-Product header, the application name shown in the plex.tv device list
+func WithProduct(product string) Option {
+	return func(p *Plex) {
+		p.Headers.Product = product
+	}
+}
+
+// WithVersion sets the X-Plex-Version header, the version of your application
+func WithVersion(version string) Option {
+	return func(p *Plex) {
+		p.Headers.Version = version
+	}
+}
+
+// WithDevice sets the X-Plex-Device header, the kind of device (i.e. "Linux", "iPhone")
+func WithDevice(device string) Option {
+	return func(p *Plex) {
+		p.Headers.Device = device
+	}
+}
+
+// WithDeviceName sets the X-Plex-Device-Name header, the friendly name of the device shown in the plex.tv device list
+func WithDeviceName(name string) Option {
+	return func(p *Plex) {
+		p.Headers.DeviceName = name
+	}
+}
+
+// WithPlatform sets the X-Plex-Platform and X-Plex-Platform-Version headers
+func WithPlatform(platform, version string) Option {
+	return func(p *Plex) {
+		p.Headers.Platform = platform
+		p.Headers.PlatformVersion = version
+	}
+}
+
+// WithClientIdentifier sets the X-Plex-Client-Identifier header. Plex uses it to tell devices apart,
+// so it should be unique to your installation and persisted between runs.
+// When omitted an identifier is derived from the host name and product
+func WithClientIdentifier(id string) Option {
+	return func(p *Plex) {
+		p.ClientIdentifier = id
+	}
+}
+
+// WithPlexTVURL sets the base url used for plex.tv requests, i.e. to point the client at a fake server in tests
+func WithPlexTVURL(plexTVURL string) Option {
+	return func(p *Plex) {
+		p.PlexTVURL = plexTVURL
+	}
+}
+
+// WithHTTPClient sets the http client used for api requests. nil keeps the default client
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Plex) {
+		if client != nil {
+			p.HTTPClient = *client
+		}
+	}
+}
+
+// WithDownloadClient sets the http client used by Download. It should not have a short timeout.
+// nil keeps the default client
+func WithDownloadClient(client *http.Client) Option {
+	return func(p *Plex) {
+		if client != nil {
+			p.DownloadClient = *client
+		}
+	}
+}
+
+// WithTransport sets the transport of both the api and download http clients
+func WithTransport(transport http.RoundTripper) Option {
+	return func(p *Plex) {
+		p.HTTPClient.Transport = transport
+		p.DownloadClient.Transport = transport
+	}
+}
+
+// WithTimeout sets the timeout of api requests. It defaults to 3 seconds, 0 means no timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(p *Plex) {
+		p.HTTPClient.Timeout = timeout
+	}
+}
+
+// WithMiddleware appends middleware to the request pipeline. See Plex.Use
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(p *Plex) {
+		p.Use(middleware...)
+	}
+}
+
+// WithRetryPolicy retries idempotent requests on transient errors
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(p *Plex) {
+		p.RetryPolicy = policy
+	}
+}
+
+// WithDryRun logs every request that changes the server, anything but GET and HEAD, to w
+// instead of sending it, so the changes a script would make can be reviewed first.
+// Those requests fail with ErrDryRun. A nil w logs to stderr
+func WithDryRun(w io.Writer) Option {
+	return func(p *Plex) {
+		if w == nil {
+			w = os.Stderr
+		}
+
+		p.DryRun = w
+	}
+}
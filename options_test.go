@@ -0,0 +1,85 @@
+package plex
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewOptions(t *testing.T) {
+	var got http.Header
+
+	capture := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			got = req.Header
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"MediaContainer":{}}`)),
+			}, nil
+		})
+	}
+
+	p, err := New("http://localhost:32400", "token",
+		WithProduct("My Tool"),
+		WithVersion("1.2.3"),
+		WithDeviceName("nas"),
+		WithClientIdentifier("my-tool-1234"),
+		WithTimeout(time.Minute),
+		WithMiddleware(capture),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.HTTPClient.Timeout != time.Minute {
+		t.Errorf("expected a timeout of 1m, got %v", p.HTTPClient.Timeout)
+	}
+
+	if _, err := p.GetSessions(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"X-Plex-Product":           "My Tool",
+		"X-Plex-Version":           "1.2.3",
+		"X-Plex-Device-Name":       "nas",
+		"X-Plex-Client-Identifier": "my-tool-1234",
+		"X-Plex-Token":             "token",
+	}
+
+	for header, value := range expected {
+		if got.Get(header) != value {
+			t.Errorf("expected %s to be %q, got %q", header, value, got.Get(header))
+		}
+	}
+}
+
+func TestDefaultClientIdentifier(t *testing.T) {
+	a, _ := New("http://localhost:32400", "token")
+	b, _ := New("http://localhost:32400", "token")
+	c, _ := New("http://localhost:32400", "token", WithProduct("Another Tool"))
+
+	if a.ClientIdentifier == "" || a.ClientIdentifier != b.ClientIdentifier {
+		t.Errorf("expected a stable client identifier, got %q and %q", a.ClientIdentifier, b.ClientIdentifier)
+	}
+
+	if a.ClientIdentifier == c.ClientIdentifier {
+		t.Error("expected products to have different client identifiers")
+	}
+}
+
+func TestNilClientOptions(t *testing.T) {
+	p, err := New("http://localhost:32400", "token", WithHTTPClient(nil), WithDownloadClient(nil))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.HTTPClient.Timeout != 3*time.Second || p.DownloadClient.Timeout != 0 {
+		t.Errorf("expected the default clients, got %+v and %+v", p.HTTPClient, p.DownloadClient)
+	}
+}
@@ -0,0 +1,168 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// DefaultPageSize is the page size used by iterators when none is given
+const DefaultPageSize = 100
+
+// getPage sends a GET request for a single page of a container endpoint
+// via the X-Plex-Container-Start and X-Plex-Container-Size headers
+func (p *Plex) getPage(ctx context.Context, query string, start, size int) (*http.Response, error) {
+	newHeaders := p.Headers
+
+	newHeaders.ContainerStart = strconv.Itoa(start)
+	newHeaders.ContainerSize = strconv.Itoa(size)
+
+	return p.get(ctx, query, newHeaders)
+}
+
+// GetLibraryContentPage retrieves a single page of the content inside a library.
+// MediaContainer.TotalSize holds the number of items in the whole library
+func (p *Plex) GetLibraryContentPage(sectionKey, filter string, start, size int) (SearchResults, error) {
+	return p.GetLibraryContentPageCtx(context.Background(), sectionKey, filter, start, size)
+}
+
+// GetLibraryContentPageCtx is like GetLibraryContentPage but carries ctx for cancellation and deadlines
+func (p *Plex) GetLibraryContentPageCtx(ctx context.Context, sectionKey, filter string, start, size int) (SearchResults, error) {
+	query := fmt.Sprintf("%s/library/sections/%s/all%s", p.URL, sectionKey, filter)
+
+	resp, err := p.getPage(ctx, query, start, size)
+
+	if err != nil {
+		return SearchResults{}, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return SearchResults{}, newAPIError(resp)
+	}
+
+	var results SearchResults
+
+	if err := decodeResponse(resp, &results); err != nil {
+		return SearchResults{}, err
+	}
+
+	return results, nil
+}
+
+// LibraryContentIterator lazily pages through the content of a library
+//
+//	it := plexConn.IterateLibraryContent("1", "", 200)
+//
+//	for it.Next() {
+//		fmt.Println(it.Item().Title)
+//	}
+//
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type LibraryContentIterator struct {
+	p          *Plex
+	ctx        context.Context
+	sectionKey string
+	filter     string
+	pageSize   int
+
+	page      []Metadata
+	index     int
+	start     int
+	totalSize int
+	fetched   bool
+	lastPage  bool
+	err       error
+}
+
+// IterateLibraryContent returns an iterator over the content of a library that fetches
+// pageSize items at a time. A pageSize of 0 uses DefaultPageSize
+func (p *Plex) IterateLibraryContent(sectionKey, filter string, pageSize int) *LibraryContentIterator {
+	return p.IterateLibraryContentCtx(context.Background(), sectionKey, filter, pageSize)
+}
+
+// IterateLibraryContentCtx is like IterateLibraryContent but carries ctx for cancellation and deadlines
+func (p *Plex) IterateLibraryContentCtx(ctx context.Context, sectionKey, filter string, pageSize int) *LibraryContentIterator {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return &LibraryContentIterator{
+		p:          p,
+		ctx:        ctx,
+		sectionKey: sectionKey,
+		filter:     filter,
+		pageSize:   pageSize,
+		index:      -1,
+	}
+}
+
+// Next advances the iterator to the next item, fetching the next page when needed.
+// It returns false when there are no more items or an error occurred
+func (it *LibraryContentIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.index++
+
+	if it.index < len(it.page) {
+		return true
+	}
+
+	if it.lastPage {
+		return false
+	}
+
+	results, err := it.p.GetLibraryContentPageCtx(it.ctx, it.sectionKey, it.filter, it.start, it.pageSize)
+
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.fetched = true
+	it.page = results.MediaContainer.Metadata
+	it.index = 0
+	it.start += len(it.page)
+	it.totalSize = results.MediaContainer.TotalSize.Int()
+
+	// servers that ignore paging return everything at once without a total size
+	if len(it.page) < it.pageSize || it.totalSize == 0 || it.start >= it.totalSize {
+		it.lastPage = true
+	}
+
+	return len(it.page) > 0
+}
+
+// Item returns the current item. Only valid after a call to Next that returned true
+func (it *LibraryContentIterator) Item() Metadata {
+	if it.index < 0 || it.index >= len(it.page) {
+		return Metadata{}
+	}
+
+	return it.page[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *LibraryContentIterator) Err() error {
+	return it.err
+}
+
+// TotalSize returns the number of items in the library as reported by the server.
+// It is 0 until the first page has been fetched
+func (it *LibraryContentIterator) TotalSize() int {
+	if !it.fetched {
+		return 0
+	}
+
+	if it.totalSize == 0 {
+		return len(it.page)
+	}
+
+	return it.totalSize
+}
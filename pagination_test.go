@@ -0,0 +1,60 @@
+package plex
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestIterateLibraryContent(t *testing.T) {
+	const totalSize = 5
+
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		start, _ := strconv.Atoi(r.Header.Get("X-Plex-Container-Start"))
+		size, _ := strconv.Atoi(r.Header.Get("X-Plex-Container-Size"))
+
+		var items []string
+
+		for ii := start; ii < start+size && ii < totalSize; ii++ {
+			items = append(items, fmt.Sprintf(`{"ratingKey":"%d"}`, ii))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"MediaContainer":{"size":%d,"totalSize":%d,"offset":%d,"Metadata":[%s]}}`, len(items), totalSize, start, strings.Join(items, ","))
+	}))
+
+	defer server.Close()
+
+	_plex := &Plex{URL: server.URL}
+
+	it := _plex.IterateLibraryContent("1", "", 2)
+
+	var keys []string
+
+	for it.Next() {
+		keys = append(keys, it.Item().RatingKey)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(keys, ","); got != "0,1,2,3,4" {
+		t.Errorf("unexpected items: %s", got)
+	}
+
+	if it.TotalSize() != totalSize {
+		t.Errorf("expected total size %d, got %d", totalSize, it.TotalSize())
+	}
+
+	if requests != 3 {
+		t.Errorf("expected 3 page requests, got %d", requests)
+	}
+}
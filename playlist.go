@@ -0,0 +1,301 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Playlist is a playlist on the server
+type Playlist struct {
+	RatingKey string `json:"ratingKey" xml:"ratingKey,attr"`
+	Key       string `json:"key" xml:"key,attr"`
+	GUID      string `json:"guid" xml:"guid,attr"`
+	Type      string `json:"type" xml:"type,attr"`
+	Title     string `json:"title" xml:"title,attr"`
+	Summary   string `json:"summary" xml:"summary,attr"`
+	// Smart playlists are filled by a library filter instead of a list of items
+	Smart FlexBool `json:"smart" xml:"smart,attr"`
+	// PlaylistType is video, audio or photo
+	PlaylistType string    `json:"playlistType" xml:"playlistType,attr"`
+	Composite    string    `json:"composite" xml:"composite,attr"`
+	Duration     Millis    `json:"duration" xml:"duration,attr"`
+	LeafCount    FlexInt   `json:"leafCount" xml:"leafCount,attr"`
+	AddedAt      Timestamp `json:"addedAt" xml:"addedAt,attr"`
+	UpdatedAt    Timestamp `json:"updatedAt" xml:"updatedAt,attr"`
+}
+
+type playlistsResponse struct {
+	MediaContainer struct {
+		Metadata []Playlist `json:"Metadata" xml:",any"`
+	} `json:"MediaContainer" xml:"MediaContainer"`
+}
+
+// CreatePlaylistParams describe a new playlist. A playlist is smart when SectionKey is set
+type CreatePlaylistParams struct {
+	Title string
+	// Type is video, audio or photo
+	Type string
+	// RatingKeys are the items of a regular playlist, in order
+	RatingKeys []string
+	// SectionKey and Filter fill a smart playlist with the content of the library matching Filter,
+	// i.e. plex.NewLibraryQuery().Type("episode").Unwatched().Encode()
+	SectionKey string
+	Filter     string
+}
+
+// machineID returns the machine identifier of the server, asking /identity when MachineIdentifier is not set
+func (p *Plex) machineID(ctx context.Context) (string, error) {
+	if p.MachineIdentifier != "" {
+		return p.MachineIdentifier, nil
+	}
+
+	identity, err := p.GetServerIdentityCtx(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	if identity.MachineIdentifier == "" {
+		return "", fmt.Errorf(ErrorCommon, "server did not report a machine identifier")
+	}
+
+	return identity.MachineIdentifier, nil
+}
+
+// libraryURI returns the uri playlists use to point at content of the server, i.e.
+// server://{machineID}/com.plexapp.plugins.library/library/metadata/1,2
+func (p *Plex) libraryURI(ctx context.Context, path string) (string, error) {
+	machineID, err := p.machineID(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("server://%s/%s%s", machineID, libraryIdentifier, path), nil
+}
+
+// GetPlaylists lists the playlists of the server. playlistType is video, audio or photo, or empty for every type
+func (p *Plex) GetPlaylists(playlistType string) ([]Playlist, error) {
+	return p.GetPlaylistsCtx(context.Background(), playlistType)
+}
+
+// GetPlaylistsCtx is like GetPlaylists but carries ctx for cancellation and deadlines
+func (p *Plex) GetPlaylistsCtx(ctx context.Context, playlistType string) ([]Playlist, error) {
+	query := p.URL + "/playlists"
+
+	if playlistType != "" {
+		query += "?playlistType=" + url.QueryEscape(playlistType)
+	}
+
+	return p.getPlaylists(ctx, http.MethodGet, query)
+}
+
+func (p *Plex) getPlaylists(ctx context.Context, method, query string) ([]Playlist, error) {
+	resp, err := p.do(ctx, &p.HTTPClient, method, query, nil, p.Headers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var result playlistsResponse
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.MediaContainer.Metadata, nil
+}
+
+// GetPlaylistItems lists the items of a playlist. Each item carries the PlaylistItemID
+// used to remove or move it
+func (p *Plex) GetPlaylistItems(ratingKey string) (SearchResults, error) {
+	return p.GetPlaylistItemsCtx(context.Background(), ratingKey)
+}
+
+// GetPlaylistItemsCtx is like GetPlaylistItems but carries ctx for cancellation and deadlines
+func (p *Plex) GetPlaylistItemsCtx(ctx context.Context, ratingKey string) (SearchResults, error) {
+	if ratingKey == "" {
+		return SearchResults{}, fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	resp, err := p.get(ctx, fmt.Sprintf("%s/playlists/%s/items", p.URL, ratingKey), p.Headers)
+
+	if err != nil {
+		return SearchResults{}, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return SearchResults{}, newAPIError(resp)
+	}
+
+	var results SearchResults
+
+	if err := decodeResponse(resp, &results); err != nil {
+		return SearchResults{}, err
+	}
+
+	return results, nil
+}
+
+// CreatePlaylist creates a regular or smart playlist and returns it
+func (p *Plex) CreatePlaylist(params CreatePlaylistParams) (Playlist, error) {
+	return p.CreatePlaylistCtx(context.Background(), params)
+}
+
+// CreatePlaylistCtx is like CreatePlaylist but carries ctx for cancellation and deadlines
+func (p *Plex) CreatePlaylistCtx(ctx context.Context, params CreatePlaylistParams) (Playlist, error) {
+	if params.Title == "" {
+		return Playlist{}, fmt.Errorf(ErrorCommon, ErrorTitleRequired)
+	}
+
+	switch params.Type {
+	case "video", "audio", "photo":
+	default:
+		return Playlist{}, fmt.Errorf(ErrorCommon, "type must be video, audio or photo")
+	}
+
+	smart := params.SectionKey != ""
+
+	if !smart && len(params.RatingKeys) == 0 {
+		return Playlist{}, fmt.Errorf(ErrorCommon, "a playlist needs rating keys or a section key")
+	}
+
+	path := "/library/metadata/" + strings.Join(params.RatingKeys, ",")
+
+	if smart {
+		path = "/library/sections/" + params.SectionKey + "/all"
+
+		if params.Filter != "" {
+			path += "?" + strings.TrimPrefix(params.Filter, "?")
+		}
+	}
+
+	uri, err := p.libraryURI(ctx, path)
+
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	vals := url.Values{}
+
+	vals.Set("title", params.Title)
+	vals.Set("type", params.Type)
+	vals.Set("uri", uri)
+	vals.Set("smart", "0")
+
+	if smart {
+		vals.Set("smart", "1")
+	}
+
+	playlists, err := p.getPlaylists(ctx, http.MethodPost, p.URL+"/playlists?"+vals.Encode())
+
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	if len(playlists) == 0 {
+		return Playlist{}, fmt.Errorf(ErrorCommon, "server did not return the new playlist")
+	}
+
+	return playlists[0], nil
+}
+
+// RenamePlaylist changes the title of a playlist
+func (p *Plex) RenamePlaylist(ratingKey, title string) error {
+	return p.RenamePlaylistCtx(context.Background(), ratingKey, title)
+}
+
+// RenamePlaylistCtx is like RenamePlaylist but carries ctx for cancellation and deadlines
+func (p *Plex) RenamePlaylistCtx(ctx context.Context, ratingKey, title string) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	if title == "" {
+		return fmt.Errorf(ErrorCommon, ErrorTitleRequired)
+	}
+
+	return p.action(ctx, http.MethodPut, "/playlists/"+ratingKey, url.Values{"title": []string{title}})
+}
+
+// AddToPlaylist appends items to a regular playlist
+func (p *Plex) AddToPlaylist(ratingKey string, itemRatingKeys ...string) error {
+	return p.AddToPlaylistCtx(context.Background(), ratingKey, itemRatingKeys...)
+}
+
+// AddToPlaylistCtx is like AddToPlaylist but carries ctx for cancellation and deadlines
+func (p *Plex) AddToPlaylistCtx(ctx context.Context, ratingKey string, itemRatingKeys ...string) error {
+	if ratingKey == "" || len(itemRatingKeys) == 0 {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	uri, err := p.libraryURI(ctx, "/library/metadata/"+strings.Join(itemRatingKeys, ","))
+
+	if err != nil {
+		return err
+	}
+
+	// playlists hold duplicates, so a retry would add the items again
+	return p.action(nonIdempotent(ctx), http.MethodPut, "/playlists/"+ratingKey+"/items", url.Values{"uri": []string{uri}})
+}
+
+// RemoveFromPlaylist removes an item from a regular playlist by its PlaylistItemID,
+// which tells apart several entries of the same item
+func (p *Plex) RemoveFromPlaylist(ratingKey, playlistItemID string) error {
+	return p.RemoveFromPlaylistCtx(context.Background(), ratingKey, playlistItemID)
+}
+
+// RemoveFromPlaylistCtx is like RemoveFromPlaylist but carries ctx for cancellation and deadlines
+func (p *Plex) RemoveFromPlaylistCtx(ctx context.Context, ratingKey, playlistItemID string) error {
+	if ratingKey == "" || playlistItemID == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(ctx, http.MethodDelete, "/playlists/"+ratingKey+"/items/"+playlistItemID, nil)
+}
+
+// MovePlaylistItem moves an item of a regular playlist right after the item with
+// afterPlaylistItemID, or to the top when afterPlaylistItemID is empty
+func (p *Plex) MovePlaylistItem(ratingKey, playlistItemID, afterPlaylistItemID string) error {
+	return p.MovePlaylistItemCtx(context.Background(), ratingKey, playlistItemID, afterPlaylistItemID)
+}
+
+// MovePlaylistItemCtx is like MovePlaylistItem but carries ctx for cancellation and deadlines
+func (p *Plex) MovePlaylistItemCtx(ctx context.Context, ratingKey, playlistItemID, afterPlaylistItemID string) error {
+	if ratingKey == "" || playlistItemID == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	var params url.Values
+
+	if afterPlaylistItemID != "" {
+		params = url.Values{"after": []string{afterPlaylistItemID}}
+	}
+
+	return p.action(ctx, http.MethodPut, "/playlists/"+ratingKey+"/items/"+playlistItemID+"/move", params)
+}
+
+// DeletePlaylist removes a playlist. The items in it are not affected
+func (p *Plex) DeletePlaylist(ratingKey string) error {
+	return p.DeletePlaylistCtx(context.Background(), ratingKey)
+}
+
+// DeletePlaylistCtx is like DeletePlaylist but carries ctx for cancellation and deadlines
+func (p *Plex) DeletePlaylistCtx(ctx context.Context, ratingKey string) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(ctx, http.MethodDelete, "/playlists/"+ratingKey, nil)
+}
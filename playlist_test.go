@@ -0,0 +1,139 @@
+package plex
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func playlistTitles(t *testing.T, conn *Plex, ratingKey string) (titles []string, ids []string) {
+	items, err := conn.GetPlaylistItems(ratingKey)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, item := range items.MediaContainer.Metadata {
+		titles = append(titles, item.Title)
+		ids = append(ids, item.PlaylistItemID.String())
+	}
+
+	return titles, ids
+}
+
+func TestPlaylistManagement(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	playlist, err := conn.CreatePlaylist(CreatePlaylistParams{
+		Title:      "Weekly",
+		Type:       "video",
+		RatingKeys: []string{"100", "101"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if playlist.Title != "Weekly" || playlist.Smart.Bool() || playlist.LeafCount.Int() != 2 {
+		t.Fatalf("unexpected playlist %+v", playlist)
+	}
+
+	// the items are sent as a uri naming the server by its machine identifier
+	var createQuery string
+
+	for _, request := range server.Requests() {
+		if request.Method == "POST" && request.Path == "/playlists" {
+			createQuery = request.Query.Get("uri")
+		}
+	}
+
+	if createQuery != "server://plextest-machine-id/com.plexapp.plugins.library/library/metadata/100,101" {
+		t.Errorf("unexpected playlist uri %s", createQuery)
+	}
+
+	if err := conn.AddToPlaylist(playlist.RatingKey, "102"); err != nil {
+		t.Fatal(err)
+	}
+
+	titles, ids := playlistTitles(t, conn, playlist.RatingKey)
+
+	if strings.Join(titles, ",") != "Alien,Aliens,Blade Runner" {
+		t.Fatalf("unexpected items %v", titles)
+	}
+
+	// move Blade Runner to the top, then Alien after Aliens
+	if err := conn.MovePlaylistItem(playlist.RatingKey, ids[2], ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.MovePlaylistItem(playlist.RatingKey, ids[0], ids[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	if titles, _ = playlistTitles(t, conn, playlist.RatingKey); strings.Join(titles, ",") != "Blade Runner,Aliens,Alien" {
+		t.Errorf("unexpected order after moving %v", titles)
+	}
+
+	if err := conn.RemoveFromPlaylist(playlist.RatingKey, ids[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.RenamePlaylist(playlist.RatingKey, "Weekend"); err != nil {
+		t.Fatal(err)
+	}
+
+	playlists, err := conn.GetPlaylists("video")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(playlists) != 2 || playlists[1].Title != "Weekend" || playlists[1].LeafCount.Int() != 2 {
+		t.Errorf("unexpected playlists %+v", playlists)
+	}
+
+	if err := conn.DeletePlaylist(playlist.RatingKey); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := conn.GetPlaylistItems(playlist.RatingKey); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a deleted playlist, got %v", err)
+	}
+}
+
+func TestSmartPlaylist(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	conn.MachineIdentifier = "plextest-machine-id"
+
+	playlist, err := conn.CreatePlaylist(CreatePlaylistParams{
+		Title:      "Unwatched Movies",
+		Type:       "video",
+		SectionKey: "1",
+		Filter:     NewLibraryQuery().Type("movie").Unwatched().Encode(),
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !playlist.Smart.Bool() {
+		t.Errorf("expected a smart playlist, got %+v", playlist)
+	}
+
+	for _, request := range server.Requests() {
+		if request.Path == "/identity" {
+			t.Error("the machine identifier should not be looked up when it is set")
+		}
+	}
+
+	if titles, _ := playlistTitles(t, conn, playlist.RatingKey); strings.Join(titles, ",") != "Alien,Aliens" {
+		t.Errorf("unexpected smart playlist items %v", titles)
+	}
+
+	if _, err := conn.CreatePlaylist(CreatePlaylistParams{Title: "Empty", Type: "video"}); err == nil {
+		t.Error("expected an error for a playlist without items or section")
+	}
+}
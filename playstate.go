@@ -0,0 +1,126 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// libraryIdentifier is the identifier of the library plugin that play state requests target
+const libraryIdentifier = "com.plexapp.plugins.library"
+
+func playStateParams(ratingKey string) url.Values {
+	return url.Values{
+		"key":        []string{ratingKey},
+		"identifier": []string{libraryIdentifier},
+	}
+}
+
+// MarkWatched marks the item with ratingKey as watched. Marking a show or a
+// season marks every episode in it
+func (p *Plex) MarkWatched(ratingKey string) error {
+	return p.MarkWatchedCtx(context.Background(), ratingKey)
+}
+
+// MarkWatchedCtx is like MarkWatched but carries ctx for cancellation and deadlines
+func (p *Plex) MarkWatchedCtx(ctx context.Context, ratingKey string) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(mutating(ctx), http.MethodGet, "/:/scrobble", playStateParams(ratingKey))
+}
+
+// MarkUnwatched marks the item with ratingKey as unwatched and clears its progress.
+// Marking a show or a season marks every episode in it
+func (p *Plex) MarkUnwatched(ratingKey string) error {
+	return p.MarkUnwatchedCtx(context.Background(), ratingKey)
+}
+
+// MarkUnwatchedCtx is like MarkUnwatched but carries ctx for cancellation and deadlines
+func (p *Plex) MarkUnwatchedCtx(ctx context.Context, ratingKey string) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(mutating(ctx), http.MethodGet, "/:/unscrobble", playStateParams(ratingKey))
+}
+
+// SetProgress sets the resume position of the item with ratingKey
+func (p *Plex) SetProgress(ratingKey string, offset time.Duration) error {
+	return p.SetProgressCtx(context.Background(), ratingKey, offset)
+}
+
+// SetProgressCtx is like SetProgress but carries ctx for cancellation and deadlines
+func (p *Plex) SetProgressCtx(ctx context.Context, ratingKey string, offset time.Duration) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	if offset < 0 {
+		return fmt.Errorf(ErrorCommon, "offset must not be negative")
+	}
+
+	params := playStateParams(ratingKey)
+
+	params.Set("time", strconv.FormatInt(int64(offset/time.Millisecond), 10))
+	params.Set("state", "stopped")
+
+	return p.action(mutating(ctx), http.MethodGet, "/:/progress", params)
+}
+
+// SetRating sets the user rating of the item with ratingKey, from 0 to 10 where
+// every 2 points are a star
+func (p *Plex) SetRating(ratingKey string, rating float64) error {
+	return p.SetRatingCtx(context.Background(), ratingKey, rating)
+}
+
+// SetRatingCtx is like SetRating but carries ctx for cancellation and deadlines
+func (p *Plex) SetRatingCtx(ctx context.Context, ratingKey string, rating float64) error {
+	if rating < 0 || rating > 10 {
+		return fmt.Errorf(ErrorCommon, "rating must be between 0 and 10")
+	}
+
+	return p.rate(ctx, ratingKey, rating)
+}
+
+// RemoveRating removes the user rating of the item with ratingKey
+func (p *Plex) RemoveRating(ratingKey string) error {
+	return p.RemoveRatingCtx(context.Background(), ratingKey)
+}
+
+// RemoveRatingCtx is like RemoveRating but carries ctx for cancellation and deadlines
+func (p *Plex) RemoveRatingCtx(ctx context.Context, ratingKey string) error {
+	// plex removes the rating when it is set to -1
+	return p.rate(ctx, ratingKey, -1)
+}
+
+func (p *Plex) rate(ctx context.Context, ratingKey string, rating float64) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	params := playStateParams(ratingKey)
+
+	params.Set("rating", strconv.FormatFloat(rating, 'f', -1, 64))
+
+	return p.action(ctx, http.MethodPut, "/:/rate", params)
+}
+
+// RemoveFromContinueWatching hides the item with ratingKey from Continue Watching
+// without changing its progress
+func (p *Plex) RemoveFromContinueWatching(ratingKey string) error {
+	return p.RemoveFromContinueWatchingCtx(context.Background(), ratingKey)
+}
+
+// RemoveFromContinueWatchingCtx is like RemoveFromContinueWatching but carries ctx for cancellation and deadlines
+func (p *Plex) RemoveFromContinueWatchingCtx(ctx context.Context, ratingKey string) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(ctx, http.MethodPut, "/actions/removeFromContinueWatching", url.Values{"ratingKey": []string{ratingKey}})
+}
@@ -0,0 +1,116 @@
+package plex
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func TestWatchedState(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	// marking the show marks both of its episodes
+	if err := conn.MarkWatched("200"); err != nil {
+		t.Fatal(err)
+	}
+
+	show, err := conn.GetMetadata("200")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if item := show.MediaContainer.Metadata[0]; item.LeafCount.Int() != 2 || item.ViewedLeafCount.Int() != 2 {
+		t.Errorf("expected every episode to be watched, got %d of %d", item.ViewedLeafCount.Int(), item.LeafCount.Int())
+	}
+
+	if err := conn.MarkUnwatched("201"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, item := range server.Fixtures().Items[5:] {
+		if item.ViewCount != 0 {
+			t.Errorf("expected %s to be unwatched, got a view count of %d", item.Title, item.ViewCount)
+		}
+	}
+
+	if err := conn.MarkWatched("999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown item, got %v", err)
+	}
+}
+
+func TestProgressAndContinueWatching(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	if err := conn.SetProgress("100", 90*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, err := conn.GetMetadata("100")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if offset := metadata.MediaContainer.Metadata[0].ViewOffset.Duration(); offset != 90*time.Second {
+		t.Errorf("expected a resume position of 90s, got %s", offset)
+	}
+
+	onDeck, err := conn.GetOnDeck()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count := len(onDeck.MediaContainer.Metadata); count != 2 {
+		t.Fatalf("expected 2 items on deck, got %d", count)
+	}
+
+	if err := conn.RemoveFromContinueWatching("100"); err != nil {
+		t.Fatal(err)
+	}
+
+	if onDeck, err = conn.GetOnDeck(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := len(onDeck.MediaContainer.Metadata); count != 1 || onDeck.MediaContainer.Metadata[0].RatingKey != "101" {
+		t.Errorf("expected only 101 on deck, got %+v", onDeck.MediaContainer.Metadata)
+	}
+
+	if err := conn.SetProgress("100", -time.Second); err == nil {
+		t.Error("expected an error for a negative offset")
+	}
+}
+
+func TestRating(t *testing.T) {
+	conn, server := newTestConn(t, plextest.DefaultFixtures())
+
+	if err := conn.SetRating("102", 8.5); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, err := conn.GetMetadata("102")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rating := metadata.MediaContainer.Metadata[0].UserRating.Float64(); rating != 8.5 {
+		t.Errorf("expected a rating of 8.5, got %v", rating)
+	}
+
+	if err := conn.RemoveRating("102"); err != nil {
+		t.Fatal(err)
+	}
+
+	if rating := server.Fixtures().Items[2].UserRating; rating != 0 {
+		t.Errorf("expected the rating to be removed, got %v", rating)
+	}
+
+	if err := conn.SetRating("102", 11); err == nil {
+		t.Error("expected an error for a rating above 10")
+	}
+}
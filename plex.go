@@ -3,12 +3,11 @@ package plex
 // plex is a Plex Media Server and Plex.tv client
 
 import (
+	"context"
 	"encoding/json"
-	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
@@ -22,50 +21,79 @@ import (
 
 const plexURL = "https://plex.tv"
 
+const defaultProduct = "Go Plex Client"
+
 func defaultHeaders() headers {
 	version := "0.0.1"
 
 	return headers{
 		Platform:         runtime.GOOS,
 		PlatformVersion:  "0.0.0",
-		Product:          "Go Plex Client",
+		Product:          defaultProduct,
 		Version:          version,
 		Device:           runtime.GOOS + " " + runtime.GOARCH,
-		ClientIdentifier: "go-plex-client-v" + version,
-		ContainerSize:    "Plex-Container-Size=50",
-		ContainerStart:   "X-Plex-Container-Start=0",
-		Accept:           "application/json",
-		ContentType:      "application/json",
+		ClientIdentifier: defaultClientIdentifier(defaultProduct),
+		Accept:           mimeJSON,
+		ContentType:      mimeJSON,
 	}
 }
 
-// New creates a new plex instance that is required to
-// to make requests to your Plex Media Server
-func New(baseURL, token string) (*Plex, error) {
-	var p Plex
+// defaultClientIdentifier derives a client identifier from the host name and product
+// so it is unique per machine yet stays the same across runs
+func defaultClientIdentifier(product string) string {
+	hostname, err := os.Hostname()
 
-	// allow empty url so caller can use GetServers() to set the server url later
+	if err != nil {
+		hostname = runtime.GOOS + "-" + runtime.GOARCH
+	}
 
-	if baseURL == "" && token == "" {
-		return &p, errors.New(ErrorUrlTokenRequired)
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(hostname+"/"+product)).String()
+}
+
+// newPlex returns a plex instance with the default http clients and headers with opts applied
+func newPlex(opts []Option) *Plex {
+	p := Plex{
+		HTTPClient: http.Client{
+			Timeout: 3 * time.Second,
+		},
+		DownloadClient: http.Client{},
+		Headers:        defaultHeaders(),
+		PlexTVURL:      plexURL,
+		capabilities:   &capabilityCache{},
 	}
 
-	p.HTTPClient = http.Client{
-		Timeout: 3 * time.Second,
+	for _, opt := range opts {
+		opt(&p)
 	}
 
-	p.DownloadClient = http.Client{}
+	if p.ClientIdentifier == "" {
+		p.ClientIdentifier = defaultClientIdentifier(p.Headers.Product)
+	}
 
-	p.Headers = defaultHeaders()
-	// id, err := uuid.NewRandom()
+	p.Headers.ClientIdentifier = p.ClientIdentifier
 
-	// if err != nil {
-	// 	return &p, err
-	// }
+	return &p
+}
 
-	// p.ClientIdentifier = id.String()
-	p.ClientIdentifier = p.Headers.ClientIdentifier
-	p.Headers.ClientIdentifier = p.ClientIdentifier
+// plexTV returns the base url of plex.tv, which can be overridden with WithPlexTVURL
+func (p Plex) plexTV() string {
+	if p.PlexTVURL == "" {
+		return plexURL
+	}
+
+	return strings.TrimSuffix(p.PlexTVURL, "/")
+}
+
+// New creates a new plex instance that is required to
+// to make requests to your Plex Media Server
+func New(baseURL, token string, opts ...Option) (*Plex, error) {
+	p := newPlex(opts)
+
+	// allow empty url so caller can use GetServers() to set the server url later
+
+	if baseURL == "" && token == "" {
+		return p, errors.New(ErrorUrlTokenRequired)
+	}
 
 	// has url and token
 	if baseURL != "" && token != "" {
@@ -74,39 +102,34 @@ func New(baseURL, token string) (*Plex, error) {
 		p.URL = baseURL
 		p.Token = token
 
-		return &p, err
+		return p, err
 	}
 
 	// just has token
 	if baseURL == "" && token != "" {
 		p.Token = token
 
-		return &p, nil
+		return p, nil
 	}
 
 	// just url
 	p.URL = baseURL
 
-	return &p, nil
+	return p, nil
 }
 
 // SignIn creates a plex instance using a user name and password instead of an auth
 // token.
-func SignIn(username, password string) (*Plex, error) {
-	id, err := uuid.NewRandom()
+// The options are the same as New's so the session uses the same client identity
+func SignIn(username, password string, opts ...Option) (*Plex, error) {
+	return SignInCtx(context.Background(), username, password, opts...)
+}
 
-	if err != nil {
-		return &Plex{}, err
-	}
+// SignInCtx is like SignIn but carries ctx for cancellation and deadlines
+func SignInCtx(ctx context.Context, username, password string, opts ...Option) (*Plex, error) {
+	p := newPlex(opts)
 
-	p := Plex{
-		ClientIdentifier: id.String(),
-		HTTPClient: http.Client{
-			Timeout: 3 * time.Second,
-		},
-	}
-
-	query := plexURL + "/api/v2/users/signin"
+	query := p.plexTV() + "/api/v2/users/signin"
 
 	// Encode login in the specific format they require
 	body := url.Values{}
@@ -118,8 +141,8 @@ func SignIn(username, password string) (*Plex, error) {
 	newHeaders := p.Headers
 	// Doesn't like having a content type, even form-data
 	newHeaders.ContentType = "application/x-www-form-urlencoded"
-	newHeaders.Accept = "application/json"
-	resp, err := p.post(query, []byte(body.Encode()), newHeaders)
+	newHeaders.Accept = mimeJSON
+	resp, err := p.post(ctx, query, []byte(body.Encode()), newHeaders)
 
 	if err != nil {
 		return &Plex{}, err
@@ -128,22 +151,27 @@ func SignIn(username, password string) (*Plex, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return &Plex{}, errors.New(resp.Status)
+		return &Plex{}, newAPIError(resp)
 	}
 
 	var signInResponse SignInResponse
 
-	if err := json.NewDecoder(resp.Body).Decode(&signInResponse); err != nil {
+	if err := decodeResponse(resp, &signInResponse); err != nil {
 		return &Plex{}, err
 	}
 
 	p.Token = signInResponse.AuthToken
 
-	return &p, err
+	return p, nil
 }
 
 // Search your Plex Server for media
 func (p *Plex) Search(title string) (SearchResults, error) {
+	return p.SearchCtx(context.Background(), title)
+}
+
+// SearchCtx is like Search but carries ctx for cancellation and deadlines
+func (p *Plex) SearchCtx(ctx context.Context, title string) (SearchResults, error) {
 	if title == "" {
 		return SearchResults{}, fmt.Errorf(ErrorCommon, ErrorTitleRequired)
 	}
@@ -153,20 +181,19 @@ func (p *Plex) Search(title string) (SearchResults, error) {
 
 	var results SearchResults
 
-	resp, err := p.get(query, p.Headers)
+	resp, err := p.get(ctx, query, p.Headers)
 
 	if err != nil {
 		return SearchResults{}, err
 	}
 
-	// Unauthorized
-	if resp.StatusCode == http.StatusUnauthorized {
-		return SearchResults{}, errors.New(ErrorNotAuthorized)
-	}
-
 	defer resp.Body.Close()
 
-	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+	if resp.StatusCode != http.StatusOK {
+		return SearchResults{}, newAPIError(resp)
+	}
+
+	if err := decodeResponse(resp, &results); err != nil {
 		return SearchResults{}, err
 	}
 
@@ -175,6 +202,11 @@ func (p *Plex) Search(title string) (SearchResults, error) {
 
 // GetMetadata can get some media info
 func (p *Plex) GetMetadata(key string) (MediaMetadata, error) {
+	return p.GetMetadataCtx(context.Background(), key)
+}
+
+// GetMetadataCtx is like GetMetadata but carries ctx for cancellation and deadlines
+func (p *Plex) GetMetadataCtx(ctx context.Context, key string) (MediaMetadata, error) {
 	if key == "" {
 		return MediaMetadata{}, fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
 	}
@@ -185,19 +217,19 @@ func (p *Plex) GetMetadata(key string) (MediaMetadata, error) {
 
 	newHeaders := p.Headers
 
-	resp, err := p.get(query, newHeaders)
+	resp, err := p.get(ctx, query, newHeaders)
 
 	if err != nil {
 		return results, err
 	}
 
+	defer resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK {
-		return results, fmt.Errorf(ErrorServer, resp.Status)
+		return results, newAPIError(resp)
 	}
 
-	defer resp.Body.Close()
-
-	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+	if err := decodeResponse(resp, &results); err != nil {
 		return results, err
 	}
 
@@ -206,6 +238,11 @@ func (p *Plex) GetMetadata(key string) (MediaMetadata, error) {
 
 // GetMetadataChildren can get a show's season titles. My use-case would be getting the season titles after using Search()
 func (p *Plex) GetMetadataChildren(key string) (MetadataChildren, error) {
+	return p.GetMetadataChildrenCtx(context.Background(), key)
+}
+
+// GetMetadataChildrenCtx is like GetMetadataChildren but carries ctx for cancellation and deadlines
+func (p *Plex) GetMetadataChildrenCtx(ctx context.Context, key string) (MetadataChildren, error) {
 	if key == "" {
 		return MetadataChildren{}, fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
 	}
@@ -214,22 +251,21 @@ func (p *Plex) GetMetadataChildren(key string) (MetadataChildren, error) {
 
 	newHeaders := p.Headers
 
-	resp, err := p.get(query, newHeaders)
+	resp, err := p.get(ctx, query, newHeaders)
 
 	if err != nil {
 		return MetadataChildren{}, err
 	}
 
-	// Unauthorized
-	if resp.StatusCode == http.StatusUnauthorized {
-		return MetadataChildren{}, errors.New(ErrorNotAuthorized)
-	}
-
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return MetadataChildren{}, newAPIError(resp)
+	}
+
 	var results MetadataChildren
 
-	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+	if err := decodeResponse(resp, &results); err != nil {
 		return MetadataChildren{}, err
 	}
 
@@ -238,28 +274,32 @@ func (p *Plex) GetMetadataChildren(key string) (MetadataChildren, error) {
 
 // GetEpisodes returns episodes of a season of a show
 func (p *Plex) GetEpisodes(key string) (SearchResultsEpisode, error) {
+	return p.GetEpisodesCtx(context.Background(), key)
+}
+
+// GetEpisodesCtx is like GetEpisodes but carries ctx for cancellation and deadlines
+func (p *Plex) GetEpisodesCtx(ctx context.Context, key string) (SearchResultsEpisode, error) {
 	if key == "" {
 		return SearchResultsEpisode{}, fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
 	}
 
 	query := fmt.Sprintf("%s/library/metadata/%s/children", p.URL, key)
 
-	resp, err := p.get(query, p.Headers)
+	resp, err := p.get(ctx, query, p.Headers)
 
 	if err != nil {
 		return SearchResultsEpisode{}, err
 	}
 
-	// Unauthorized
-	if resp.StatusCode == http.StatusUnauthorized {
-		return SearchResultsEpisode{}, errors.New(ErrorNotAuthorized)
-	}
-
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return SearchResultsEpisode{}, newAPIError(resp)
+	}
+
 	var results SearchResultsEpisode
 
-	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+	if err := decodeResponse(resp, &results); err != nil {
 		return SearchResultsEpisode{}, err
 	}
 
@@ -268,28 +308,32 @@ func (p *Plex) GetEpisodes(key string) (SearchResultsEpisode, error) {
 
 // GetEpisode returns a single episode of a show.
 func (p *Plex) GetEpisode(key string) (SearchResultsEpisode, error) {
+	return p.GetEpisodeCtx(context.Background(), key)
+}
+
+// GetEpisodeCtx is like GetEpisode but carries ctx for cancellation and deadlines
+func (p *Plex) GetEpisodeCtx(ctx context.Context, key string) (SearchResultsEpisode, error) {
 	if key == "" {
 		return SearchResultsEpisode{}, fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
 	}
 
 	query := fmt.Sprintf("%s/library/metadata/%s", p.URL, key)
 
-	resp, err := p.get(query, p.Headers)
+	resp, err := p.get(ctx, query, p.Headers)
 
 	if err != nil {
 		return SearchResultsEpisode{}, err
 	}
 
-	// Unauthorized
-	if resp.StatusCode == http.StatusUnauthorized {
-		return SearchResultsEpisode{}, errors.New(ErrorNotAuthorized)
-	}
-
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return SearchResultsEpisode{}, newAPIError(resp)
+	}
+
 	var results SearchResultsEpisode
 
-	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+	if err := decodeResponse(resp, &results); err != nil {
 		return SearchResultsEpisode{}, err
 	}
 
@@ -298,24 +342,28 @@ func (p *Plex) GetEpisode(key string) (SearchResultsEpisode, error) {
 
 // GetOnDeck gets the on-deck videos.
 func (p *Plex) GetOnDeck() (SearchResultsEpisode, error) {
+	return p.GetOnDeckCtx(context.Background())
+}
+
+// GetOnDeckCtx is like GetOnDeck but carries ctx for cancellation and deadlines
+func (p *Plex) GetOnDeckCtx(ctx context.Context) (SearchResultsEpisode, error) {
 	query := fmt.Sprintf("%s/library/onDeck", p.URL)
 
-	resp, err := p.get(query, p.Headers)
+	resp, err := p.get(ctx, query, p.Headers)
 
 	if err != nil {
 		return SearchResultsEpisode{}, err
 	}
 
-	// Unauthorized
-	if resp.StatusCode == http.StatusUnauthorized {
-		return SearchResultsEpisode{}, errors.New(ErrorNotAuthorized)
-	}
-
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return SearchResultsEpisode{}, newAPIError(resp)
+	}
+
 	var results SearchResultsEpisode
 
-	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+	if err := decodeResponse(resp, &results); err != nil {
 		return SearchResultsEpisode{}, err
 	}
 
@@ -324,6 +372,11 @@ func (p *Plex) GetOnDeck() (SearchResultsEpisode, error) {
 
 // Download media associated with metadata
 func (p *Plex) Download(meta Metadata, path string, createFolders bool, skipIfExists bool) error {
+	return p.DownloadCtx(context.Background(), meta, path, createFolders, skipIfExists)
+}
+
+// DownloadCtx is like Download but carries ctx for cancellation and deadlines
+func (p *Plex) DownloadCtx(ctx context.Context, meta Metadata, path string, createFolders bool, skipIfExists bool) error {
 
 	if len(meta.Media) == 0 {
 		return fmt.Errorf("no media associated with metadata, skipping")
@@ -360,14 +413,15 @@ func (p *Plex) Download(meta Metadata, path string, createFolders bool, skipIfEx
 
 			query := fmt.Sprintf("%s%s?download=1", p.URL, part.Key)
 
-			resp, err := p.grab(query, p.Headers)
+			resp, err := p.grab(ctx, query, p.Headers)
 			if err != nil {
 				return err
 			}
 
-			// Unauthorized
-			if resp.StatusCode == http.StatusUnauthorized {
-				return errors.New(ErrorNotAuthorized)
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return newAPIError(resp)
 			}
 
 			out, err := os.Create(fp)
@@ -390,26 +444,28 @@ func (p *Plex) Download(meta Metadata, path string, createFolders bool, skipIfEx
 
 // GetPlaylist gets all videos in a playlist.
 func (p *Plex) GetPlaylist(key int) (SearchResultsEpisode, error) {
+	return p.GetPlaylistCtx(context.Background(), key)
+}
+
+// GetPlaylistCtx is like GetPlaylist but carries ctx for cancellation and deadlines
+func (p *Plex) GetPlaylistCtx(ctx context.Context, key int) (SearchResultsEpisode, error) {
 	query := fmt.Sprintf("%s/playlists/%d/items", p.URL, key)
 
-	resp, err := p.get(query, p.Headers)
+	resp, err := p.get(ctx, query, p.Headers)
 
 	if err != nil {
 		return SearchResultsEpisode{}, err
 	}
 
-	// Unauthorized
-	if resp.StatusCode == http.StatusUnauthorized {
-		return SearchResultsEpisode{}, errors.New(ErrorNotAuthorized)
-	} else if resp.StatusCode != http.StatusOK {
-		return SearchResultsEpisode{}, fmt.Errorf(ErrorServer, resp.Status)
-	}
-
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return SearchResultsEpisode{}, newAPIError(resp)
+	}
+
 	var results SearchResultsEpisode
 
-	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+	if err := decodeResponse(resp, &results); err != nil {
 		return SearchResultsEpisode{}, err
 	}
 
@@ -419,14 +475,24 @@ func (p *Plex) GetPlaylist(key int) (SearchResultsEpisode, error) {
 // GetThumbnail returns the response of a request to pms thumbnail
 // My ideal use case would be to proxy a request to pms without exposing the plex token
 func (p *Plex) GetThumbnail(key, thumbnailID string) (*http.Response, error) {
+	return p.GetThumbnailCtx(context.Background(), key, thumbnailID)
+}
+
+// GetThumbnailCtx is like GetThumbnail but carries ctx for cancellation and deadlines
+func (p *Plex) GetThumbnailCtx(ctx context.Context, key, thumbnailID string) (*http.Response, error) {
 	query := fmt.Sprintf("%s/library/metadata/%s/thumb/%s", p.URL, key, thumbnailID)
 
-	return p.get(query, p.Headers)
+	return p.get(ctx, query, p.Headers)
 }
 
 // Test your connection to your Plex Media Server
 func (p *Plex) Test() (bool, error) {
-	resp, err := p.get(plexURL+"/api/servers", p.Headers)
+	return p.TestCtx(context.Background())
+}
+
+// TestCtx is like Test but carries ctx for cancellation and deadlines
+func (p *Plex) TestCtx(ctx context.Context) (bool, error) {
+	resp, err := p.get(ctx, p.plexTV()+"/api/servers", p.Headers)
 
 	if err != nil {
 		return false, err
@@ -434,10 +500,8 @@ func (p *Plex) Test() (bool, error) {
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return false, errors.New(ErrorNotAuthorized)
-	} else if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf(ErrorServerReplied, resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		return false, newAPIError(resp)
 	}
 
 	return true, nil
@@ -445,6 +509,11 @@ func (p *Plex) Test() (bool, error) {
 
 // KillTranscodeSession stops a transcode session
 func (p *Plex) KillTranscodeSession(sessionKey string) (bool, error) {
+	return p.KillTranscodeSessionCtx(context.Background(), sessionKey)
+}
+
+// KillTranscodeSessionCtx is like KillTranscodeSession but carries ctx for cancellation and deadlines
+func (p *Plex) KillTranscodeSessionCtx(ctx context.Context, sessionKey string) (bool, error) {
 
 	if sessionKey == "" {
 		return false, errors.New(ErrorMissingSessionKey)
@@ -452,7 +521,7 @@ func (p *Plex) KillTranscodeSession(sessionKey string) (bool, error) {
 
 	query := p.URL + "/video/:/transcode/universal/stop?session=" + sessionKey
 
-	resp, err := p.get(query, p.Headers)
+	resp, err := p.get(mutating(ctx), query, p.Headers)
 
 	if err != nil {
 		return false, err
@@ -460,10 +529,8 @@ func (p *Plex) KillTranscodeSession(sessionKey string) (bool, error) {
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return false, errors.New(ErrorNotAuthorized)
-	} else if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf(ErrorServerReplied, resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		return false, newAPIError(resp)
 	}
 
 	return true, nil
@@ -471,11 +538,16 @@ func (p *Plex) KillTranscodeSession(sessionKey string) (bool, error) {
 
 // GetTranscodeSessions retrieves a list of all active transcode sessions
 func (p *Plex) GetTranscodeSessions() (TranscodeSessionsResponse, error) {
+	return p.GetTranscodeSessionsCtx(context.Background())
+}
+
+// GetTranscodeSessionsCtx is like GetTranscodeSessions but carries ctx for cancellation and deadlines
+func (p *Plex) GetTranscodeSessionsCtx(ctx context.Context) (TranscodeSessionsResponse, error) {
 	var result TranscodeSessionsResponse
 
 	query := p.URL + "/transcode/sessions"
 
-	resp, err := p.get(query, p.Headers)
+	resp, err := p.get(ctx, query, p.Headers)
 
 	if err != nil {
 		return result, err
@@ -483,23 +555,26 @@ func (p *Plex) GetTranscodeSessions() (TranscodeSessionsResponse, error) {
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return result, errors.New(ErrorNotAuthorized)
-	} else if resp.StatusCode != http.StatusOK {
-		return result, fmt.Errorf(ErrorServerReplied, resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		return result, newAPIError(resp)
 	}
 
-	return result, json.NewDecoder(resp.Body).Decode(&result)
+	return result, decodeResponse(resp, &result)
 
 }
 
 // GetPlexTokens not sure if it works
 func (p *Plex) GetPlexTokens(token string) (DevicesResponse, error) {
+	return p.GetPlexTokensCtx(context.Background(), token)
+}
+
+// GetPlexTokensCtx is like GetPlexTokens but carries ctx for cancellation and deadlines
+func (p *Plex) GetPlexTokensCtx(ctx context.Context, token string) (DevicesResponse, error) {
 	var result DevicesResponse
 
-	query := plexURL + "/devices.json"
+	query := p.plexTV() + "/devices.json"
 
-	resp, err := p.get(query, p.Headers)
+	resp, err := p.get(ctx, query, p.Headers)
 
 	if err != nil {
 		return result, err
@@ -507,22 +582,25 @@ func (p *Plex) GetPlexTokens(token string) (DevicesResponse, error) {
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return result, errors.New(ErrorNotAuthorized)
-	} else if resp.StatusCode != http.StatusOK {
-		return result, fmt.Errorf(ErrorServerReplied, resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		return result, newAPIError(resp)
 	}
 
-	return result, json.NewDecoder(resp.Body).Decode(&result)
+	return result, decodeResponse(resp, &result)
 }
 
 // DeletePlexToken is currently not tested
 func (p *Plex) DeletePlexToken(token string) (bool, error) {
+	return p.DeletePlexTokenCtx(context.Background(), token)
+}
+
+// DeletePlexTokenCtx is like DeletePlexToken but carries ctx for cancellation and deadlines
+func (p *Plex) DeletePlexTokenCtx(ctx context.Context, token string) (bool, error) {
 	var result bool
 
-	query := plexURL + "/devices/" + token + ".json"
+	query := p.plexTV() + "/devices/" + token + ".json"
 
-	resp, err := p.get(query, p.Headers)
+	resp, err := p.get(mutating(ctx), query, p.Headers)
 
 	if err != nil {
 		return result, err
@@ -530,27 +608,30 @@ func (p *Plex) DeletePlexToken(token string) (bool, error) {
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return result, errors.New(ErrorNotAuthorized)
-	} else if resp.StatusCode != http.StatusOK {
-		return result, fmt.Errorf(ErrorServerReplied, resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		return result, newAPIError(resp)
 	}
 
-	return result, json.NewDecoder(resp.Body).Decode(&result)
+	return result, decodeResponse(resp, &result)
 }
 
 // GetFriends returns all of your plex friends
 func (p *Plex) GetFriends() ([]Friends, error) {
+	return p.GetFriendsCtx(context.Background())
+}
+
+// GetFriendsCtx is like GetFriends but carries ctx for cancellation and deadlines
+func (p *Plex) GetFriendsCtx(ctx context.Context) ([]Friends, error) {
 
 	var plexFriendsResp friendsResponse
 
-	query := plexURL + "/api/users"
+	query := p.plexTV() + "/api/users"
 
 	newHeaders := p.Headers
 
-	newHeaders.Accept = "application/xml"
+	newHeaders.Accept = mimeXML
 
-	resp, err := p.get(query, newHeaders)
+	resp, err := p.get(ctx, query, newHeaders)
 
 	if err != nil {
 		return []Friends{}, err
@@ -558,21 +639,11 @@ func (p *Plex) GetFriends() ([]Friends, error) {
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return []Friends{}, errors.New(ErrorNotAuthorized)
-	} else if resp.StatusCode != http.StatusOK {
-		return []Friends{}, fmt.Errorf(ErrorServerReplied, resp.StatusCode)
-	}
-
-	respBytes, err := ioutil.ReadAll(resp.Body)
-
-	if err != nil {
-		return []Friends{}, err
+	if resp.StatusCode != http.StatusOK {
+		return []Friends{}, newAPIError(resp)
 	}
 
-	err = xml.Unmarshal(respBytes, &plexFriendsResp)
-
-	if err != nil {
+	if err := decodeResponse(resp, &plexFriendsResp); err != nil {
 		return []Friends{}, err
 	}
 
@@ -589,10 +660,15 @@ func (p *Plex) GetFriends() ([]Friends, error) {
 
 // RemoveFriend from your friend's list which stops access to your Plex server
 func (p *Plex) RemoveFriend(id string) (bool, error) {
+	return p.RemoveFriendCtx(context.Background(), id)
+}
+
+// RemoveFriendCtx is like RemoveFriend but carries ctx for cancellation and deadlines
+func (p *Plex) RemoveFriendCtx(ctx context.Context, id string) (bool, error) {
 
-	query := plexURL + "/api/friends/" + id
+	query := p.plexTV() + "/api/friends/" + id
 
-	resp, err := p.delete(query, p.Headers)
+	resp, err := p.delete(ctx, query, p.Headers)
 
 	if err != nil {
 		return false, err
@@ -601,12 +677,12 @@ func (p *Plex) RemoveFriend(id string) (bool, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
-		return false, errors.New(resp.Status)
+		return false, newAPIError(resp)
 	}
 
 	result := new(resultResponse)
 
-	if err := xml.NewDecoder(resp.Body).Decode(result); err != nil {
+	if err := decodeResponse(resp, result); err != nil {
 		return false, err
 	}
 
@@ -615,10 +691,15 @@ func (p *Plex) RemoveFriend(id string) (bool, error) {
 
 // InviteFriend to access your Plex server. Add restrictions to media or give them full access.
 func (p *Plex) InviteFriend(params InviteFriendParams) error {
+	return p.InviteFriendCtx(context.Background(), params)
+}
+
+// InviteFriendCtx is like InviteFriend but carries ctx for cancellation and deadlines
+func (p *Plex) InviteFriendCtx(ctx context.Context, params InviteFriendParams) error {
 
 	label := url.QueryEscape(params.Label)
 
-	query := fmt.Sprintf("%s/api/v2/shared_servers", plexURL)
+	query := fmt.Sprintf("%s/api/v2/shared_servers", p.plexTV())
 
 	var requestBody inviteFriendBody
 
@@ -641,7 +722,7 @@ func (p *Plex) InviteFriend(params InviteFriendParams) error {
 		return jsonErr
 	}
 
-	resp, err := p.post(query, jsonBody, p.Headers)
+	resp, err := p.post(ctx, query, jsonBody, p.Headers)
 
 	if err != nil {
 		return err
@@ -650,12 +731,12 @@ func (p *Plex) InviteFriend(params InviteFriendParams) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New(resp.Status)
+		return newAPIError(resp)
 	}
 
 	result := new(inviteFriendResponse)
 
-	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+	if err := decodeResponse(resp, result); err != nil {
 		return err
 	}
 
@@ -664,6 +745,11 @@ func (p *Plex) InviteFriend(params InviteFriendParams) error {
 
 // UpdateFriendAccess limit your friends access to your plex server
 func (p *Plex) UpdateFriendAccess(userID string, params UpdateFriendParams) (bool, error) {
+	return p.UpdateFriendAccessCtx(context.Background(), userID, params)
+}
+
+// UpdateFriendAccessCtx is like UpdateFriendAccess but carries ctx for cancellation and deadlines
+func (p *Plex) UpdateFriendAccessCtx(ctx context.Context, userID string, params UpdateFriendParams) (bool, error) {
 	// Fix any defaults to statisfy what plex expects
 	if params.AllowSync == "" {
 		params.AllowSync = "0"
@@ -677,7 +763,7 @@ func (p *Plex) UpdateFriendAccess(userID string, params UpdateFriendParams) (boo
 		params.AllowChannels = "0"
 	}
 
-	query := fmt.Sprintf("%s/api/friends/%s", plexURL, userID)
+	query := fmt.Sprintf("%s/api/friends/%s", p.plexTV(), userID)
 
 	parsedQuery, parseErr := url.Parse(query)
 
@@ -699,16 +785,16 @@ func (p *Plex) UpdateFriendAccess(userID string, params UpdateFriendParams) (boo
 
 	query = parsedQuery.String()
 
-	resp, err := p.put(query, nil, p.Headers)
+	resp, err := p.put(ctx, query, nil, p.Headers)
 
 	if err != nil {
 		return false, err
 	}
 
-	resp.Body.Close()
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return false, errors.New(resp.Status)
+		return false, newAPIError(resp)
 	}
 
 	return true, nil
@@ -716,18 +802,23 @@ func (p *Plex) UpdateFriendAccess(userID string, params UpdateFriendParams) (boo
 
 // RemoveFriendAccessToLibrary you can individually revoke access to a library on your server. Such as movies, tv shows, music, etc
 func (p *Plex) RemoveFriendAccessToLibrary(userID, machineID, serverID string) (bool, error) {
-	query := fmt.Sprintf("%s/api/servers/%s/shared_servers/%s", plexURL, machineID, serverID)
+	return p.RemoveFriendAccessToLibraryCtx(context.Background(), userID, machineID, serverID)
+}
 
-	resp, err := p.delete(query, p.Headers)
+// RemoveFriendAccessToLibraryCtx is like RemoveFriendAccessToLibrary but carries ctx for cancellation and deadlines
+func (p *Plex) RemoveFriendAccessToLibraryCtx(ctx context.Context, userID, machineID, serverID string) (bool, error) {
+	query := fmt.Sprintf("%s/api/servers/%s/shared_servers/%s", p.plexTV(), machineID, serverID)
+
+	resp, err := p.delete(ctx, query, p.Headers)
 
 	if err != nil {
 		return false, err
 	}
 
-	resp.Body.Close()
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return false, errors.New(resp.Status)
+		return false, newAPIError(resp)
 	}
 
 	return true, nil
@@ -735,12 +826,17 @@ func (p *Plex) RemoveFriendAccessToLibrary(userID, machineID, serverID string) (
 
 // CheckUsernameOrEmail will check if the username is a Plex user or will verify an email is valid
 func (p *Plex) CheckUsernameOrEmail(usernameOrEmail string) (bool, error) {
+	return p.CheckUsernameOrEmailCtx(context.Background(), usernameOrEmail)
+}
+
+// CheckUsernameOrEmailCtx is like CheckUsernameOrEmail but carries ctx for cancellation and deadlines
+func (p *Plex) CheckUsernameOrEmailCtx(ctx context.Context, usernameOrEmail string) (bool, error) {
 
 	usernameOrEmail = url.QueryEscape(usernameOrEmail)
 
-	query := fmt.Sprintf("%s/api/users/validate?invited_email=%s", plexURL, usernameOrEmail)
+	query := fmt.Sprintf("%s/api/users/validate?invited_email=%s", p.plexTV(), usernameOrEmail)
 
-	resp, err := p.post(query, nil, p.Headers)
+	resp, err := p.post(ctx, query, nil, p.Headers)
 
 	if err != nil {
 		return false, err
@@ -749,12 +845,12 @@ func (p *Plex) CheckUsernameOrEmail(usernameOrEmail string) (bool, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
-		return false, errors.New(resp.Status)
+		return false, newAPIError(resp)
 	}
 
 	result := new(resultResponse)
 
-	if err := xml.NewDecoder(resp.Body).Decode(result); err != nil {
+	if err := decodeResponse(resp, result); err != nil {
 		return false, err
 	}
 
@@ -763,21 +859,28 @@ func (p *Plex) CheckUsernameOrEmail(usernameOrEmail string) (bool, error) {
 
 // StopPlayback acts as a remote controller and sends the 'stop' command
 func (p *Plex) StopPlayback(machineID string) error {
+	return p.StopPlaybackCtx(context.Background(), machineID)
+}
+
+// StopPlaybackCtx is like StopPlayback but carries ctx for cancellation and deadlines
+func (p *Plex) StopPlaybackCtx(ctx context.Context, machineID string) error {
 	query := p.URL + "/player/playback/stop"
 
 	newHeaders := p.Headers
 
-	newHeaders.Accept = "application/xml"
+	newHeaders.Accept = mimeXML
 	newHeaders.TargetClientIdentifier = machineID
 
-	resp, err := p.get(query, newHeaders)
+	resp, err := p.get(mutating(ctx), query, newHeaders)
 
 	if err != nil {
 		return err
 	}
 
+	defer resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf(resp.Status)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -785,9 +888,14 @@ func (p *Plex) StopPlayback(machineID string) error {
 
 // GetDevices returns a list of your Plex devices (servers, players, controllers, etc)
 func (p *Plex) GetDevices() ([]PMSDevices, error) {
-	query := plexURL + "/api/resources?includeHttps=1"
+	return p.GetDevicesCtx(context.Background())
+}
+
+// GetDevicesCtx is like GetDevices but carries ctx for cancellation and deadlines
+func (p *Plex) GetDevicesCtx(ctx context.Context) ([]PMSDevices, error) {
+	query := p.plexTV() + "/api/resources?includeHttps=1"
 
-	resp, err := p.get(query, p.Headers)
+	resp, err := p.get(ctx, query, p.Headers)
 
 	if err != nil {
 		return []PMSDevices{}, err
@@ -798,10 +906,10 @@ func (p *Plex) GetDevices() ([]PMSDevices, error) {
 	result := new(resourcesResponse)
 
 	if resp.StatusCode != http.StatusOK {
-		return []PMSDevices{}, errors.New(resp.Status)
+		return []PMSDevices{}, newAPIError(resp)
 	}
 
-	if err := xml.NewDecoder(resp.Body).Decode(result); err != nil {
+	if err := decodeResponse(resp, result); err != nil {
 		fmt.Println(err.Error())
 
 		return []PMSDevices{}, err
@@ -812,12 +920,17 @@ func (p *Plex) GetDevices() ([]PMSDevices, error) {
 
 // GetServers returns a list of your Plex servers
 func (p *Plex) GetServers() ([]PMSDevices, error) {
+	return p.GetServersCtx(context.Background())
+}
+
+// GetServersCtx is like GetServers but carries ctx for cancellation and deadlines
+func (p *Plex) GetServersCtx(ctx context.Context) ([]PMSDevices, error) {
 
 	// we can use the https://<pms-ip>/media/providers endpoint
 	// but if the caller does not know the ip beforehand, we can grab it
 	// from plex.tv so we'll use https://plex.tv endpoint to give that option
 
-	devices, err := p.GetDevices()
+	devices, err := p.GetDevicesCtx(ctx)
 
 	if err != nil {
 		return devices, err
@@ -839,9 +952,14 @@ func (p *Plex) GetServers() ([]PMSDevices, error) {
 
 // GetServersInfo returns info about all of your Plex servers
 func (p *Plex) GetServersInfo() (ServerInfo, error) {
-	query := plexURL + "/api/servers"
+	return p.GetServersInfoCtx(context.Background())
+}
 
-	resp, err := p.get(query, p.Headers)
+// GetServersInfoCtx is like GetServersInfo but carries ctx for cancellation and deadlines
+func (p *Plex) GetServersInfoCtx(ctx context.Context) (ServerInfo, error) {
+	query := p.plexTV() + "/api/servers"
+
+	resp, err := p.get(ctx, query, p.Headers)
 
 	if err != nil {
 		return ServerInfo{}, err
@@ -850,12 +968,12 @@ func (p *Plex) GetServersInfo() (ServerInfo, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return ServerInfo{}, errors.New(resp.Status)
+		return ServerInfo{}, newAPIError(resp)
 	}
 
 	result := ServerInfo{}
 
-	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := decodeResponse(resp, &result); err != nil {
 		fmt.Println(err.Error())
 
 		return ServerInfo{}, err
@@ -866,11 +984,16 @@ func (p *Plex) GetServersInfo() (ServerInfo, error) {
 
 // GetMachineID returns the machine id of the server with the associated access token
 func (p *Plex) GetMachineID() (string, error) {
+	return p.GetMachineIDCtx(context.Background())
+}
+
+// GetMachineIDCtx is like GetMachineID but carries ctx for cancellation and deadlines
+func (p *Plex) GetMachineIDCtx(ctx context.Context) (string, error) {
 	if p.Token == "" {
 		return "", errors.New("a token is required to fetch machine id")
 	}
 
-	servers, err := p.GetServersInfo()
+	servers, err := p.GetServersInfoCtx(ctx)
 
 	if err != nil {
 		return "", err
@@ -894,13 +1017,18 @@ func (p *Plex) GetMachineID() (string, error) {
 // GetSections of your plex server. This is useful when inviting a user
 // as you can restrict the invited user to a library (i.e. Movie's, TV Shows)
 func (p *Plex) GetSections(machineID string) ([]ServerSections, error) {
-	query := fmt.Sprintf("%s/api/servers/%s", plexURL, machineID)
+	return p.GetSectionsCtx(context.Background(), machineID)
+}
+
+// GetSectionsCtx is like GetSections but carries ctx for cancellation and deadlines
+func (p *Plex) GetSectionsCtx(ctx context.Context, machineID string) ([]ServerSections, error) {
+	query := fmt.Sprintf("%s/api/servers/%s", p.plexTV(), machineID)
 
 	newHeaders := p.Headers
 
-	newHeaders.Accept = "application/xml"
+	newHeaders.Accept = mimeXML
 
-	resp, err := p.get(query, newHeaders)
+	resp, err := p.get(ctx, query, newHeaders)
 
 	if err != nil {
 		return []ServerSections{}, err
@@ -908,9 +1036,13 @@ func (p *Plex) GetSections(machineID string) ([]ServerSections, error) {
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return []ServerSections{}, newAPIError(resp)
+	}
+
 	var result SectionIDResponse
 
-	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := decodeResponse(resp, &result); err != nil {
 		fmt.Println(err.Error())
 
 		return []ServerSections{}, err
@@ -931,9 +1063,14 @@ func (p *Plex) GetSections(machineID string) ([]ServerSections, error) {
 // GetLibraries of your Plex server. My ideal use-case would be
 // to get library count to determine label index
 func (p *Plex) GetLibraries() (LibrarySections, error) {
+	return p.GetLibrariesCtx(context.Background())
+}
+
+// GetLibrariesCtx is like GetLibraries but carries ctx for cancellation and deadlines
+func (p *Plex) GetLibrariesCtx(ctx context.Context) (LibrarySections, error) {
 	query := fmt.Sprintf("%s/library/sections", p.URL)
 
-	resp, err := p.get(query, p.Headers)
+	resp, err := p.get(ctx, query, p.Headers)
 
 	if err != nil {
 		return LibrarySections{}, err
@@ -942,12 +1079,12 @@ func (p *Plex) GetLibraries() (LibrarySections, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return LibrarySections{}, errors.New(resp.Status)
+		return LibrarySections{}, newAPIError(resp)
 	}
 
 	var result LibrarySections
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := decodeResponse(resp, &result); err != nil {
 		fmt.Println(err.Error())
 
 		return LibrarySections{}, err
@@ -956,33 +1093,31 @@ func (p *Plex) GetLibraries() (LibrarySections, error) {
 	return result, nil
 }
 
-// GetLibraryContent retrieve the content inside a library
+// GetLibraryContent retrieve the content inside a library. filter is appended to the
+// query as is, see QueryLibrary for a typed and validated alternative
 func (p *Plex) GetLibraryContent(sectionKey string, filter string) (SearchResults, error) {
+	return p.GetLibraryContentCtx(context.Background(), sectionKey, filter)
+}
+
+// GetLibraryContentCtx is like GetLibraryContent but carries ctx for cancellation and deadlines
+func (p *Plex) GetLibraryContentCtx(ctx context.Context, sectionKey string, filter string) (SearchResults, error) {
 	query := fmt.Sprintf("%s/library/sections/%s/all%s", p.URL, sectionKey, filter)
 
-	resp, err := p.get(query, p.Headers)
+	resp, err := p.get(ctx, query, p.Headers)
 
 	if err != nil {
 		return SearchResults{}, err
 	}
 
-	if resp.Status == ErrorInvalidToken {
-		return SearchResults{}, errors.New("invalid token")
-	}
-
-	if resp.StatusCode == http.StatusUnauthorized {
-		return SearchResults{}, errors.New(ErrorNotAuthorized)
-	}
+	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusBadRequest {
-		return SearchResults{}, errors.New("there was an error in the request")
+	if resp.StatusCode != http.StatusOK {
+		return SearchResults{}, newAPIError(resp)
 	}
 
-	defer resp.Body.Close()
-
 	var results SearchResults
 
-	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+	if err := decodeResponse(resp, &results); err != nil {
 		return SearchResults{}, err
 	}
 
@@ -991,12 +1126,17 @@ func (p *Plex) GetLibraryContent(sectionKey string, filter string) (SearchResult
 
 // CreateLibrary will create a new library on your Plex server
 func (p *Plex) CreateLibrary(params CreateLibraryParams) error {
+	return p.CreateLibraryCtx(context.Background(), params)
+}
+
+// CreateLibraryCtx is like CreateLibrary but carries ctx for cancellation and deadlines
+func (p *Plex) CreateLibraryCtx(ctx context.Context, params CreateLibraryParams) error {
 	// all params are required
 	if params.Name == "" {
 		return errors.New("name is required")
 	}
 
-	if params.Location == "" {
+	if params.Location == "" && len(params.Locations) == 0 {
 		return errors.New("location is required")
 	}
 
@@ -1027,17 +1167,26 @@ func (p *Plex) CreateLibrary(params CreateLibraryParams) error {
 	queryValues := parsedQuery.Query()
 
 	queryValues.Add("name", params.Name)
-	queryValues.Add("location", params.Location)
 	queryValues.Add("language", params.Language)
 	queryValues.Add("type", params.LibraryType)
 	queryValues.Add("agent", params.Agent)
 	queryValues.Add("scanner", params.Scanner)
 
+	if params.Location != "" {
+		queryValues.Add("location", params.Location)
+	}
+
+	for _, location := range params.Locations {
+		queryValues.Add("location", location)
+	}
+
+	addLibraryPrefs(queryValues, params.Prefs)
+
 	parsedQuery.RawQuery = queryValues.Encode()
 
 	query = parsedQuery.String()
 
-	resp, err := p.post(query, nil, p.Headers)
+	resp, err := p.post(ctx, query, nil, p.Headers)
 
 	if err != nil {
 		return err
@@ -1046,7 +1195,7 @@ func (p *Plex) CreateLibrary(params CreateLibraryParams) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New(resp.Status)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -1054,9 +1203,14 @@ func (p *Plex) CreateLibrary(params CreateLibraryParams) error {
 
 // DeleteLibrary removes the library from your Plex server via library key (or id)
 func (p *Plex) DeleteLibrary(key string) error {
+	return p.DeleteLibraryCtx(context.Background(), key)
+}
+
+// DeleteLibraryCtx is like DeleteLibrary but carries ctx for cancellation and deadlines
+func (p *Plex) DeleteLibraryCtx(ctx context.Context, key string) error {
 	query := fmt.Sprintf("%s/library/sections/%s", p.URL, key)
 
-	resp, err := p.delete(query, p.Headers)
+	resp, err := p.delete(ctx, query, p.Headers)
 
 	if err != nil {
 		return err
@@ -1065,14 +1219,50 @@ func (p *Plex) DeleteLibrary(key string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New(resp.Status)
+		return newAPIError(resp)
 	}
 
 	return nil
 }
 
+// DeleteMetadata deletes an item with its children and their media files from disk.
+// The server refuses unless its allowMediaDeletion preference is enabled.
+// See WithDryRun to review the deletions of a script before running it
+func (p *Plex) DeleteMetadata(ratingKey string) error {
+	return p.DeleteMetadataCtx(context.Background(), ratingKey)
+}
+
+// DeleteMetadataCtx is like DeleteMetadata but carries ctx for cancellation and deadlines
+func (p *Plex) DeleteMetadataCtx(ctx context.Context, ratingKey string) error {
+	if ratingKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(ctx, http.MethodDelete, "/library/metadata/"+ratingKey, nil)
+}
+
+// DeleteMediaVersion deletes a single version of an item, i.e. a duplicate in a lower
+// resolution, and its files from disk. mediaID is the ID of one of the item's Media
+func (p *Plex) DeleteMediaVersion(ratingKey, mediaID string) error {
+	return p.DeleteMediaVersionCtx(context.Background(), ratingKey, mediaID)
+}
+
+// DeleteMediaVersionCtx is like DeleteMediaVersion but carries ctx for cancellation and deadlines
+func (p *Plex) DeleteMediaVersionCtx(ctx context.Context, ratingKey, mediaID string) error {
+	if ratingKey == "" || mediaID == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(ctx, http.MethodDelete, "/library/metadata/"+ratingKey+"/media/"+mediaID, nil)
+}
+
 // GetLibraryLabels of your plex server
 func (p *Plex) GetLibraryLabels(sectionKey, sectionIndex string) (LibraryLabels, error) {
+	return p.GetLibraryLabelsCtx(context.Background(), sectionKey, sectionIndex)
+}
+
+// GetLibraryLabelsCtx is like GetLibraryLabels but carries ctx for cancellation and deadlines
+func (p *Plex) GetLibraryLabelsCtx(ctx context.Context, sectionKey, sectionIndex string) (LibraryLabels, error) {
 
 	if sectionIndex == "" {
 		sectionIndex = "1"
@@ -1080,7 +1270,7 @@ func (p *Plex) GetLibraryLabels(sectionKey, sectionIndex string) (LibraryLabels,
 
 	query := fmt.Sprintf("%s/library/sections/%s/labels?type=%s", p.URL, sectionKey, sectionIndex)
 
-	resp, err := p.get(query, p.Headers)
+	resp, err := p.get(ctx, query, p.Headers)
 
 	if err != nil {
 		return LibraryLabels{}, err
@@ -1089,12 +1279,12 @@ func (p *Plex) GetLibraryLabels(sectionKey, sectionIndex string) (LibraryLabels,
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return LibraryLabels{}, errors.New(resp.Status)
+		return LibraryLabels{}, newAPIError(resp)
 	}
 
 	var result LibraryLabels
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := decodeResponse(resp, &result); err != nil {
 		fmt.Println(err.Error())
 
 		return LibraryLabels{}, err
@@ -1103,11 +1293,19 @@ func (p *Plex) GetLibraryLabels(sectionKey, sectionIndex string) (LibraryLabels,
 	return result, nil
 }
 
-// AddLabelToMedia restrict access to certain media. Requires a Plex Pass.
+// AddLabelToMedia restrict access to certain media. Requires a Plex Pass and fails with ErrPlexPassRequired without one.
 // mediaType is the media type (1), id is the ratingKey or media id, label is your label, locked is unknown
 // 1. A reference to the plex media types: https://github.com/Arcanemagus/plex-api/wiki/MediaTypes
 // XXX: Currently plex is capitalizing the first letter
 func (p *Plex) AddLabelToMedia(mediaType, sectionID, id, label, locked string) (bool, error) {
+	return p.AddLabelToMediaCtx(context.Background(), mediaType, sectionID, id, label, locked)
+}
+
+// AddLabelToMediaCtx is like AddLabelToMedia but carries ctx for cancellation and deadlines
+func (p *Plex) AddLabelToMediaCtx(ctx context.Context, mediaType, sectionID, id, label, locked string) (bool, error) {
+	if err := p.requirePlexPass(ctx, "add label"); err != nil {
+		return false, err
+	}
 
 	query := fmt.Sprintf("%s/library/sections/%s/all", p.URL, sectionID)
 
@@ -1128,7 +1326,7 @@ func (p *Plex) AddLabelToMedia(mediaType, sectionID, id, label, locked string) (
 
 	query = parsedQuery.String()
 
-	resp, err := p.put(query, nil, p.Headers)
+	resp, err := p.put(ctx, query, nil, p.Headers)
 
 	if err != nil {
 		return false, err
@@ -1136,11 +1334,23 @@ func (p *Plex) AddLabelToMedia(mediaType, sectionID, id, label, locked string) (
 
 	defer resp.Body.Close()
 
-	return resp.StatusCode == http.StatusOK, nil
+	if resp.StatusCode != http.StatusOK {
+		return false, newAPIError(resp)
+	}
+
+	return true, nil
 }
 
-// RemoveLabelFromMedia to remove a label from a piece of media Requires a Plex Pass.
+// RemoveLabelFromMedia removes a label from a piece of media. Requires a Plex Pass and fails with ErrPlexPassRequired without one.
 func (p *Plex) RemoveLabelFromMedia(mediaType, sectionID, id, label, locked string) (bool, error) {
+	return p.RemoveLabelFromMediaCtx(context.Background(), mediaType, sectionID, id, label, locked)
+}
+
+// RemoveLabelFromMediaCtx is like RemoveLabelFromMedia but carries ctx for cancellation and deadlines
+func (p *Plex) RemoveLabelFromMediaCtx(ctx context.Context, mediaType, sectionID, id, label, locked string) (bool, error) {
+	if err := p.requirePlexPass(ctx, "remove label"); err != nil {
+		return false, err
+	}
 
 	query := fmt.Sprintf("%s/library/sections/%s/all", p.URL, sectionID)
 
@@ -1161,7 +1371,7 @@ func (p *Plex) RemoveLabelFromMedia(mediaType, sectionID, id, label, locked stri
 
 	query = parsedQuery.String()
 
-	resp, err := p.put(query, nil, p.Headers)
+	resp, err := p.put(ctx, query, nil, p.Headers)
 
 	if err != nil {
 		return false, err
@@ -1169,16 +1379,25 @@ func (p *Plex) RemoveLabelFromMedia(mediaType, sectionID, id, label, locked stri
 
 	defer resp.Body.Close()
 
-	return resp.StatusCode == http.StatusOK, nil
+	if resp.StatusCode != http.StatusOK {
+		return false, newAPIError(resp)
+	}
+
+	return true, nil
 }
 
 // GetSessions of devices currently consuming media
 func (p *Plex) GetSessions() (CurrentSessions, error) {
+	return p.GetSessionsCtx(context.Background())
+}
+
+// GetSessionsCtx is like GetSessions but carries ctx for cancellation and deadlines
+func (p *Plex) GetSessionsCtx(ctx context.Context) (CurrentSessions, error) {
 	newHeaders := p.Headers
 
 	query := fmt.Sprintf("%s/status/sessions", p.URL)
 
-	resp, err := p.get(query, newHeaders)
+	resp, err := p.get(ctx, query, newHeaders)
 
 	if err != nil {
 		return CurrentSessions{}, err
@@ -1187,20 +1406,30 @@ func (p *Plex) GetSessions() (CurrentSessions, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return CurrentSessions{}, errors.New(resp.Status)
+		return CurrentSessions{}, newAPIError(resp)
 	}
 
 	var result CurrentSessions
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := decodeResponse(resp, &result); err != nil {
 		return CurrentSessions{}, err
 	}
 
 	return result, nil
 }
 
-// TerminateSession will end a streaming session - plex pass feature
+// TerminateSession will end a streaming session - plex pass feature.
+// Without a Plex Pass it fails with ErrPlexPassRequired before asking the server to end it
 func (p *Plex) TerminateSession(sessionID string, reason string) error {
+	return p.TerminateSessionCtx(context.Background(), sessionID, reason)
+}
+
+// TerminateSessionCtx is like TerminateSession but carries ctx for cancellation and deadlines
+func (p *Plex) TerminateSessionCtx(ctx context.Context, sessionID string, reason string) error {
+	if err := p.requirePlexPass(ctx, "terminate session"); err != nil {
+		return err
+	}
+
 	if reason == "" {
 		reason = "The server owner has ended the stream"
 	}
@@ -1211,9 +1440,9 @@ func (p *Plex) TerminateSession(sessionID string, reason string) error {
 	query := fmt.Sprintf("%s/status/sessions/terminate?sessionId=%s&reason=%s", p.URL, sessionID, reason)
 
 	newHeaders := p.Headers
-	newHeaders.Accept = "application/xml"
+	newHeaders.Accept = mimeXML
 
-	resp, err := p.get(query, newHeaders)
+	resp, err := p.get(mutating(ctx), query, newHeaders)
 
 	if err != nil {
 		return err
@@ -1222,7 +1451,7 @@ func (p *Plex) TerminateSession(sessionID string, reason string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("%s", resp.Status)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -1,14 +1,19 @@
 package plex
 
 import (
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/jrudio/go-plex-client/plextest"
 )
 
 var (
@@ -44,15 +49,46 @@ func newTestServer(code int, body string) (*httptest.Server, *Plex) {
 	}
 
 	httpClient := http.Client{Transport: transport}
-	plex := &Plex{URL: server.URL, Token: "", HTTPClient: httpClient}
+	plex := &Plex{URL: server.URL, PlexTVURL: server.URL, Token: "", HTTPClient: httpClient}
 
 	return server, plex
 }
 
+// newTestConn starts a fake server with fixtures and returns a connection to it,
+// which also uses the fake for plex.tv. The server is closed when the test ends
+func newTestConn(t *testing.T, fixtures plextest.Fixtures, opts ...Option) (*Plex, *plextest.Server) {
+	t.Helper()
+
+	server := plextest.NewServer(fixtures)
+	t.Cleanup(server.Close)
+
+	conn, err := New(server.URL, server.Token(), append([]Option{WithPlexTVURL(server.URL)}, opts...)...)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn, server
+}
+
 func TestSignIn(t *testing.T) {
 	username := os.Getenv("PLEX_USERNAME")
 	password := os.Getenv("PLEX_PASSWORD")
-	plex, err := SignIn(username, password)
+
+	var opts []Option
+
+	// fall back to the fake plex.tv when no account is configured
+	if username == "" {
+		fixtures := plextest.DefaultFixtures()
+
+		server := plextest.NewServer(fixtures)
+		defer server.Close()
+
+		username, password = fixtures.Account.Username, fixtures.Account.Password
+		opts = append(opts, WithPlexTVURL(server.URL))
+	}
+
+	plex, err := SignIn(username, password, opts...)
 
 	if err != nil {
 		t.Error(err.Error())
@@ -129,19 +165,49 @@ func TestGetMetadata(t *testing.T) {
 }
 
 func TestGetServersInfo(t *testing.T) {
-	if plexConn == nil {
-		t.Error("GetServerInfo requires a plex connection")
-		return
+	conn := plexConn
+
+	// fall back to the fake plex.tv when no server is configured
+	if conn == nil {
+		conn, _ = newTestConn(t, plextest.DefaultFixtures())
 	}
 
-	info, err := plexConn.GetServersInfo()
+	info, err := conn.GetServersInfo()
 
 	if err != nil {
 		t.Error(err.Error())
 		return
 	}
 
-	fmt.Println(info.Size)
+	if info.Size == 0 || len(info.Server) == 0 {
+		t.Error("expected at least one server")
+	}
+}
+
+func TestGetMachineID(t *testing.T) {
+	fixtures := plextest.DefaultFixtures()
+
+	conn, _ := newTestConn(t, fixtures)
+
+	machineID, err := conn.GetMachineID()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if machineID != fixtures.MachineIdentifier {
+		t.Errorf("expected machine id %s, got %s", fixtures.MachineIdentifier, machineID)
+	}
+
+	sections, err := conn.GetSections(machineID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sections) != len(fixtures.Libraries) {
+		t.Errorf("expected %d sections, got %d", len(fixtures.Libraries), len(sections))
+	}
 }
 
 func TestCheckUsernameOrEmailResponse(t *testing.T) {
@@ -191,3 +257,58 @@ func TestInviteFriendResponse(t *testing.T) {
 		t.Error(err.Error())
 	}
 }
+
+func TestGetSessionsCtxCanceled(t *testing.T) {
+	_, _plex := newTestServer(200, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := _plex.GetSessionsCtx(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	_, _plex := newTestServer(500, "")
+
+	var order []string
+
+	_plex.Use(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			order = append(order, "outer")
+			req.Header.Set("X-Plex-Product", "middleware test")
+
+			return next.RoundTrip(req)
+		})
+	}, func(next http.RoundTripper) http.RoundTripper {
+		// answer the request without reaching the server
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			order = append(order, "inner")
+
+			if product := req.Header.Get("X-Plex-Product"); product != "middleware test" {
+				t.Errorf("expected header set by outer middleware, got %q", product)
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"MediaContainer":{"size":1}}`)),
+			}, nil
+		})
+	})
+
+	sessions, err := _plex.GetSessions()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sessions.MediaContainer.Size != 1 {
+		t.Errorf("expected size 1 from middleware response, got %d", sessions.MediaContainer.Size)
+	}
+
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Errorf("unexpected middleware order: %v", order)
+	}
+}
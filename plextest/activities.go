@@ -0,0 +1,177 @@
+package plextest
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Activity is a background task of the server, like a library scan, sent to
+// notification subscribers with NotifyActivity. The ones in Fixtures.Activities
+// are running and served by /activities
+type Activity struct {
+	UUID        string `json:"uuid"`
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	Subtitle    string `json:"subtitle"`
+	Progress    int    `json:"progress"`
+	Cancellable bool   `json:"cancellable"`
+	// SectionKey is the library the activity works on, if any
+	SectionKey string `json:"sectionKey"`
+}
+
+// NotifyActivity sends an "activity" notification. event is one of started, updated or ended
+func (s *Server) NotifyActivity(event string, activity Activity) error {
+	a := map[string]interface{}{
+		"uuid":        activity.UUID,
+		"type":        activity.Type,
+		"title":       activity.Title,
+		"subtitle":    activity.Subtitle,
+		"progress":    activity.Progress,
+		"cancellable": activity.Cancellable,
+		"userID":      s.Fixtures().Account.ID,
+	}
+
+	if activity.SectionKey != "" {
+		a["Context"] = map[string]interface{}{
+			"key":              "/library/sections/" + activity.SectionKey,
+			"librarySectionID": activity.SectionKey,
+		}
+	}
+
+	return s.Notify(map[string]interface{}{
+		"type": "activity",
+		"size": 1,
+		"ActivityNotification": []interface{}{
+			map[string]interface{}{
+				"event":    event,
+				"uuid":     activity.UUID,
+				"Activity": a,
+			},
+		},
+	})
+}
+
+// handleRefreshLibrary starts a scan that reports its progress to notification
+// subscribers and ends right away, see runActivity
+func (s *Server) handleRefreshLibrary(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findLibrary(params["section"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	library := s.fixtures.Libraries[ii]
+
+	activity := Activity{
+		Type:        "library.update.section",
+		Title:       "Scanning " + library.Title,
+		Subtitle:    r.URL.Query().Get("path"),
+		Cancellable: true,
+		SectionKey:  library.Key,
+	}
+
+	if r.URL.Query().Get("force") == "1" {
+		activity.Type = "library.refresh.items"
+		activity.Title = "Refreshing " + library.Title
+	}
+
+	s.runActivity(activity)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// runActivity reports activity to notification subscribers as started, half way done and ended.
+// Handlers hold the lock, so the notifications are sent once it is released
+func (s *Server) runActivity(activity Activity) {
+	if activity.UUID == "" {
+		activity.UUID = "plextest-activity-" + strconv.Itoa(s.newID())
+	}
+
+	go func() {
+		for _, event := range []string{"started", "updated", "ended"} {
+			if event == "updated" {
+				activity.Progress = 50
+			}
+
+			if event == "ended" {
+				activity.Progress = 100
+			}
+
+			s.NotifyActivity(event, activity)
+		}
+	}()
+}
+
+func (s *Server) handleCancelRefreshLibrary(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if _, ok := s.findLibrary(params["section"]); !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) findActivity(uuid string) (int, bool) {
+	for ii, activity := range s.fixtures.Activities {
+		if activity.UUID == uuid {
+			return ii, true
+		}
+	}
+
+	return -1, false
+}
+
+func (s *Server) activityElement(activity Activity) *element {
+	el := newElement("Activity",
+		"uuid", activity.UUID,
+		"type", activity.Type,
+		"cancellable", activity.Cancellable,
+		"userID", s.fixtures.Account.ID,
+		"title", activity.Title,
+		"subtitle", activity.Subtitle,
+		"progress", activity.Progress,
+	)
+
+	if activity.SectionKey != "" {
+		el.addOne("Context", newElement("Context",
+			"key", "/library/sections/"+activity.SectionKey,
+			"librarySectionID", activity.SectionKey,
+		))
+	}
+
+	return el
+}
+
+func (s *Server) handleActivities(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	root := s.container("size", len(s.fixtures.Activities))
+
+	for _, activity := range s.fixtures.Activities {
+		root.add("Activity", s.activityElement(activity))
+	}
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+// handleCancelActivity stops a running activity, which subscribers see as ended
+func (s *Server) handleCancelActivity(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findActivity(params["uuid"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	activity := s.fixtures.Activities[ii]
+
+	if !activity.Cancellable {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	s.fixtures.Activities = append(s.fixtures.Activities[:ii], s.fixtures.Activities[ii+1:]...)
+
+	go s.NotifyActivity("ended", activity)
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,297 @@
+package plextest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func (s *Server) findCollection(ratingKey string) (int, bool) {
+	for ii, collection := range s.fixtures.Collections {
+		if collection.RatingKey == ratingKey {
+			return ii, true
+		}
+	}
+
+	return -1, false
+}
+
+// collectionItems returns the items of a collection: the library content matching the
+// filter of a smart collection or the items tagged with the title of a regular one
+func (s *Server) collectionItems(collection Collection) []Item {
+	ii, ok := s.findLibrary(collection.LibraryKey)
+
+	if !ok {
+		return nil
+	}
+
+	library := s.fixtures.Libraries[ii]
+
+	if collection.Smart {
+		query, _ := url.ParseQuery(collection.Filter)
+
+		return s.libraryItems(library, query)
+	}
+
+	var items []Item
+
+	for _, item := range s.fixtures.Items {
+		if item.LibraryKey == library.Key && containsFold(item.Collections, collection.Title) {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func (s *Server) collectionElement(collection Collection) *element {
+	thumb := collection.Poster
+
+	if thumb == "" {
+		thumb = "/library/collections/" + collection.RatingKey + "/composite/1600000000"
+	}
+
+	libraryID, _ := strconv.Atoi(collection.LibraryKey)
+
+	e := newElement("Directory",
+		"ratingKey", collection.RatingKey,
+		"key", "/library/collections/"+collection.RatingKey+"/children",
+		"guid", "collection://plextest-collection-"+collection.RatingKey,
+		"type", "collection",
+		"title", collection.Title,
+		"summary", collection.Summary,
+		"subtype", collection.Type,
+		"smart", collection.Smart,
+		"childCount", len(s.collectionItems(collection)),
+		"collectionMode", collection.Mode,
+		"collectionSort", collection.Sort,
+		"thumb", thumb,
+		"librarySectionID", libraryID,
+	)
+
+	if collection.Smart {
+		e.set("content", "/library/sections/"+collection.LibraryKey+"/all?"+collection.Filter)
+	}
+
+	return e
+}
+
+func (s *Server) handleCollections(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if _, ok := s.findLibrary(params["section"]); !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	root := s.container()
+
+	for _, collection := range s.fixtures.Collections {
+		if collection.LibraryKey == params["section"] {
+			root.add("Metadata", s.collectionElement(collection))
+		}
+	}
+
+	writeContainer(w, r, http.StatusOK, root.set("size", len(root.children)))
+}
+
+func (s *Server) handleCreateCollection(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	query := r.URL.Query()
+
+	collection := Collection{
+		RatingKey:  strconv.Itoa(s.newID()),
+		LibraryKey: query.Get("sectionId"),
+		Title:      query.Get("title"),
+		Mode:       -1,
+	}
+
+	for name, id := range metadataTypes {
+		if strconv.Itoa(id) == query.Get("type") {
+			collection.Type = name
+		}
+	}
+
+	if _, ok := s.findLibrary(collection.LibraryKey); !ok || collection.Title == "" || collection.Type == "" {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	if query.Get("smart") == "1" {
+		sectionKey, filter, ok := s.uriSection(query.Get("uri"))
+
+		if !ok || sectionKey != collection.LibraryKey {
+			writeError(w, r, http.StatusBadRequest, "Bad Request")
+			return
+		}
+
+		collection.Smart, collection.Filter = true, filter
+	} else {
+		ratingKeys, ok := s.uriRatingKeys(query.Get("uri"))
+
+		if !ok {
+			writeError(w, r, http.StatusBadRequest, "Bad Request")
+			return
+		}
+
+		s.tagCollection(collection.Title, ratingKeys)
+	}
+
+	s.fixtures.Collections = append(s.fixtures.Collections, collection)
+
+	root := s.container("size", 1)
+
+	writeContainer(w, r, http.StatusOK, root.add("Metadata", s.collectionElement(collection)))
+}
+
+// tagCollection adds the collection tag to the items with ratingKeys
+func (s *Server) tagCollection(title string, ratingKeys []string) {
+	for _, ratingKey := range ratingKeys {
+		ii, _ := s.findItem(ratingKey)
+
+		if !containsFold(s.fixtures.Items[ii].Collections, title) {
+			s.fixtures.Items[ii].Collections = append(s.fixtures.Items[ii].Collections, title)
+		}
+	}
+}
+
+func (s *Server) handleDeleteCollection(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findCollection(params["collection"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	collection := s.fixtures.Collections[ii]
+
+	if !collection.Smart {
+		for jj := range s.fixtures.Items {
+			s.fixtures.Items[jj].Collections = removeFold(s.fixtures.Items[jj].Collections, collection.Title)
+		}
+	}
+
+	s.fixtures.Collections = append(s.fixtures.Collections[:ii], s.fixtures.Collections[ii+1:]...)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleCollectionItems(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findCollection(params["collection"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	collection := s.fixtures.Collections[ii]
+
+	root := s.container(
+		"key", collection.RatingKey,
+		"title1", collection.Title,
+	)
+
+	s.writeItems(w, r, root, s.collectionItems(collection))
+}
+
+// editableCollection returns the regular collection of the request, smart collections cannot be edited by item
+func (s *Server) editableCollection(w http.ResponseWriter, r *http.Request, ratingKey string) (Collection, bool) {
+	ii, ok := s.findCollection(ratingKey)
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return Collection{}, false
+	}
+
+	if s.fixtures.Collections[ii].Smart {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return Collection{}, false
+	}
+
+	return s.fixtures.Collections[ii], true
+}
+
+func (s *Server) handleAddCollectionItems(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	collection, ok := s.editableCollection(w, r, params["collection"])
+
+	if !ok {
+		return
+	}
+
+	ratingKeys, ok := s.uriRatingKeys(r.URL.Query().Get("uri"))
+
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	s.tagCollection(collection.Title, ratingKeys)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleRemoveCollectionItem(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	collection, ok := s.editableCollection(w, r, params["collection"])
+
+	if !ok {
+		return
+	}
+
+	ii, ok := s.findItem(params["ratingKey"])
+
+	if !ok || !containsFold(s.fixtures.Items[ii].Collections, collection.Title) {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	s.fixtures.Items[ii].Collections = removeFold(s.fixtures.Items[ii].Collections, collection.Title)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleCollectionPrefs(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findCollection(params["ratingKey"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	collection := &s.fixtures.Collections[ii]
+
+	for name, target := range map[string]*int{"collectionMode": &collection.Mode, "collectionSort": &collection.Sort} {
+		value := r.URL.Query().Get(name)
+
+		if value == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(value)
+
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "Bad Request")
+			return
+		}
+
+		*target = n
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleCollectionPoster(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findCollection(params["ratingKey"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	poster := r.URL.Query().Get("url")
+
+	if poster == "" {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	s.fixtures.Collections[ii].Poster = poster
+
+	w.WriteHeader(http.StatusOK)
+}
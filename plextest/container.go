@@ -0,0 +1,175 @@
+package plextest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// element is a node of a plex response. Plex Media Server renders the same tree
+// as xml or json depending on the Accept header: attributes become json keys and
+// children are grouped in arrays, i.e. <Video/> elements become "Metadata": [...]
+type element struct {
+	name     string
+	jsonKey  string
+	attrs    []attr
+	children []*element
+	// single children are a json object instead of an array, like the Player of a session
+	single bool
+}
+
+type attr struct {
+	key   string
+	value interface{}
+}
+
+// newElement returns an element with the attributes given as key, value pairs
+func newElement(name string, keyValues ...interface{}) *element {
+	e := &element{name: name, jsonKey: name}
+
+	e.set(keyValues...)
+
+	return e
+}
+
+// set appends attributes given as key, value pairs. nil values are skipped
+func (e *element) set(keyValues ...interface{}) *element {
+	for ii := 0; ii+1 < len(keyValues); ii += 2 {
+		if keyValues[ii+1] == nil {
+			continue
+		}
+
+		e.attrs = append(e.attrs, attr{key: keyValues[ii].(string), value: keyValues[ii+1]})
+	}
+
+	return e
+}
+
+// add appends children under jsonKey, which is the array they are grouped in as json
+func (e *element) add(jsonKey string, children ...*element) *element {
+	for _, child := range children {
+		child.jsonKey = jsonKey
+		e.children = append(e.children, child)
+	}
+
+	return e
+}
+
+// addOne appends a child that is rendered as a json object under jsonKey
+func (e *element) addOne(jsonKey string, child *element) *element {
+	e.add(jsonKey, child)
+	child.single = true
+
+	return e
+}
+
+func (e *element) jsonValue() map[string]interface{} {
+	value := make(map[string]interface{}, len(e.attrs)+len(e.children))
+
+	for _, a := range e.attrs {
+		value[a.key] = a.value
+	}
+
+	for _, child := range e.children {
+		if child.single {
+			value[child.jsonKey] = child.jsonValue()
+			continue
+		}
+
+		list, _ := value[child.jsonKey].([]interface{})
+		value[child.jsonKey] = append(list, child.jsonValue())
+	}
+
+	return value
+}
+
+func (e *element) encodeXML(enc *xml.Encoder) error {
+	start := xml.StartElement{Name: xml.Name{Local: e.name}}
+
+	for _, a := range e.attrs {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: a.key}, Value: xmlValue(a.value)})
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, child := range e.children {
+		if err := child.encodeXML(enc); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// xmlValue formats attributes the way plex does, with booleans as 0 or 1
+func xmlValue(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "1"
+		}
+
+		return "0"
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	}
+
+	return fmt.Sprint(value)
+}
+
+func wantsJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "json")
+}
+
+// writeContainer writes root as json when the request accepts it and as xml otherwise
+func writeContainer(w http.ResponseWriter, r *http.Request, status int, root *element) {
+	if wantsJSON(r) {
+		writeJSON(w, status, map[string]interface{}{root.name: root.jsonValue()})
+		return
+	}
+
+	writeXML(w, status, root)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeXML(w http.ResponseWriter, status int, root *element) {
+	w.Header().Set("Content-Type", "text/xml;charset=utf-8")
+	w.WriteHeader(status)
+
+	fmt.Fprint(w, xml.Header)
+
+	enc := xml.NewEncoder(w)
+
+	root.encodeXML(enc)
+	enc.Flush()
+}
+
+// writeError writes a plex error body, json for the plex.tv v2 api and xml otherwise
+func writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	if strings.HasPrefix(r.URL.Path, "/api/v2/") {
+		writeJSON(w, status, map[string]interface{}{
+			"errors": []map[string]interface{}{
+				{"code": status * 10, "message": message, "status": status},
+			},
+		})
+
+		return
+	}
+
+	writeXML(w, status, newElement("Response", "code", status, "status", message))
+}
@@ -0,0 +1,72 @@
+package plextest
+
+import (
+	"net/http"
+)
+
+// descendants returns the rating keys of the item and everything below it
+func (s *Server) descendants(ratingKey string) map[string]bool {
+	keys := map[string]bool{ratingKey: true}
+
+	for _, item := range s.fixtures.Items {
+		if item.ParentRatingKey == ratingKey {
+			for key := range s.descendants(item.RatingKey) {
+				keys[key] = true
+			}
+		}
+	}
+
+	return keys
+}
+
+// mediaDeletionAllowed writes an error unless the allowMediaDeletion setting is enabled, like plex does
+func (s *Server) mediaDeletionAllowed(w http.ResponseWriter, r *http.Request) bool {
+	if s.preference("allowMediaDeletion") != "true" {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) handleDeleteMetadata(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if !s.mediaDeletionAllowed(w, r) {
+		return
+	}
+
+	if _, ok := s.findItem(params["ratingKey"]); !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	deleted := s.descendants(params["ratingKey"])
+	items := s.fixtures.Items[:0]
+
+	for _, item := range s.fixtures.Items {
+		if !deleted[item.RatingKey] {
+			items = append(items, item)
+		}
+	}
+
+	s.fixtures.Items = items
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleDeleteMedia deletes the media of an item. Items have a single media whose id is their rating key
+func (s *Server) handleDeleteMedia(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if !s.mediaDeletionAllowed(w, r) {
+		return
+	}
+
+	ii, ok := s.findItem(params["ratingKey"])
+
+	if !ok || s.fixtures.Items[ii].File == "" || params["media"] != s.fixtures.Items[ii].RatingKey {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	s.fixtures.Items[ii].File = ""
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,213 @@
+package plextest
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// libraryFilters are the fields library content can be filtered by
+var libraryFilters = []struct {
+	filter, filterType, title string
+}{
+	{"title", "string", "Title"},
+	{"year", "integer", "Year"},
+	{"unwatched", "boolean", "Unwatched"},
+	{"inProgress", "boolean", "In Progress"},
+	{"label", "tag", "Labels"},
+}
+
+// librarySorts are the fields library content can be sorted by
+var librarySorts = []struct {
+	key, defaultDirection, title string
+}{
+	{"titleSort", "asc", "Title"},
+	{"year", "desc", "Year"},
+	{"addedAt", "desc", "Date Added"},
+}
+
+func (s *Server) handleLibraryFilters(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if _, ok := s.findLibrary(params["section"]); !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	root := s.container("size", len(libraryFilters))
+
+	for _, f := range libraryFilters {
+		root.add("Directory", newElement("Directory",
+			"filter", f.filter,
+			"filterType", f.filterType,
+			"key", "/library/sections/"+params["section"]+"/"+f.filter,
+			"title", f.title,
+			"type", "filter",
+		))
+	}
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+func (s *Server) handleLibrarySorts(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if _, ok := s.findLibrary(params["section"]); !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	root := s.container("size", len(librarySorts))
+
+	for _, sort := range librarySorts {
+		root.add("Directory", newElement("Directory",
+			"defaultDirection", sort.defaultDirection,
+			"descKey", sort.key+":desc",
+			"key", sort.key,
+			"title", sort.title,
+		))
+	}
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+// splitFilter splits a query parameter like year>>=2000 into the field, operator and value.
+// The query parser cuts at the first "=", so title==Alien arrives as "title" and "=Alien"
+func splitFilter(key, value string) (field, operator, filterValue string) {
+	field = strings.TrimRight(key, "!<>")
+	operator = key[len(field):] + "="
+
+	if strings.HasPrefix(value, "=") {
+		operator += "="
+		value = value[1:]
+	}
+
+	return field, operator, value
+}
+
+// matchesFilters reports whether item passes every filter in query. Parameters that are
+// not filters, like type or sort, are ignored
+func matchesFilters(item Item, query url.Values) bool {
+	for key, values := range query {
+		for _, value := range values {
+			field, operator, value := splitFilter(key, value)
+
+			switch field {
+			case "title":
+				if !matchString(item.Title, operator, value) {
+					return false
+				}
+			case "year":
+				if !matchInt(item.Year, operator, value) {
+					return false
+				}
+			case "unwatched":
+				if (item.ViewCount == 0) != matchBool(operator, value) {
+					return false
+				}
+			case "inProgress":
+				if (item.ViewOffset > 0) != matchBool(operator, value) {
+					return false
+				}
+			case "label":
+				if hasLabel(item, value) != (operator == "=") {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+func matchString(s, operator, value string) bool {
+	s, value = strings.ToLower(s), strings.ToLower(value)
+
+	switch operator {
+	case "=":
+		return strings.Contains(s, value)
+	case "!=":
+		return !strings.Contains(s, value)
+	case "==":
+		return s == value
+	case "!==":
+		return s != value
+	case "<=":
+		return strings.HasPrefix(s, value)
+	case ">=":
+		return strings.HasSuffix(s, value)
+	}
+
+	return false
+}
+
+func matchInt(n int, operator, value string) bool {
+	v, err := strconv.Atoi(value)
+
+	if err != nil {
+		return false
+	}
+
+	switch operator {
+	case "=":
+		return n == v
+	case "!=":
+		return n != v
+	case ">>=":
+		return n > v
+	case "<<=":
+		return n < v
+	}
+
+	return false
+}
+
+// matchBool returns the state a boolean filter asks for, so unwatched!=1 is the same as unwatched=0
+func matchBool(operator, value string) bool {
+	return (value == "1") == (operator == "=")
+}
+
+// sortItems orders items by a sort parameter like "year:desc,titleSort"
+func sortItems(items []Item, sortParam string) {
+	if sortParam == "" {
+		return
+	}
+
+	fields := strings.Split(sortParam, ",")
+
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, field := range fields {
+			key, descending := field, false
+
+			if strings.HasSuffix(key, ":desc") {
+				key, descending = strings.TrimSuffix(key, ":desc"), true
+			}
+
+			cmp := compareItems(items[i], items[j], key)
+
+			if cmp == 0 {
+				continue
+			}
+
+			return (cmp < 0) != descending
+		}
+
+		return false
+	})
+}
+
+func compareItems(a, b Item, key string) int {
+	switch key {
+	case "titleSort":
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	case "year":
+		return a.Year - b.Year
+	case "addedAt":
+		switch {
+		case a.AddedAt < b.AddedAt:
+			return -1
+		case a.AddedAt > b.AddedAt:
+			return 1
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,296 @@
+package plextest
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Fixtures seed the state of a fake server. They can be built in go, loaded from
+// json with LoadFixtures or started from DefaultFixtures and modified
+type Fixtures struct {
+	// Token is the auth token accepted by the fake Plex Media Server and plex.tv
+	Token             string       `json:"token"`
+	MachineIdentifier string       `json:"machineIdentifier"`
+	FriendlyName      string       `json:"friendlyName"`
+	Version           string       `json:"version"`
+	Account           Account      `json:"account"`
+	Libraries         []Library    `json:"libraries"`
+	Items             []Item       `json:"items"`
+	Sessions          []Session    `json:"sessions"`
+	Friends           []Friend     `json:"friends"`
+	Webhooks          []string     `json:"webhooks"`
+	Playlists         []Playlist   `json:"playlists"`
+	Collections       []Collection `json:"collections"`
+	History           []Play       `json:"history"`
+	// Matches are the metadata the fake agent finds when matching items
+	Matches []Match `json:"matches"`
+	// Preferences are the server settings served by /:/prefs
+	Preferences []Preference `json:"preferences"`
+	ButlerTasks []ButlerTask `json:"butlerTasks"`
+	// Activities are the background tasks running on the server
+	Activities []Activity `json:"activities"`
+}
+
+// Account is the plex.tv account that owns the fake server
+type Account struct {
+	ID       int    `json:"id"`
+	UUID     string `json:"uuid"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Title    string `json:"title"`
+	// Password is checked by the sign in endpoint together with Username or Email
+	Password string `json:"password"`
+	// PlexPass enables the endpoints that require a Plex Pass subscription, such as webhooks
+	PlexPass bool `json:"plexPass"`
+}
+
+// Library is a library section of the fake server
+type Library struct {
+	Key      string `json:"key"`
+	Title    string `json:"title"`
+	Type     string `json:"type"`
+	Agent    string `json:"agent"`
+	Scanner  string `json:"scanner"`
+	Language string `json:"language"`
+	// Locations are the folders of the library
+	Locations []string `json:"locations"`
+	// Prefs are the advanced settings of the library that differ from their default, by id
+	Prefs map[string]string `json:"prefs"`
+}
+
+// Item is a piece of metadata inside a library: a movie, show, season, episode, artist, album or track
+type Item struct {
+	RatingKey string `json:"ratingKey"`
+	// LibraryKey is the key of the library the item belongs to
+	LibraryKey string `json:"libraryKey"`
+	// ParentRatingKey links seasons to shows, episodes to seasons and so on
+	ParentRatingKey string `json:"parentRatingKey"`
+	Type            string `json:"type"`
+	Title           string `json:"title"`
+	Summary         string `json:"summary"`
+	Year            int    `json:"year"`
+	Index           int    `json:"index"`
+	// Duration and ViewOffset are in milliseconds
+	Duration   int64 `json:"duration"`
+	ViewOffset int64 `json:"viewOffset"`
+	ViewCount  int   `json:"viewCount"`
+	// AddedAt is in unix seconds
+	AddedAt int64    `json:"addedAt"`
+	Labels  []string `json:"labels"`
+
+	TitleSort     string `json:"titleSort"`
+	OriginalTitle string `json:"originalTitle"`
+	ContentRating string `json:"contentRating"`
+	Studio        string `json:"studio"`
+	Tagline       string `json:"tagline"`
+	// OriginallyAvailableAt is the release date as YYYY-MM-DD
+	OriginallyAvailableAt string   `json:"originallyAvailableAt"`
+	Genres                []string `json:"genres"`
+	Directors             []string `json:"directors"`
+	Writers               []string `json:"writers"`
+	Collections           []string `json:"collections"`
+	// LockedFields are the names of the fields locked by edits, i.e. title or genre
+	LockedFields []string `json:"lockedFields"`
+	// UserRating is the rating given by the account, from 0 to 10
+	UserRating float64 `json:"userRating"`
+	// HiddenFromContinueWatching leaves the item off the on deck list despite its progress
+	HiddenFromContinueWatching bool `json:"hiddenFromContinueWatching"`
+	// GUID is the id of the metadata the item is matched to. It defaults to plex://{type}/{ratingKey}
+	GUID string `json:"guid"`
+	// AltGUIDs are the ids of the item at other providers, like imdb://tt0078748
+	AltGUIDs []string `json:"altGuids"`
+	// File is the path of the media file. Items without one have no Media
+	File string `json:"file"`
+}
+
+// Playlist is a playlist of the fake server. It is smart when SectionKey is set
+type Playlist struct {
+	RatingKey string `json:"ratingKey"`
+	Title     string `json:"title"`
+	Summary   string `json:"summary"`
+	// Type is video, audio or photo
+	Type string `json:"type"`
+	// Items are the entries of a regular playlist, in order
+	Items []PlaylistItem `json:"items"`
+	// SectionKey and Filter select the library content of a smart playlist,
+	// Filter being a query like type=1&unwatched=1
+	SectionKey string `json:"sectionKey"`
+	Filter     string `json:"filter"`
+}
+
+// PlaylistItem is an entry of a playlist. The same item can be in a playlist several times
+type PlaylistItem struct {
+	ID        int    `json:"id"`
+	RatingKey string `json:"ratingKey"`
+}
+
+// Collection is a collection of a library. The items of a regular collection are the
+// items of the library tagged with its title, see Item.Collections
+type Collection struct {
+	RatingKey  string `json:"ratingKey"`
+	LibraryKey string `json:"libraryKey"`
+	Title      string `json:"title"`
+	Summary    string `json:"summary"`
+	// Type is the type of the items in the collection, i.e. movie
+	Type string `json:"type"`
+	// Smart collections hold the library content matching Filter, a query like type=1&unwatched=1
+	Smart  bool   `json:"smart"`
+	Filter string `json:"filter"`
+	// Mode and Sort are the collectionMode and collectionSort preferences
+	Mode   int    `json:"mode"`
+	Sort   int    `json:"sort"`
+	Poster string `json:"poster"`
+}
+
+// Session is a playback session of the fake server
+type Session struct {
+	// ID identifies the session when terminating it
+	ID         string `json:"id"`
+	SessionKey string `json:"sessionKey"`
+	// RatingKey is the item being played
+	RatingKey  string `json:"ratingKey"`
+	UserID     int    `json:"userID"`
+	Username   string `json:"username"`
+	Player     string `json:"player"`
+	Product    string `json:"product"`
+	State      string `json:"state"`
+	ViewOffset int64  `json:"viewOffset"`
+}
+
+// Match is metadata known to the agent of the fake server, see Fixtures.Matches
+type Match struct {
+	GUID     string   `json:"guid"`
+	Type     string   `json:"type"`
+	Title    string   `json:"title"`
+	Year     int      `json:"year"`
+	Summary  string   `json:"summary"`
+	AltGUIDs []string `json:"altGuids"`
+}
+
+// Preference is a server setting. Value and Default are formatted as the Type says:
+// true or false for bool, digits for int and double, anything for text
+type Preference struct {
+	ID       string `json:"id"`
+	Label    string `json:"label"`
+	Type     string `json:"type"`
+	Group    string `json:"group"`
+	Value    string `json:"value"`
+	Default  string `json:"default"`
+	Hidden   bool   `json:"hidden"`
+	Advanced bool   `json:"advanced"`
+	// EnumValues restricts the value to a list of value:label pairs separated by |
+	EnumValues string `json:"enumValues"`
+}
+
+// ButlerTask is a scheduled maintenance task of the server
+type ButlerTask struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	// Interval is the number of days between two runs
+	Interval int `json:"interval"`
+}
+
+// Play is an entry of the watch history of the fake server
+type Play struct {
+	ID        int    `json:"id"`
+	RatingKey string `json:"ratingKey"`
+	AccountID int    `json:"accountID"`
+	DeviceID  int    `json:"deviceID"`
+	// ViewedAt is a unix timestamp in seconds
+	ViewedAt int64 `json:"viewedAt"`
+}
+
+// Friend is a plex.tv user the server is shared with
+type Friend struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Title    string `json:"title"`
+}
+
+// LoadFixtures decodes fixtures from json. The keys match the json tags of Fixtures
+func LoadFixtures(r io.Reader) (Fixtures, error) {
+	var fixtures Fixtures
+
+	err := json.NewDecoder(r).Decode(&fixtures)
+
+	return fixtures, err
+}
+
+// DefaultFixtures returns a small server with a movie library, a tv show library,
+// a session in progress, a friend and some watch history
+func DefaultFixtures() Fixtures {
+	return Fixtures{
+		Token:             "plextest-token",
+		MachineIdentifier: "plextest-machine-id",
+		FriendlyName:      "plextest",
+		Version:           "1.32.0.0000-plextest",
+		Account: Account{
+			ID:       1,
+			UUID:     "plextest-account",
+			Username: "plextest",
+			Email:    "plextest@example.com",
+			Title:    "plextest",
+			Password: "password",
+			PlexPass: true,
+		},
+		Libraries: []Library{
+			{Key: "1", Title: "Movies", Type: "movie", Agent: "tv.plex.agents.movie", Scanner: "Plex Movie", Language: "en-US", Locations: []string{"/data/movies"}},
+			{Key: "2", Title: "TV Shows", Type: "show", Agent: "tv.plex.agents.series", Scanner: "Plex TV Series", Language: "en-US", Locations: []string{"/data/tv"}},
+		},
+		Items: []Item{
+			{RatingKey: "100", LibraryKey: "1", Type: "movie", GUID: "plex://movie/alien", AltGUIDs: []string{"imdb://tt0078748", "tmdb://348"}, Title: "Alien", Year: 1979, Duration: 7020000, AddedAt: 1600000000, Collections: []string{"Alien"}, File: "/data/movies/Alien (1979)/Alien.mkv"},
+			{RatingKey: "101", LibraryKey: "1", Type: "movie", Title: "Aliens", Year: 1986, Duration: 8220000, ViewOffset: 1200000, AddedAt: 1600000100, Collections: []string{"Alien"}, File: "/data/movies/Aliens (1986)/Aliens.mkv"},
+			{RatingKey: "102", LibraryKey: "1", Type: "movie", GUID: "plex://movie/blade-runner-2049", AltGUIDs: []string{"imdb://tt1856101", "tmdb://335984"}, Title: "Blade Runner", Year: 1982, Duration: 7020000, ViewCount: 2, AddedAt: 1600000200, File: "/data/movies/Blade Runner (1982)/Blade Runner.mkv"},
+			{RatingKey: "200", LibraryKey: "2", Type: "show", Title: "The Expanse", Year: 2015, AddedAt: 1600000300},
+			{RatingKey: "201", LibraryKey: "2", ParentRatingKey: "200", Type: "season", Title: "Season 1", Index: 1, AddedAt: 1600000300},
+			{RatingKey: "202", LibraryKey: "2", ParentRatingKey: "201", Type: "episode", Title: "Dulcinea", Index: 1, Year: 2015, Duration: 2640000, AddedAt: 1600000300, File: "/data/tv/The Expanse/Season 01/S01E01.mkv"},
+			{RatingKey: "203", LibraryKey: "2", ParentRatingKey: "201", Type: "episode", Title: "The Big Empty", Index: 2, Year: 2015, Duration: 2580000, AddedAt: 1600000300, File: "/data/tv/The Expanse/Season 01/S01E02.mkv"},
+		},
+		Sessions: []Session{
+			{ID: "session-1", SessionKey: "1", RatingKey: "202", UserID: 1, Username: "plextest", Player: "Living Room", Product: "Plex for Android", State: "playing", ViewOffset: 600000},
+		},
+		Friends: []Friend{
+			{ID: 2, Username: "friend", Email: "friend@example.com", Title: "friend"},
+		},
+		Playlists: []Playlist{
+			{RatingKey: "300", Title: "Sci-Fi Night", Type: "video", Items: []PlaylistItem{{ID: 1, RatingKey: "100"}, {ID: 2, RatingKey: "102"}}},
+		},
+		Collections: []Collection{
+			{RatingKey: "400", LibraryKey: "1", Title: "Alien", Type: "movie", Mode: -1},
+		},
+		Matches: []Match{
+			{GUID: "plex://movie/alien", Type: "movie", Title: "Alien", Year: 1979, AltGUIDs: []string{"imdb://tt0078748", "tmdb://348"}},
+			{GUID: "plex://movie/aliens", Type: "movie", Title: "Aliens", Year: 1986, AltGUIDs: []string{"imdb://tt0090605", "tmdb://679"}},
+			{GUID: "plex://movie/blade-runner", Type: "movie", Title: "Blade Runner", Year: 1982, AltGUIDs: []string{"imdb://tt0083658", "tmdb://78"}},
+			{GUID: "plex://movie/blade-runner-2049", Type: "movie", Title: "Blade Runner 2049", Year: 2017, AltGUIDs: []string{"imdb://tt1856101", "tmdb://335984"}},
+		},
+		Preferences: []Preference{
+			{ID: "allowMediaDeletion", Label: "Allow media deletion", Type: "bool", Group: "library", Value: "true", Default: "false"},
+			{ID: "TranscoderQuality", Label: "Transcoder quality", Type: "int", Group: "transcoder", Value: "0", Default: "0", EnumValues: "0:Automatic|1:Prefer higher speed encoding|2:Prefer higher quality encoding|3:Make my CPU hurt"},
+			{ID: "HardwareAcceleratedCodecs", Label: "Use hardware acceleration when available", Type: "bool", Group: "transcoder", Value: "true", Default: "true", Advanced: true},
+			{ID: "TranscoderTempDirectory", Label: "Transcoder temporary directory", Type: "text", Group: "transcoder", Advanced: true},
+			{ID: "ManualPortMappingMode", Label: "Manually specify public port", Type: "bool", Group: "network", Value: "false", Default: "false"},
+			{ID: "ManualPortMappingPort", Label: "Public port", Type: "int", Group: "network", Value: "32400", Default: "32400"},
+			{ID: "secureConnections", Label: "Secure connections", Type: "int", Group: "network", Value: "1", Default: "1", EnumValues: "0:Required|1:Preferred|2:Disabled"},
+		},
+		ButlerTasks: []ButlerTask{
+			{Name: "BackupDatabase", Title: "Backup Database", Description: "Create a backup copy of the server's database", Enabled: true, Interval: 3},
+			{Name: "CleanOldBundles", Title: "Clean Old Bundles", Description: "Remove old bundles", Enabled: true, Interval: 7},
+			{Name: "OptimizeDatabase", Title: "Optimize Database", Description: "Optimize the server's database", Enabled: true, Interval: 7},
+			{Name: "DeepMediaAnalysis", Title: "Perform extensive media analysis", Description: "Analyze media files for bitrate information", Enabled: false, Interval: 1},
+		},
+		Activities: []Activity{
+			{UUID: "plextest-activity-analysis", Type: "media.generate.bif", Title: "Generating video preview thumbnails", Subtitle: "Alien", Progress: 40, Cancellable: true, SectionKey: "1"},
+			{UUID: "plextest-activity-optimize", Type: "media.generate.optimized", Title: "Optimizing", Subtitle: "Blade Runner", Progress: 10, Cancellable: true},
+			{UUID: "plextest-activity-backup", Type: "butler.BackupDatabase", Title: "Backing up database", Progress: 80},
+		},
+		History: []Play{
+			{ID: 1, RatingKey: "102", AccountID: 1, DeviceID: 1, ViewedAt: 1600100000},
+			{ID: 2, RatingKey: "202", AccountID: 2, DeviceID: 2, ViewedAt: 1600200000},
+			{ID: 3, RatingKey: "102", AccountID: 1, DeviceID: 1, ViewedAt: 1600300000},
+		},
+	}
+}
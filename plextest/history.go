@@ -0,0 +1,119 @@
+package plextest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// matchesPlay reports whether play passes the history filters of the request
+func (s *Server) matchesPlay(play Play, r *http.Request) bool {
+	query := r.URL.Query()
+	ii, ok := s.findItem(play.RatingKey)
+
+	if !ok {
+		return false
+	}
+
+	if v := query.Get("accountID"); v != "" && v != strconv.Itoa(play.AccountID) {
+		return false
+	}
+
+	if v := query.Get("librarySectionID"); v != "" && v != s.fixtures.Items[ii].LibraryKey {
+		return false
+	}
+
+	if v := query.Get("metadataItemID"); v != "" && v != play.RatingKey {
+		return false
+	}
+
+	// the comparison is part of the key: viewedAt>=1600000000 parses as "viewedAt>" = "1600000000"
+	if v, err := strconv.ParseInt(query.Get("viewedAt>"), 10, 64); err == nil && play.ViewedAt < v {
+		return false
+	}
+
+	if v, err := strconv.ParseInt(query.Get("viewedAt<"), 10, 64); err == nil && play.ViewedAt > v {
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) playElement(play Play) *element {
+	ii, _ := s.findItem(play.RatingKey)
+	item := s.fixtures.Items[ii]
+
+	e := newElement(itemElementName(item.Type),
+		"historyKey", "/status/sessions/history/"+strconv.Itoa(play.ID),
+		"key", "/library/metadata/"+item.RatingKey,
+		"ratingKey", item.RatingKey,
+		"librarySectionID", item.LibraryKey,
+		"title", item.Title,
+		"type", item.Type,
+		"viewedAt", play.ViewedAt,
+		"accountID", play.AccountID,
+		"deviceID", play.DeviceID,
+	)
+
+	if item.Index != 0 {
+		e.set("index", item.Index)
+	}
+
+	// episodes and tracks carry the titles of their season and show, or album and artist
+	if jj, ok := s.findItem(item.ParentRatingKey); ok {
+		parent := s.fixtures.Items[jj]
+
+		e.set("parentKey", "/library/metadata/"+parent.RatingKey, "parentTitle", parent.Title)
+
+		if parent.Index != 0 {
+			e.set("parentIndex", parent.Index)
+		}
+
+		if kk, ok := s.findItem(parent.ParentRatingKey); ok {
+			grandparent := s.fixtures.Items[kk]
+
+			e.set("grandparentKey", "/library/metadata/"+grandparent.RatingKey, "grandparentTitle", grandparent.Title)
+		}
+	}
+
+	return e
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var plays []Play
+
+	for _, play := range s.fixtures.History {
+		if s.matchesPlay(play, r) {
+			plays = append(plays, play)
+		}
+	}
+
+	switch r.URL.Query().Get("sort") {
+	case "viewedAt":
+		sort.SliceStable(plays, func(i, j int) bool { return plays[i].ViewedAt < plays[j].ViewedAt })
+	case "viewedAt:desc":
+		sort.SliceStable(plays, func(i, j int) bool { return plays[i].ViewedAt > plays[j].ViewedAt })
+	}
+
+	start, size := containerRange(r)
+	total := len(plays)
+
+	if start > total {
+		start = total
+	}
+
+	end := total
+
+	if size >= 0 && start+size < total {
+		end = start + size
+	}
+
+	page := plays[start:end]
+	root := s.container("size", len(page), "totalSize", total, "offset", start)
+
+	for _, play := range page {
+		root.add("Metadata", s.playElement(play))
+	}
+
+	writeContainer(w, r, http.StatusOK, root)
+}
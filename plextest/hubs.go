@@ -0,0 +1,200 @@
+package plextest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// hubTitles are the titles of the search hubs of each media type, in the order they are returned
+var hubTitles = []struct {
+	mediaType, title string
+}{
+	{"movie", "Movies"},
+	{"show", "Shows"},
+	{"season", "Seasons"},
+	{"episode", "Episodes"},
+	{"artist", "Artists"},
+	{"album", "Albums"},
+	{"track", "Tracks"},
+}
+
+// tagHubs are the tag fields searched by hub search with their plex tag types
+var tagHubs = []struct {
+	hubType, title string
+	tagType        int
+	tags           func(Item) []string
+}{
+	{"director", "Directors", 4, func(item Item) []string { return item.Directors }},
+	{"genre", "Genres", 1, func(item Item) []string { return item.Genres }},
+}
+
+func newHub(identifier, hubType, title string, size int, more bool) *element {
+	return newElement("Hub",
+		"hubIdentifier", identifier,
+		"type", hubType,
+		"title", title,
+		"size", size,
+		"more", more,
+	)
+}
+
+// itemHub returns a hub of items, keeping limit of them when limit is positive
+func (s *Server) itemHub(identifier, hubType, title string, items []Item, limit int) *element {
+	more := false
+
+	if limit > 0 && len(items) > limit {
+		items, more = items[:limit], true
+	}
+
+	var ratingKeys []string
+
+	for _, item := range items {
+		ratingKeys = append(ratingKeys, item.RatingKey)
+	}
+
+	hub := newHub(identifier, hubType, title, len(items), more).set(
+		"hubKey", "/library/metadata/"+strings.Join(ratingKeys, ","),
+	)
+
+	for _, item := range items {
+		hub.add("Metadata", s.itemElement(item))
+	}
+
+	return hub
+}
+
+func (s *Server) handleHubSearch(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	sectionKey := r.URL.Query().Get("sectionId")
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	if query == "" {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	var items []Item
+
+	for _, item := range s.fixtures.Items {
+		if sectionKey == "" || item.LibraryKey == sectionKey {
+			items = append(items, item)
+		}
+	}
+
+	root := s.container()
+
+	for _, hubTitle := range hubTitles {
+		var matches []Item
+
+		for _, item := range items {
+			if item.Type == hubTitle.mediaType && strings.Contains(strings.ToLower(item.Title), query) {
+				matches = append(matches, item)
+			}
+		}
+
+		if len(matches) > 0 {
+			root.add("Hub", s.itemHub(hubTitle.mediaType, hubTitle.mediaType, hubTitle.title, matches, limit))
+		}
+	}
+
+	for _, tagHub := range tagHubs {
+		counts := map[string]int{}
+
+		for _, item := range items {
+			for _, tag := range tagHub.tags(item) {
+				if strings.Contains(strings.ToLower(tag), query) {
+					counts[tag]++
+				}
+			}
+		}
+
+		if len(counts) == 0 {
+			continue
+		}
+
+		var tags []string
+
+		for tag := range counts {
+			tags = append(tags, tag)
+		}
+
+		sort.Strings(tags)
+
+		more := false
+
+		if limit > 0 && len(tags) > limit {
+			tags, more = tags[:limit], true
+		}
+
+		hub := newHub(tagHub.hubType, tagHub.hubType, tagHub.title, len(tags), more)
+
+		for _, tag := range tags {
+			hub.add("Directory", newElement("Directory",
+				"tag", tag,
+				"tagType", tagHub.tagType,
+				"count", counts[tag],
+			))
+		}
+
+		root.add("Hub", hub)
+	}
+
+	writeContainer(w, r, http.StatusOK, root.set("size", len(root.children)))
+}
+
+// onDeckItems returns the items with progress that were not removed from Continue Watching
+func (s *Server) onDeckItems(sectionKey string) []Item {
+	var items []Item
+
+	for _, item := range s.fixtures.Items {
+		if item.ViewOffset > 0 && !item.HiddenFromContinueWatching && (sectionKey == "" || item.LibraryKey == sectionKey) {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// recentlyAdded returns the top level items of library, newest first
+func (s *Server) recentlyAdded(library Library) []Item {
+	items := s.libraryItems(library, nil)
+
+	sortItems(items, "addedAt:desc")
+
+	return items
+}
+
+func (s *Server) handleHubs(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	root := s.container()
+
+	root.add("Hub", s.itemHub("home.continue", "mixed", "Continue Watching", s.onDeckItems(""), 0))
+
+	for _, library := range s.fixtures.Libraries {
+		identifier := "home." + library.Type + ".recent." + library.Key
+
+		root.add("Hub", s.itemHub(identifier, library.Type, "Recently Added in "+library.Title, s.recentlyAdded(library), 0))
+	}
+
+	writeContainer(w, r, http.StatusOK, root.set("size", len(root.children)))
+}
+
+func (s *Server) handleSectionHubs(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findLibrary(params["section"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	library := s.fixtures.Libraries[ii]
+	root := s.container("librarySectionID", library.Key, "librarySectionTitle", library.Title)
+
+	root.add("Hub",
+		s.itemHub(library.Type+".inprogress."+library.Key, library.Type, "Continue Watching", s.onDeckItems(library.Key), 0),
+		s.itemHub(library.Type+".recentlyadded."+library.Key, library.Type, "Recently Added", s.recentlyAdded(library), 0),
+	)
+
+	writeContainer(w, r, http.StatusOK, root.set("size", len(root.children)))
+}
@@ -0,0 +1,188 @@
+package plextest
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// creditsDetection lets a video library opt out of credits detection, which is on by default
+var creditsDetection = Preference{
+	ID:         "enableCreditsMarkerGeneration",
+	Label:      "Enable credits detection",
+	Type:       "int",
+	Default:    "-1",
+	EnumValues: "-1:Use server default|0:Disabled",
+}
+
+var includeInGlobal = Preference{
+	ID:      "includeInGlobal",
+	Label:   "Include in dashboard",
+	Type:    "bool",
+	Default: "true",
+}
+
+// libraryPreferences are the advanced settings of the libraries of each type. Their value is the default
+var libraryPreferences = map[string][]Preference{
+	"movie": {
+		{ID: "enableCinemaTrailers", Label: "Enable video previews", Type: "bool", Default: "true"},
+		creditsDetection,
+		includeInGlobal,
+	},
+	"show": {
+		{ID: "showOrdering", Label: "Episode ordering", Type: "text", Default: "aired", EnumValues: "aired:TheTVDB (Aired)|dvd:TheTVDB (DVD)|absolute:TheTVDB (Absolute)|tmdbAiring:The Movie Database (Aired)"},
+		{ID: "episodeSort", Label: "Episode sorting", Type: "int", Default: "-1", EnumValues: "-1:Library default|0:Oldest first|1:Newest first"},
+		creditsDetection,
+		includeInGlobal,
+	},
+	"artist": {
+		includeInGlobal,
+	},
+}
+
+// libraryPrefs returns the advanced settings of library with their current value
+func libraryPrefs(library Library) []Preference {
+	var prefs []Preference
+
+	for _, pref := range libraryPreferences[library.Type] {
+		pref.Value = pref.Default
+
+		if value, ok := library.Prefs[pref.ID]; ok {
+			pref.Value = value
+		}
+
+		prefs = append(prefs, pref)
+	}
+
+	return prefs
+}
+
+// libraryPrefsQuery returns the settings sent as prefs[id]=value when creating or updating a library
+func libraryPrefsQuery(query url.Values) map[string]string {
+	values := map[string]string{}
+
+	for key := range query {
+		if strings.HasPrefix(key, "prefs[") && strings.HasSuffix(key, "]") {
+			values[key[len("prefs["):len(key)-1]] = query.Get(key)
+		}
+	}
+
+	return values
+}
+
+// setLibraryPrefs applies values to library, or none of them when one is unknown or invalid
+func setLibraryPrefs(library *Library, values map[string]string) bool {
+	prefs := map[string]Preference{}
+
+	for _, pref := range libraryPreferences[library.Type] {
+		prefs[pref.ID] = pref
+	}
+
+	valid := map[string]string{}
+
+	for id, value := range values {
+		pref, ok := prefs[id]
+
+		if !ok {
+			return false
+		}
+
+		if valid[id], ok = validValue(pref, value); !ok {
+			return false
+		}
+	}
+
+	if len(valid) == 0 {
+		return true
+	}
+
+	if library.Prefs == nil {
+		library.Prefs = map[string]string{}
+	}
+
+	for id, value := range valid {
+		library.Prefs[id] = value
+	}
+
+	return true
+}
+
+func (s *Server) handleUpdateLibrary(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findLibrary(params["section"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	query := r.URL.Query()
+	library := s.fixtures.Libraries[ii]
+
+	if name := query.Get("name"); name != "" {
+		library.Title = name
+	}
+
+	if agent := query.Get("agent"); agent != "" {
+		library.Agent = agent
+	}
+
+	if language := query.Get("language"); language != "" {
+		library.Language = language
+	}
+
+	// like plex, the locations of the request replace the ones of the library
+	if locations := query["location"]; len(locations) > 0 {
+		library.Locations = locations
+	}
+
+	if !setLibraryPrefs(&library, libraryPrefsQuery(query)) {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	s.fixtures.Libraries[ii] = library
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleLibraryPrefs(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findLibrary(params["section"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	prefs := libraryPrefs(s.fixtures.Libraries[ii])
+	root := s.container("size", len(prefs))
+
+	for _, pref := range prefs {
+		root.add("Setting", preferenceElement(pref))
+	}
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+func (s *Server) handleSetLibraryPrefs(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findLibrary(params["section"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	values := map[string]string{}
+
+	for key := range r.URL.Query() {
+		if !strings.HasPrefix(key, "X-Plex-") {
+			values[key] = r.URL.Query().Get(key)
+		}
+	}
+
+	if !setLibraryPrefs(&s.fixtures.Libraries[ii], values) {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
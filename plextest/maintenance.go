@@ -0,0 +1,88 @@
+package plextest
+
+import (
+	"net/http"
+)
+
+func (s *Server) findButlerTask(name string) (int, bool) {
+	for ii, task := range s.fixtures.ButlerTasks {
+		if task.Name == name {
+			return ii, true
+		}
+	}
+
+	return -1, false
+}
+
+func (s *Server) handleEmptyTrash(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findLibrary(params["section"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	library := s.fixtures.Libraries[ii]
+
+	s.runActivity(Activity{
+		Type:       "library.empty.trash",
+		Title:      "Emptying trash of " + library.Title,
+		SectionKey: library.Key,
+	})
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleCleanBundles(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.runActivity(Activity{Type: "library.clean.bundles", Title: "Cleaning bundles"})
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleOptimize(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.runActivity(Activity{Type: "database.optimize", Title: "Optimizing database"})
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleButlerTasks answers with a ButlerTasks root instead of a MediaContainer, like plex
+func (s *Server) handleButlerTasks(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	root := newElement("ButlerTasks")
+
+	for _, task := range s.fixtures.ButlerTasks {
+		root.add("ButlerTask", newElement("ButlerTask",
+			"name", task.Name,
+			"title", task.Title,
+			"description", task.Description,
+			"enabled", task.Enabled,
+			"interval", task.Interval,
+			"scheduleRandomized", false,
+		))
+	}
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+func (s *Server) handleStartButlerTask(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findButlerTask(params["task"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	task := s.fixtures.ButlerTasks[ii]
+
+	s.runActivity(Activity{Type: "butler." + task.Name, Title: task.Title, Cancellable: true})
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleStopButlerTask(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if _, ok := s.findButlerTask(params["task"]); !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,145 @@
+package plextest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func (s *Server) findMatch(guid string) (int, bool) {
+	for ii, match := range s.fixtures.Matches {
+		if match.GUID == guid {
+			return ii, true
+		}
+	}
+
+	return -1, false
+}
+
+// matchScore rates how well match fits a title and a year the way the agent does, 0 being no match
+func matchScore(match Match, title string, year int) int {
+	if !strings.Contains(strings.ToLower(match.Title), strings.ToLower(title)) {
+		return 0
+	}
+
+	score := 50
+
+	if strings.EqualFold(match.Title, title) {
+		score += 35
+	}
+
+	if year == 0 || match.Year == year {
+		score += 15
+	}
+
+	return score
+}
+
+func (s *Server) handleMatches(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findItem(params["ratingKey"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	item := s.fixtures.Items[ii]
+	query := r.URL.Query()
+	title, year := item.Title, item.Year
+
+	// manual searches use the title and year of the request instead of the ones of the item
+	if query.Get("manual") == "1" {
+		title = query.Get("title")
+		year, _ = strconv.Atoi(query.Get("year"))
+	}
+
+	type result struct {
+		match Match
+		score int
+	}
+
+	var results []result
+
+	for _, match := range s.fixtures.Matches {
+		if match.Type != item.Type {
+			continue
+		}
+
+		if score := matchScore(match, title, year); score > 0 {
+			results = append(results, result{match, score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+
+	root := s.container("size", len(results))
+
+	for _, result := range results {
+		root.add("SearchResult", newElement("SearchResult",
+			"guid", result.match.GUID,
+			"name", result.match.Title,
+			"year", result.match.Year,
+			"summary", result.match.Summary,
+			"type", result.match.Type,
+			"score", result.score,
+			"matched", result.match.GUID == item.GUID,
+		))
+	}
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+func (s *Server) handleMatch(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findItem(params["ratingKey"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	jj, ok := s.findMatch(r.URL.Query().Get("guid"))
+
+	if !ok || s.fixtures.Matches[jj].Type != s.fixtures.Items[ii].Type {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	match := s.fixtures.Matches[jj]
+	item := &s.fixtures.Items[ii]
+
+	item.GUID = match.GUID
+	item.AltGUIDs = append([]string(nil), match.AltGUIDs...)
+	item.Title = match.Title
+	item.Year = match.Year
+
+	if name := r.URL.Query().Get("name"); name != "" {
+		item.Title = name
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleUnmatch(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findItem(params["ratingKey"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	s.fixtures.Items[ii].GUID = "local://" + s.fixtures.Items[ii].RatingKey
+	s.fixtures.Items[ii].AltGUIDs = nil
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleRefreshMetadata accepts the refresh, there is no agent data to download again
+func (s *Server) handleRefreshMetadata(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if _, ok := s.findItem(params["ratingKey"]); !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
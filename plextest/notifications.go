@@ -0,0 +1,132 @@
+package plextest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+const notificationsPath = "/:/websockets/notifications"
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+func (s *Server) serveNotifications(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	authorized := s.authorized(r)
+	s.mu.Unlock()
+
+	if !authorized {
+		writeError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.subscribers[conn] = true
+	close(s.subscribersCh)
+	s.subscribersCh = make(chan struct{})
+	s.mu.Unlock()
+
+	// read until the client goes away so close frames are handled
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	delete(s.subscribers, conn)
+	s.mu.Unlock()
+
+	conn.Close()
+}
+
+// WaitForSubscribers blocks until at least n clients are subscribed to notifications or ctx is done
+func (s *Server) WaitForSubscribers(ctx context.Context, n int) error {
+	for {
+		s.mu.Lock()
+		count := len(s.subscribers)
+		changed := s.subscribersCh
+		s.mu.Unlock()
+
+		if count >= n {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Notify sends a notification to every subscribed client. container is encoded as the
+// NotificationContainer of the message and should set "type", i.e.
+//
+//	server.Notify(map[string]interface{}{
+//		"type": "playing",
+//		"size": 1,
+//		"PlaySessionStateNotification": []map[string]interface{}{
+//			{"sessionKey": "1", "ratingKey": "202", "state": "paused", "viewOffset": 1000},
+//		},
+//	})
+func (s *Server) Notify(container interface{}) error {
+	message, err := json.Marshal(map[string]interface{}{"NotificationContainer": container})
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn := range s.subscribers {
+		if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NotifyPlaying sends a "playing" notification for the session with sessionKey.
+// state is one of playing, paused, buffering or stopped
+func (s *Server) NotifyPlaying(sessionKey, state string, viewOffset int64) error {
+	s.mu.Lock()
+
+	notification := map[string]interface{}{
+		"sessionKey": sessionKey,
+		"state":      state,
+		"viewOffset": viewOffset,
+	}
+
+	for ii, session := range s.fixtures.Sessions {
+		if session.SessionKey != sessionKey {
+			continue
+		}
+
+		s.fixtures.Sessions[ii].State = state
+		s.fixtures.Sessions[ii].ViewOffset = viewOffset
+
+		notification["ratingKey"] = session.RatingKey
+		notification["key"] = "/library/metadata/" + session.RatingKey
+	}
+
+	s.mu.Unlock()
+
+	return s.Notify(map[string]interface{}{
+		"type":                         "playing",
+		"size":                         1,
+		"PlaySessionStateNotification": []interface{}{notification},
+	})
+}
@@ -0,0 +1,378 @@
+package plextest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+func (s *Server) findPlaylist(ratingKey string) (int, bool) {
+	for ii, playlist := range s.fixtures.Playlists {
+		if playlist.RatingKey == ratingKey {
+			return ii, true
+		}
+	}
+
+	return -1, false
+}
+
+// playlistEntries returns the items of a playlist with their playlist item ids.
+// The entries of a smart playlist are numbered in the order they are found
+func (s *Server) playlistEntries(playlist Playlist) ([]PlaylistItem, []Item) {
+	var entries []PlaylistItem
+	var items []Item
+
+	if playlist.SectionKey != "" {
+		ii, ok := s.findLibrary(playlist.SectionKey)
+
+		if !ok {
+			return nil, nil
+		}
+
+		query, _ := url.ParseQuery(playlist.Filter)
+
+		for jj, item := range s.libraryItems(s.fixtures.Libraries[ii], query) {
+			entries = append(entries, PlaylistItem{ID: jj + 1, RatingKey: item.RatingKey})
+			items = append(items, item)
+		}
+
+		return entries, items
+	}
+
+	for _, entry := range playlist.Items {
+		if ii, ok := s.findItem(entry.RatingKey); ok {
+			entries = append(entries, entry)
+			items = append(items, s.fixtures.Items[ii])
+		}
+	}
+
+	return entries, items
+}
+
+func (s *Server) playlistElement(playlist Playlist) *element {
+	_, items := s.playlistEntries(playlist)
+
+	var duration int64
+
+	for _, item := range items {
+		duration += item.Duration
+	}
+
+	return newElement("Playlist",
+		"ratingKey", playlist.RatingKey,
+		"key", "/playlists/"+playlist.RatingKey+"/items",
+		"guid", "com.plexapp.agents.none://plextest-playlist-"+playlist.RatingKey,
+		"type", "playlist",
+		"title", playlist.Title,
+		"summary", playlist.Summary,
+		"smart", playlist.SectionKey != "",
+		"playlistType", playlist.Type,
+		"composite", "/playlists/"+playlist.RatingKey+"/composite/1600000000",
+		"duration", duration,
+		"leafCount", len(items),
+	)
+}
+
+// libraryURIPath returns the path of a server://{machineID}/com.plexapp.plugins.library/... uri
+func (s *Server) libraryURIPath(uri string) (string, bool) {
+	prefix := "server://" + s.fixtures.MachineIdentifier + "/com.plexapp.plugins.library"
+
+	if !strings.HasPrefix(uri, prefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(uri, prefix), true
+}
+
+// uriRatingKeys returns the rating keys of a uri pointing at /library/metadata/{ratingKeys}
+func (s *Server) uriRatingKeys(uri string) ([]string, bool) {
+	path, ok := s.libraryURIPath(uri)
+
+	if !ok || !strings.HasPrefix(path, "/library/metadata/") {
+		return nil, false
+	}
+
+	ratingKeys := strings.Split(strings.TrimPrefix(path, "/library/metadata/"), ",")
+
+	for _, ratingKey := range ratingKeys {
+		if _, ok := s.findItem(ratingKey); !ok {
+			return nil, false
+		}
+	}
+
+	return ratingKeys, true
+}
+
+// uriSection returns the library and filter of a uri pointing at /library/sections/{key}/all?{filter}
+func (s *Server) uriSection(uri string) (sectionKey, filter string, ok bool) {
+	path, ok := s.libraryURIPath(uri)
+
+	if !ok {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(path, "?", 2)
+	segments := strings.Split(parts[0], "/")
+
+	if len(segments) != 5 || segments[1] != "library" || segments[2] != "sections" || segments[4] != "all" {
+		return "", "", false
+	}
+
+	if _, ok := s.findLibrary(segments[3]); !ok {
+		return "", "", false
+	}
+
+	if len(parts) == 2 {
+		filter = parts[1]
+	}
+
+	return segments[3], filter, true
+}
+
+// uriItems returns new playlist entries for a uri pointing at /library/metadata/{ratingKeys}
+func (s *Server) uriItems(uri string) ([]PlaylistItem, bool) {
+	ratingKeys, ok := s.uriRatingKeys(uri)
+
+	if !ok {
+		return nil, false
+	}
+
+	var entries []PlaylistItem
+
+	for _, ratingKey := range ratingKeys {
+		entries = append(entries, PlaylistItem{ID: s.newID(), RatingKey: ratingKey})
+	}
+
+	return entries, true
+}
+
+func (s *Server) handlePlaylists(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	root := s.container()
+	playlistType := r.URL.Query().Get("playlistType")
+
+	for _, playlist := range s.fixtures.Playlists {
+		if playlistType == "" || playlist.Type == playlistType {
+			root.add("Metadata", s.playlistElement(playlist))
+		}
+	}
+
+	writeContainer(w, r, http.StatusOK, root.set("size", len(root.children)))
+}
+
+func (s *Server) handleCreatePlaylist(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	query := r.URL.Query()
+
+	playlist := Playlist{
+		RatingKey: strconv.Itoa(s.newID()),
+		Title:     query.Get("title"),
+		Type:      query.Get("type"),
+	}
+
+	if playlist.Title == "" || playlist.Type == "" {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	if query.Get("smart") == "1" {
+		sectionKey, filter, ok := s.uriSection(query.Get("uri"))
+
+		if !ok {
+			writeError(w, r, http.StatusBadRequest, "Bad Request")
+			return
+		}
+
+		playlist.SectionKey, playlist.Filter = sectionKey, filter
+	} else {
+		entries, ok := s.uriItems(query.Get("uri"))
+
+		if !ok {
+			writeError(w, r, http.StatusBadRequest, "Bad Request")
+			return
+		}
+
+		playlist.Items = entries
+	}
+
+	s.fixtures.Playlists = append(s.fixtures.Playlists, playlist)
+
+	root := s.container("size", 1)
+
+	writeContainer(w, r, http.StatusOK, root.add("Metadata", s.playlistElement(playlist)))
+}
+
+func (s *Server) handlePlaylist(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findPlaylist(params["playlist"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	root := s.container("size", 1)
+
+	writeContainer(w, r, http.StatusOK, root.add("Metadata", s.playlistElement(s.fixtures.Playlists[ii])))
+}
+
+func (s *Server) handleEditPlaylist(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findPlaylist(params["playlist"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	query := r.URL.Query()
+
+	if title := query.Get("title"); title != "" {
+		s.fixtures.Playlists[ii].Title = title
+	}
+
+	if _, ok := query["summary"]; ok {
+		s.fixtures.Playlists[ii].Summary = query.Get("summary")
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDeletePlaylist(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findPlaylist(params["playlist"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	s.fixtures.Playlists = append(s.fixtures.Playlists[:ii], s.fixtures.Playlists[ii+1:]...)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handlePlaylistItems(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findPlaylist(params["playlist"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	playlist := s.fixtures.Playlists[ii]
+	entries, items := s.playlistEntries(playlist)
+
+	root := s.container(
+		"ratingKey", playlist.RatingKey,
+		"title", playlist.Title,
+		"smart", playlist.SectionKey != "",
+		"playlistType", playlist.Type,
+		"leafCount", len(items),
+		"size", len(items),
+	)
+
+	for jj, item := range items {
+		root.add("Metadata", s.itemElement(item).set("playlistItemID", entries[jj].ID))
+	}
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+// editablePlaylist returns the regular playlist of the request, smart playlists cannot be edited by item
+func (s *Server) editablePlaylist(w http.ResponseWriter, r *http.Request, ratingKey string) (*Playlist, bool) {
+	ii, ok := s.findPlaylist(ratingKey)
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return nil, false
+	}
+
+	if s.fixtures.Playlists[ii].SectionKey != "" {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return nil, false
+	}
+
+	return &s.fixtures.Playlists[ii], true
+}
+
+func (s *Server) handleAddPlaylistItems(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	playlist, ok := s.editablePlaylist(w, r, params["playlist"])
+
+	if !ok {
+		return
+	}
+
+	entries, ok := s.uriItems(r.URL.Query().Get("uri"))
+
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	playlist.Items = append(playlist.Items, entries...)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// removePlaylistEntry removes the entry with id and returns it
+func removePlaylistEntry(playlist *Playlist, id string) (PlaylistItem, bool) {
+	for ii, entry := range playlist.Items {
+		if strconv.Itoa(entry.ID) == id {
+			playlist.Items = append(playlist.Items[:ii], playlist.Items[ii+1:]...)
+			return entry, true
+		}
+	}
+
+	return PlaylistItem{}, false
+}
+
+func (s *Server) handleRemovePlaylistItem(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	playlist, ok := s.editablePlaylist(w, r, params["playlist"])
+
+	if !ok {
+		return
+	}
+
+	if _, ok := removePlaylistEntry(playlist, params["item"]); !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleMovePlaylistItem(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	playlist, ok := s.editablePlaylist(w, r, params["playlist"])
+
+	if !ok {
+		return
+	}
+
+	original := append([]PlaylistItem(nil), playlist.Items...)
+
+	entry, ok := removePlaylistEntry(playlist, params["item"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	position := 0
+
+	if after := r.URL.Query().Get("after"); after != "" {
+		position = -1
+
+		for ii, e := range playlist.Items {
+			if strconv.Itoa(e.ID) == after {
+				position = ii + 1
+			}
+		}
+
+		if position < 0 {
+			playlist.Items = original
+			writeError(w, r, http.StatusNotFound, "Not Found")
+			return
+		}
+	}
+
+	playlist.Items = append(playlist.Items[:position], append([]PlaylistItem{entry}, playlist.Items[position:]...)...)
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,135 @@
+package plextest
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// leaves returns the indexes of the playable items under ratingKey, like the episodes of a show or a season
+func (s *Server) leaves(ratingKey string) []int {
+	var leaves []int
+
+	for ii, item := range s.fixtures.Items {
+		if item.ParentRatingKey != ratingKey {
+			continue
+		}
+
+		if children := s.leaves(item.RatingKey); len(children) > 0 {
+			leaves = append(leaves, children...)
+		} else if playable(item) {
+			leaves = append(leaves, ii)
+		}
+	}
+
+	return leaves
+}
+
+func playable(item Item) bool {
+	return item.Type != "show" && item.Type != "season" && item.Type != "artist" && item.Type != "album"
+}
+
+// playStateItems returns the playable items the param query parameter refers to,
+// which is the item itself or the items under it
+func (s *Server) playStateItems(w http.ResponseWriter, r *http.Request, param string) ([]int, bool) {
+	ii, ok := s.findItem(r.URL.Query().Get(param))
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return nil, false
+	}
+
+	if playable(s.fixtures.Items[ii]) {
+		return []int{ii}, true
+	}
+
+	return s.leaves(s.fixtures.Items[ii].RatingKey), true
+}
+
+func (s *Server) handleScrobble(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	leaves, ok := s.playStateItems(w, r, "key")
+
+	if !ok {
+		return
+	}
+
+	for _, ii := range leaves {
+		s.fixtures.Items[ii].ViewCount++
+		s.fixtures.Items[ii].ViewOffset = 0
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleUnscrobble(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	leaves, ok := s.playStateItems(w, r, "key")
+
+	if !ok {
+		return
+	}
+
+	for _, ii := range leaves {
+		s.fixtures.Items[ii].ViewCount = 0
+		s.fixtures.Items[ii].ViewOffset = 0
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleProgress(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	ii, ok := s.findItem(r.URL.Query().Get("key"))
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.URL.Query().Get("time"), 10, 64)
+
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	s.fixtures.Items[ii].ViewOffset = offset
+	s.fixtures.Items[ii].HiddenFromContinueWatching = false
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleRate(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	ii, ok := s.findItem(r.URL.Query().Get("key"))
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	rating, err := strconv.ParseFloat(r.URL.Query().Get("rating"), 64)
+
+	if err != nil || rating > 10 {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	// -1 removes the rating
+	if rating < 0 {
+		rating = 0
+	}
+
+	s.fixtures.Items[ii].UserRating = rating
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleRemoveFromContinueWatching(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	ii, ok := s.findItem(r.URL.Query().Get("ratingKey"))
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	s.fixtures.Items[ii].HiddenFromContinueWatching = true
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,367 @@
+package plextest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pin is a plex.tv pin used to link an app to an account
+type pin struct {
+	id               int
+	code             string
+	clientIdentifier string
+	createdAt        time.Time
+	linked           bool
+}
+
+func (s *Server) plexTVRoutes() []route {
+	return []route{
+		{method: http.MethodPost, pattern: splitPath("/api/v2/users/signin"), public: true, handler: s.handleSignIn},
+		{method: http.MethodPost, pattern: splitPath("/api/v2/pins.json"), public: true, handler: s.handleRequestPIN},
+		{method: http.MethodGet, pattern: splitPath("/api/v2/pins/{pin}"), public: true, handler: s.handleCheckPIN},
+		{method: http.MethodPut, pattern: splitPath("/api/v2/pins/link.json"), handler: s.handleLinkPIN},
+		{method: http.MethodGet, pattern: splitPath("/api/v2/user/webhooks"), handler: s.handleGetWebhooks},
+		{method: http.MethodPost, pattern: splitPath("/api/v2/user/webhooks"), handler: s.handleSetWebhooks},
+		{method: http.MethodGet, pattern: splitPath("/users/account"), handler: s.handleAccount},
+		{method: http.MethodGet, pattern: splitPath("/api/users"), handler: s.handleFriends},
+		{method: http.MethodDelete, pattern: splitPath("/api/friends/{id}"), handler: s.handleRemoveFriend},
+		{method: http.MethodPost, pattern: splitPath("/api/users/validate"), handler: s.handleValidateUser},
+		{method: http.MethodGet, pattern: splitPath("/api/servers"), handler: s.handleServers},
+		{method: http.MethodGet, pattern: splitPath("/api/servers/{machineID}"), handler: s.handleServerSections},
+		{method: http.MethodGet, pattern: splitPath("/api/resources"), handler: s.handleResources},
+	}
+}
+
+// AuthorizePIN links the pin with code to the account, as if the user entered it on plex.tv/link
+func (s *Server) AuthorizePIN(code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.pins {
+		if strings.EqualFold(p.code, code) {
+			p.linked = true
+			return nil
+		}
+	}
+
+	return fmt.Errorf("plextest: unknown pin %q", code)
+}
+
+func (s *Server) userJSON() map[string]interface{} {
+	account := s.fixtures.Account
+
+	return map[string]interface{}{
+		"id":        account.ID,
+		"uuid":      account.UUID,
+		"username":  account.Username,
+		"email":     account.Email,
+		"title":     account.Title,
+		"authToken": s.fixtures.Token,
+		"subscription": map[string]interface{}{
+			"active": account.PlexPass,
+		},
+	}
+}
+
+func (s *Server) handleSignIn(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	account := s.fixtures.Account
+
+	login := r.PostForm.Get("login")
+
+	if (login != account.Username && login != account.Email) || r.PostForm.Get("password") != account.Password {
+		writeError(w, r, http.StatusUnauthorized, "Invalid email, username, or password.")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, s.userJSON())
+}
+
+func (s *Server) pinJSON(p *pin) map[string]interface{} {
+	var authToken interface{}
+
+	if p.linked {
+		authToken = s.fixtures.Token
+	}
+
+	return map[string]interface{}{
+		"id":               p.id,
+		"code":             p.code,
+		"clientIdentifier": p.clientIdentifier,
+		"createdAt":        p.createdAt.UTC().Format(time.RFC3339),
+		"expiresAt":        p.createdAt.Add(15 * time.Minute).UTC().Format(time.RFC3339),
+		"expiresIn":        900,
+		"trusted":          false,
+		"authToken":        authToken,
+	}
+}
+
+func (s *Server) handleRequestPIN(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	clientIdentifier := r.Header.Get("X-Plex-Client-Identifier")
+
+	if clientIdentifier == "" {
+		writeError(w, r, http.StatusBadRequest, "X-Plex-Client-Identifier is missing")
+		return
+	}
+
+	id := s.newID()
+
+	p := &pin{
+		id:               id,
+		code:             fmt.Sprintf("P%03d", id%1000),
+		clientIdentifier: clientIdentifier,
+		createdAt:        time.Now(),
+	}
+
+	s.pins = append(s.pins, p)
+
+	writeJSON(w, http.StatusCreated, s.pinJSON(p))
+}
+
+func (s *Server) handleCheckPIN(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	id, _ := strconv.Atoi(strings.TrimSuffix(params["pin"], ".json"))
+
+	for _, p := range s.pins {
+		// plex.tv only answers the client that requested the pin
+		if p.id != id || p.clientIdentifier != r.Header.Get("X-Plex-Client-Identifier") {
+			continue
+		}
+
+		writeJSON(w, http.StatusOK, s.pinJSON(p))
+
+		return
+	}
+
+	writeError(w, r, http.StatusNotFound, "Code not found or expired")
+}
+
+func (s *Server) handleLinkPIN(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	code := r.PostForm.Get("code")
+
+	for _, p := range s.pins {
+		if strings.EqualFold(p.code, code) {
+			p.linked = true
+			w.WriteHeader(http.StatusNoContent)
+
+			return
+		}
+	}
+
+	writeError(w, r, http.StatusNotFound, "Code not found or expired")
+}
+
+func (s *Server) requirePlexPass(w http.ResponseWriter, r *http.Request) bool {
+	if s.fixtures.Account.PlexPass {
+		return true
+	}
+
+	writeError(w, r, http.StatusForbidden, "This feature requires an active Plex Pass subscription")
+
+	return false
+}
+
+func (s *Server) handleGetWebhooks(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	if !s.requirePlexPass(w, r) {
+		return
+	}
+
+	hooks := []map[string]string{}
+
+	for _, hook := range s.fixtures.Webhooks {
+		hooks = append(hooks, map[string]string{"url": hook})
+	}
+
+	writeJSON(w, http.StatusOK, hooks)
+}
+
+func (s *Server) handleSetWebhooks(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	if !s.requirePlexPass(w, r) {
+		return
+	}
+
+	var hooks []string
+
+	for _, hook := range r.PostForm["urls[]"] {
+		if hook != "" {
+			hooks = append(hooks, hook)
+		}
+	}
+
+	s.fixtures.Webhooks = hooks
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	account := s.fixtures.Account
+
+	status := "Inactive"
+
+	if account.PlexPass {
+		status = "Active"
+	}
+
+	user := newElement("user",
+		"id", account.ID,
+		"uuid", account.UUID,
+		"username", account.Username,
+		"email", account.Email,
+		"title", account.Title,
+		"authToken", s.fixtures.Token,
+		"hasPassword", account.Password != "",
+	)
+
+	user.add("subscription", newElement("subscription", "active", account.PlexPass, "status", status))
+
+	writeXML(w, http.StatusOK, user)
+}
+
+func (s *Server) handleFriends(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	root := newElement("MediaContainer",
+		"friendlyName", "myPlex",
+		"identifier", "com.plexapp.plugins.myplex",
+		"machineIdentifier", s.fixtures.MachineIdentifier,
+		"totalSize", len(s.fixtures.Friends),
+		"size", len(s.fixtures.Friends),
+	)
+
+	for _, friend := range s.fixtures.Friends {
+		root.add("User", newElement("User",
+			"id", friend.ID,
+			"title", friend.Title,
+			"username", friend.Username,
+			"email", friend.Email,
+		))
+	}
+
+	writeXML(w, http.StatusOK, root)
+}
+
+func (s *Server) handleRemoveFriend(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	id, _ := strconv.Atoi(params["id"])
+
+	for ii, friend := range s.fixtures.Friends {
+		if friend.ID != id {
+			continue
+		}
+
+		s.fixtures.Friends = append(s.fixtures.Friends[:ii], s.fixtures.Friends[ii+1:]...)
+
+		writeXML(w, http.StatusOK, newElement("Response", "code", 0, "status", "Success"))
+
+		return
+	}
+
+	writeXML(w, http.StatusBadRequest, newElement("Response", "code", 1, "status", "Friend not found"))
+}
+
+func (s *Server) handleValidateUser(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	writeXML(w, http.StatusOK, newElement("Response", "code", 0, "status", "Valid user"))
+}
+
+// hostPort returns the address and port the fake listens on
+func (s *Server) hostPort() (address, port string) {
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	if ii := strings.LastIndex(host, ":"); ii >= 0 {
+		return host[:ii], host[ii+1:]
+	}
+
+	return host, "80"
+}
+
+func (s *Server) serverElement() *element {
+	address, port := s.hostPort()
+
+	return newElement("Server",
+		"accessToken", s.fixtures.Token,
+		"name", s.fixtures.FriendlyName,
+		"address", address,
+		"port", port,
+		"version", s.fixtures.Version,
+		"scheme", "http",
+		"host", address,
+		"localAddresses", address,
+		"machineIdentifier", s.fixtures.MachineIdentifier,
+		"createdAt", int64(1600000000),
+		"updatedAt", int64(1600000000),
+		"owned", 1,
+		"synced", 0,
+	)
+}
+
+func (s *Server) handleServers(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	root := newElement("MediaContainer",
+		"friendlyName", "myPlex",
+		"identifier", "com.plexapp.plugins.myplex",
+		"machineIdentifier", s.fixtures.MachineIdentifier,
+		"size", 1,
+	)
+
+	root.add("Server", s.serverElement())
+
+	writeXML(w, http.StatusOK, root)
+}
+
+func (s *Server) handleServerSections(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if params["machineID"] != s.fixtures.MachineIdentifier {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	server := s.serverElement()
+
+	for _, library := range s.fixtures.Libraries {
+		server.add("Section", newElement("Section",
+			"id", library.Key,
+			"key", library.Key,
+			"type", library.Type,
+			"title", library.Title,
+		))
+	}
+
+	root := newElement("MediaContainer",
+		"friendlyName", "myPlex",
+		"identifier", "com.plexapp.plugins.myplex",
+		"machineIdentifier", s.fixtures.MachineIdentifier,
+		"size", 1,
+	)
+
+	root.add("Server", server)
+
+	writeXML(w, http.StatusOK, root)
+}
+
+func (s *Server) handleResources(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	address, port := s.hostPort()
+
+	device := newElement("Device",
+		"name", s.fixtures.FriendlyName,
+		"product", "Plex Media Server",
+		"productVersion", s.fixtures.Version,
+		"platform", "Linux",
+		"clientIdentifier", s.fixtures.MachineIdentifier,
+		"createdAt", int64(1600000000),
+		"lastSeenAt", time.Now().Unix(),
+		"provides", "server",
+		"owned", 1,
+		"accessToken", s.fixtures.Token,
+		"presence", 1,
+	)
+
+	device.add("Connection", newElement("Connection",
+		"protocol", "http",
+		"address", address,
+		"port", port,
+		"uri", s.URL,
+		"local", 1,
+	))
+
+	root := newElement("MediaContainer", "size", 1)
+
+	root.add("Device", device)
+
+	writeXML(w, http.StatusOK, root)
+}
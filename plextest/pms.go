@@ -0,0 +1,739 @@
+package plextest
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// metadataTypes maps the plex type names to the numbers used by the type query parameter
+var metadataTypes = map[string]int{
+	"movie":   1,
+	"show":    2,
+	"season":  3,
+	"episode": 4,
+	"artist":  8,
+	"album":   9,
+	"track":   10,
+}
+
+func (s *Server) pmsRoutes() []route {
+	return []route{
+		{method: http.MethodGet, pattern: splitPath("/"), handler: s.handleRoot},
+		{method: http.MethodGet, pattern: splitPath("/identity"), public: true, handler: s.handleIdentity},
+		{method: http.MethodGet, pattern: splitPath("/library/sections"), handler: s.handleLibraries},
+		{method: http.MethodPost, pattern: splitPath("/library/sections"), handler: s.handleCreateLibrary},
+		{method: http.MethodPut, pattern: splitPath("/library/sections/{section}"), handler: s.handleUpdateLibrary},
+		{method: http.MethodDelete, pattern: splitPath("/library/sections/{section}"), handler: s.handleDeleteLibrary},
+		{method: http.MethodGet, pattern: splitPath("/library/sections/{section}/prefs"), handler: s.handleLibraryPrefs},
+		{method: http.MethodPut, pattern: splitPath("/library/sections/{section}/prefs"), handler: s.handleSetLibraryPrefs},
+		{method: http.MethodGet, pattern: splitPath("/library/sections/{section}/all"), handler: s.handleLibraryContent},
+		{method: http.MethodPut, pattern: splitPath("/library/sections/{section}/all"), handler: s.handleEditLibraryContent},
+		{method: http.MethodGet, pattern: splitPath("/library/sections/{section}/filters"), handler: s.handleLibraryFilters},
+		{method: http.MethodGet, pattern: splitPath("/library/sections/{section}/sorts"), handler: s.handleLibrarySorts},
+		{method: http.MethodGet, pattern: splitPath("/library/sections/{section}/refresh"), handler: s.handleRefreshLibrary},
+		{method: http.MethodDelete, pattern: splitPath("/library/sections/{section}/refresh"), handler: s.handleCancelRefreshLibrary},
+		{method: http.MethodGet, pattern: splitPath("/library/metadata/{ratingKey}"), handler: s.handleMetadata},
+		{method: http.MethodDelete, pattern: splitPath("/library/metadata/{ratingKey}"), handler: s.handleDeleteMetadata},
+		{method: http.MethodGet, pattern: splitPath("/library/metadata/{ratingKey}/children"), handler: s.handleMetadataChildren},
+		{method: http.MethodDelete, pattern: splitPath("/library/metadata/{ratingKey}/media/{media}"), handler: s.handleDeleteMedia},
+		{method: http.MethodGet, pattern: splitPath("/library/metadata/{ratingKey}/matches"), handler: s.handleMatches},
+		{method: http.MethodPut, pattern: splitPath("/library/metadata/{ratingKey}/match"), handler: s.handleMatch},
+		{method: http.MethodPut, pattern: splitPath("/library/metadata/{ratingKey}/unmatch"), handler: s.handleUnmatch},
+		{method: http.MethodPut, pattern: splitPath("/library/metadata/{ratingKey}/refresh"), handler: s.handleRefreshMetadata},
+		{method: http.MethodGet, pattern: splitPath("/library/onDeck"), handler: s.handleOnDeck},
+		{method: http.MethodGet, pattern: splitPath("/search"), handler: s.handleSearch},
+		{method: http.MethodGet, pattern: splitPath("/status/sessions"), handler: s.handleSessions},
+		{method: http.MethodGet, pattern: splitPath("/status/sessions/terminate"), handler: s.handleTerminateSession},
+		{method: http.MethodGet, pattern: splitPath("/status/sessions/history/all"), handler: s.handleHistory},
+		{method: http.MethodGet, pattern: splitPath("/transcode/sessions"), handler: s.handleTranscodeSessions},
+		{method: http.MethodGet, pattern: splitPath("/:/scrobble"), handler: s.handleScrobble},
+		{method: http.MethodGet, pattern: splitPath("/:/unscrobble"), handler: s.handleUnscrobble},
+		{method: http.MethodGet, pattern: splitPath("/:/progress"), handler: s.handleProgress},
+		{method: http.MethodPut, pattern: splitPath("/:/rate"), handler: s.handleRate},
+		{method: http.MethodPut, pattern: splitPath("/library/sections/{section}/emptyTrash"), handler: s.handleEmptyTrash},
+		{method: http.MethodPut, pattern: splitPath("/library/clean/bundles"), handler: s.handleCleanBundles},
+		{method: http.MethodPut, pattern: splitPath("/library/optimize"), handler: s.handleOptimize},
+		{method: http.MethodGet, pattern: splitPath("/butler"), handler: s.handleButlerTasks},
+		{method: http.MethodPost, pattern: splitPath("/butler/{task}"), handler: s.handleStartButlerTask},
+		{method: http.MethodDelete, pattern: splitPath("/butler/{task}"), handler: s.handleStopButlerTask},
+		{method: http.MethodGet, pattern: splitPath("/activities"), handler: s.handleActivities},
+		{method: http.MethodDelete, pattern: splitPath("/activities/{uuid}"), handler: s.handleCancelActivity},
+		{method: http.MethodGet, pattern: splitPath("/:/prefs"), handler: s.handlePrefs},
+		{method: http.MethodPut, pattern: splitPath("/:/prefs"), handler: s.handleSetPrefs},
+		{method: http.MethodPut, pattern: splitPath("/actions/removeFromContinueWatching"), handler: s.handleRemoveFromContinueWatching},
+		{method: http.MethodGet, pattern: splitPath("/library/sections/{section}/collections"), handler: s.handleCollections},
+		{method: http.MethodPost, pattern: splitPath("/library/collections"), handler: s.handleCreateCollection},
+		{method: http.MethodDelete, pattern: splitPath("/library/collections/{collection}"), handler: s.handleDeleteCollection},
+		{method: http.MethodGet, pattern: splitPath("/library/collections/{collection}/children"), handler: s.handleCollectionItems},
+		{method: http.MethodPut, pattern: splitPath("/library/collections/{collection}/items"), handler: s.handleAddCollectionItems},
+		{method: http.MethodDelete, pattern: splitPath("/library/collections/{collection}/items/{ratingKey}"), handler: s.handleRemoveCollectionItem},
+		{method: http.MethodPut, pattern: splitPath("/library/metadata/{ratingKey}/prefs"), handler: s.handleCollectionPrefs},
+		{method: http.MethodPost, pattern: splitPath("/library/metadata/{ratingKey}/posters"), handler: s.handleCollectionPoster},
+		{method: http.MethodGet, pattern: splitPath("/hubs"), handler: s.handleHubs},
+		{method: http.MethodGet, pattern: splitPath("/hubs/search"), handler: s.handleHubSearch},
+		{method: http.MethodGet, pattern: splitPath("/hubs/sections/{section}"), handler: s.handleSectionHubs},
+		{method: http.MethodGet, pattern: splitPath("/playlists"), handler: s.handlePlaylists},
+		{method: http.MethodPost, pattern: splitPath("/playlists"), handler: s.handleCreatePlaylist},
+		{method: http.MethodGet, pattern: splitPath("/playlists/{playlist}"), handler: s.handlePlaylist},
+		{method: http.MethodPut, pattern: splitPath("/playlists/{playlist}"), handler: s.handleEditPlaylist},
+		{method: http.MethodDelete, pattern: splitPath("/playlists/{playlist}"), handler: s.handleDeletePlaylist},
+		{method: http.MethodGet, pattern: splitPath("/playlists/{playlist}/items"), handler: s.handlePlaylistItems},
+		{method: http.MethodPut, pattern: splitPath("/playlists/{playlist}/items"), handler: s.handleAddPlaylistItems},
+		{method: http.MethodDelete, pattern: splitPath("/playlists/{playlist}/items/{item}"), handler: s.handleRemovePlaylistItem},
+		{method: http.MethodPut, pattern: splitPath("/playlists/{playlist}/items/{item}/move"), handler: s.handleMovePlaylistItem},
+	}
+}
+
+func (s *Server) findLibrary(key string) (int, bool) {
+	for ii, library := range s.fixtures.Libraries {
+		if library.Key == key {
+			return ii, true
+		}
+	}
+
+	return -1, false
+}
+
+func (s *Server) findItem(ratingKey string) (int, bool) {
+	for ii, item := range s.fixtures.Items {
+		if item.RatingKey == ratingKey {
+			return ii, true
+		}
+	}
+
+	return -1, false
+}
+
+func (s *Server) container(keyValues ...interface{}) *element {
+	root := newElement("MediaContainer",
+		"machineIdentifier", s.fixtures.MachineIdentifier,
+	)
+
+	return root.set(keyValues...)
+}
+
+// itemElementName returns the xml element name of an item of mediaType. Playable
+// items are Video or Track elements, everything else is a Directory
+func itemElementName(mediaType string) string {
+	switch mediaType {
+	case "movie", "episode", "clip":
+		return "Video"
+	case "track":
+		return "Track"
+	}
+
+	return "Directory"
+}
+
+// itemElement renders an item the way /library/metadata does
+func (s *Server) itemElement(item Item) *element {
+	guid := item.GUID
+
+	if guid == "" {
+		guid = "plex://" + item.Type + "/" + item.RatingKey
+	}
+
+	e := newElement(itemElementName(item.Type),
+		"ratingKey", item.RatingKey,
+		"key", "/library/metadata/"+item.RatingKey,
+		"guid", guid,
+		"type", item.Type,
+		"title", item.Title,
+		"summary", item.Summary,
+		"addedAt", item.AddedAt,
+		"updatedAt", item.AddedAt,
+	)
+
+	if item.Year != 0 {
+		e.set("year", item.Year)
+	}
+
+	if item.Index != 0 {
+		e.set("index", item.Index)
+	}
+
+	if item.Duration != 0 {
+		e.set("duration", item.Duration)
+	}
+
+	if item.ViewOffset != 0 {
+		e.set("viewOffset", item.ViewOffset)
+	}
+
+	if item.ViewCount != 0 {
+		e.set("viewCount", item.ViewCount)
+	}
+
+	if item.UserRating != 0 {
+		e.set("userRating", item.UserRating)
+	}
+
+	if item.Type == "show" || item.Type == "season" {
+		leaves := s.leaves(item.RatingKey)
+		viewed := 0
+
+		for _, leaf := range leaves {
+			if s.fixtures.Items[leaf].ViewCount > 0 {
+				viewed++
+			}
+		}
+
+		e.set("leafCount", len(leaves), "viewedLeafCount", viewed)
+	}
+
+	if ii, ok := s.findLibrary(item.LibraryKey); ok {
+		library := s.fixtures.Libraries[ii]
+
+		libraryID, _ := strconv.Atoi(library.Key)
+
+		e.set(
+			"librarySectionID", libraryID,
+			"librarySectionKey", "/library/sections/"+library.Key,
+			"librarySectionTitle", library.Title,
+		)
+	}
+
+	if parentIndex, ok := s.findItem(item.ParentRatingKey); ok {
+		parent := s.fixtures.Items[parentIndex]
+
+		e.set(
+			"parentRatingKey", parent.RatingKey,
+			"parentKey", "/library/metadata/"+parent.RatingKey,
+			"parentTitle", parent.Title,
+			"parentIndex", parent.Index,
+		)
+
+		if grandparentIndex, ok := s.findItem(parent.ParentRatingKey); ok {
+			grandparent := s.fixtures.Items[grandparentIndex]
+
+			e.set(
+				"grandparentRatingKey", grandparent.RatingKey,
+				"grandparentKey", "/library/metadata/"+grandparent.RatingKey,
+				"grandparentTitle", grandparent.Title,
+			)
+		}
+	}
+
+	for _, field := range []struct{ key, value string }{
+		{"titleSort", item.TitleSort},
+		{"originalTitle", item.OriginalTitle},
+		{"contentRating", item.ContentRating},
+		{"studio", item.Studio},
+		{"tagline", item.Tagline},
+		{"originallyAvailableAt", item.OriginallyAvailableAt},
+	} {
+		if field.value != "" {
+			e.set(field.key, field.value)
+		}
+	}
+
+	for _, tags := range []struct {
+		name string
+		tags []string
+	}{
+		{"Genre", item.Genres},
+		{"Director", item.Directors},
+		{"Writer", item.Writers},
+		{"Collection", item.Collections},
+		{"Label", item.Labels},
+	} {
+		for _, tag := range tags.tags {
+			e.add(tags.name, newElement(tags.name, "tag", tag))
+		}
+	}
+
+	for _, id := range item.AltGUIDs {
+		e.add("Guid", newElement("Guid", "id", id))
+	}
+
+	for _, field := range item.LockedFields {
+		e.add("Field", newElement("Field", "name", field, "locked", true))
+	}
+
+	if item.File != "" {
+		part := newElement("Part",
+			"id", item.RatingKey,
+			"key", "/library/parts/"+item.RatingKey+"/file",
+			"file", item.File,
+			"duration", item.Duration,
+		)
+
+		media := newElement("Media", "id", item.RatingKey, "duration", item.Duration)
+
+		e.add("Media", media.add("Part", part))
+	}
+
+	return e
+}
+
+// writeItems writes items honoring the X-Plex-Container-Start and X-Plex-Container-Size
+// headers or query parameters
+func (s *Server) writeItems(w http.ResponseWriter, r *http.Request, root *element, items []Item) {
+	start, size := containerRange(r)
+	total := len(items)
+
+	if start > total {
+		start = total
+	}
+
+	end := total
+
+	if size >= 0 && start+size < total {
+		end = start + size
+	}
+
+	page := items[start:end]
+
+	root.set("size", len(page), "totalSize", total, "offset", start)
+
+	for _, item := range page {
+		root.add("Metadata", s.itemElement(item))
+	}
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+func containerRange(r *http.Request) (start, size int) {
+	value := func(name string) string {
+		if v := r.Header.Get(name); v != "" {
+			return v
+		}
+
+		return r.URL.Query().Get(name)
+	}
+
+	start, _ = strconv.Atoi(value("X-Plex-Container-Start"))
+	size = -1
+
+	if v, err := strconv.Atoi(value("X-Plex-Container-Size")); err == nil {
+		size = v
+	}
+
+	if start < 0 {
+		start = 0
+	}
+
+	return start, size
+}
+
+// ownerFeatures are the features a Plex Pass adds to the account of the server owner
+const ownerFeatures = "session_kick,webhooks,collections,content_filter,hardware_transcoding"
+
+func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	root := s.container(
+		"friendlyName", s.fixtures.FriendlyName,
+		"version", s.fixtures.Version,
+		"platform", "Linux",
+		"platformVersion", "5.15",
+		"updatedAt", 1600000000,
+		"myPlex", true,
+		"myPlexUsername", s.fixtures.Account.Username,
+		"myPlexSubscription", s.fixtures.Account.PlexPass,
+		"allowSync", true,
+		"allowSharing", true,
+		"allowMediaDeletion", s.preference("allowMediaDeletion") == "true",
+		"multiuser", true,
+		"transcoderAudio", true,
+		"transcoderVideo", true,
+		"transcoderPhoto", true,
+		"transcoderSubtitles", true,
+		"transcoderLyrics", true,
+		"transcoderActiveVideoSessions", 0,
+		"transcoderVideoBitrates", "64,96,208,320,720,1500,2000,3000,4000,8000,10000,12000,20000",
+		"transcoderVideoQualities", "0,16,25,35,50,60,70,80,90,100,100,100,100",
+		"transcoderVideoResolutions", "128,128,160,240,320,480,768,720,720,1080,1080,1080,1080",
+		"size", 0,
+	)
+
+	if s.fixtures.Account.PlexPass {
+		root.set("ownerFeatures", ownerFeatures)
+	}
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+func (s *Server) handleIdentity(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	root := s.container("claimed", true, "version", s.fixtures.Version, "size", 0)
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+func (s *Server) handleLibraries(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	root := s.container("size", len(s.fixtures.Libraries), "title1", "Plex Library")
+
+	for _, library := range s.fixtures.Libraries {
+		directory := newElement("Directory",
+			"key", library.Key,
+			"title", library.Title,
+			"type", library.Type,
+			"agent", library.Agent,
+			"scanner", library.Scanner,
+			"language", library.Language,
+			"uuid", "plextest-library-"+library.Key,
+			"allowSync", true,
+			"refreshing", false,
+		)
+
+		for ii, location := range library.Locations {
+			directory.add("Location", newElement("Location", "id", ii+1, "path", location))
+		}
+
+		root.add("Directory", directory)
+	}
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+func (s *Server) handleCreateLibrary(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	query := r.URL.Query()
+
+	library := Library{
+		Key:       strconv.Itoa(s.newID()),
+		Title:     query.Get("name"),
+		Type:      query.Get("type"),
+		Agent:     query.Get("agent"),
+		Scanner:   query.Get("scanner"),
+		Language:  query.Get("language"),
+		Locations: query["location"],
+	}
+
+	if library.Title == "" || library.Type == "" || len(library.Locations) == 0 {
+		writeError(w, r, http.StatusBadRequest, "name, type and location are required")
+		return
+	}
+
+	if !setLibraryPrefs(&library, libraryPrefsQuery(query)) {
+		writeError(w, r, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	s.fixtures.Libraries = append(s.fixtures.Libraries, library)
+
+	writeContainer(w, r, http.StatusCreated, s.container("size", 0))
+}
+
+func (s *Server) handleDeleteLibrary(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findLibrary(params["section"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	s.fixtures.Libraries = append(s.fixtures.Libraries[:ii], s.fixtures.Libraries[ii+1:]...)
+
+	var items []Item
+
+	for _, item := range s.fixtures.Items {
+		if item.LibraryKey != params["section"] {
+			items = append(items, item)
+		}
+	}
+
+	s.fixtures.Items = items
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleLibraryContent(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findLibrary(params["section"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	library := s.fixtures.Libraries[ii]
+	items := s.libraryItems(library, r.URL.Query())
+
+	root := s.container(
+		"librarySectionID", library.Key,
+		"librarySectionTitle", library.Title,
+		"librarySectionUUID", "plextest-library-"+library.Key,
+	)
+
+	s.writeItems(w, r, root, items)
+}
+
+// libraryItems returns the items of library matching the type, filters and sort of query
+func (s *Server) libraryItems(library Library, query url.Values) []Item {
+	// without a type only the top level items of the library are listed
+	wantType := library.Type
+
+	if t := query.Get("type"); t != "" {
+		wantType = t
+	}
+
+	var items []Item
+
+	for _, item := range s.fixtures.Items {
+		if item.LibraryKey != library.Key {
+			continue
+		}
+
+		if item.Type != wantType && strconv.Itoa(metadataTypes[item.Type]) != wantType {
+			continue
+		}
+
+		if !matchesFilters(item, query) {
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	sortItems(items, query.Get("sort"))
+
+	return items
+}
+
+func hasLabel(item Item, label string) bool {
+	return containsFold(item.Labels, label)
+}
+
+// handleEditLibraryContent edits items the way AddLabelToMedia, RemoveLabelFromMedia and EditMetadata do:
+// field.value sets a field, field[N].tag.tag adds a tag, field[].tag.tag- removes a comma separated
+// list of tags and field.locked locks or unlocks a field
+func (s *Server) handleEditLibraryContent(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	query := r.URL.Query()
+
+	ii, ok := s.findItem(query.Get("id"))
+
+	if !ok || s.fixtures.Items[ii].LibraryKey != params["section"] {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	item := &s.fixtures.Items[ii]
+
+	for key, values := range query {
+		value := values[0]
+
+		switch {
+		case strings.HasSuffix(key, ".value"):
+			field := strings.TrimSuffix(key, ".value")
+
+			if field == "year" {
+				year, err := strconv.Atoi(value)
+
+				if err != nil {
+					writeError(w, r, http.StatusBadRequest, "Bad Request")
+					return
+				}
+
+				item.Year = year
+			} else if target := stringField(item, field); target != nil {
+				*target = value
+			}
+		case strings.HasSuffix(key, "].tag.tag"):
+			tags := tagField(item, key[:strings.Index(key, "[")])
+
+			if tags != nil && !containsFold(*tags, value) {
+				*tags = append(*tags, value)
+			}
+		case strings.HasSuffix(key, "[].tag.tag-"):
+			tags := tagField(item, strings.TrimSuffix(key, "[].tag.tag-"))
+
+			if tags == nil {
+				continue
+			}
+
+			for _, tag := range strings.Split(value, ",") {
+				*tags = removeFold(*tags, tag)
+			}
+		case strings.HasSuffix(key, ".locked"):
+			field := strings.TrimSuffix(key, ".locked")
+
+			item.LockedFields = removeFold(item.LockedFields, field)
+
+			if value == "1" {
+				item.LockedFields = append(item.LockedFields, field)
+			}
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func stringField(item *Item, field string) *string {
+	switch field {
+	case "title":
+		return &item.Title
+	case "titleSort":
+		return &item.TitleSort
+	case "originalTitle":
+		return &item.OriginalTitle
+	case "summary":
+		return &item.Summary
+	case "contentRating":
+		return &item.ContentRating
+	case "studio":
+		return &item.Studio
+	case "tagline":
+		return &item.Tagline
+	case "originallyAvailableAt":
+		return &item.OriginallyAvailableAt
+	}
+
+	return nil
+}
+
+func tagField(item *Item, field string) *[]string {
+	switch field {
+	case "genre":
+		return &item.Genres
+	case "director":
+		return &item.Directors
+	case "writer":
+		return &item.Writers
+	case "collection":
+		return &item.Collections
+	case "label":
+		return &item.Labels
+	}
+
+	return nil
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeFold(list []string, s string) []string {
+	var kept []string
+
+	for _, v := range list {
+		if !strings.EqualFold(v, s) {
+			kept = append(kept, v)
+		}
+	}
+
+	return kept
+}
+
+func (s *Server) handleMetadata(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findItem(params["ratingKey"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	root := s.container("size", 1)
+
+	root.add("Metadata", s.itemElement(s.fixtures.Items[ii]))
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+func (s *Server) handleMetadataChildren(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ii, ok := s.findItem(params["ratingKey"])
+
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	parent := s.fixtures.Items[ii]
+
+	var children []Item
+
+	for _, item := range s.fixtures.Items {
+		if item.ParentRatingKey == parent.RatingKey {
+			children = append(children, item)
+		}
+	}
+
+	sort.SliceStable(children, func(a, b int) bool {
+		return children[a].Index < children[b].Index
+	})
+
+	root := s.container(
+		"key", parent.RatingKey,
+		"parentTitle", parent.Title,
+	)
+
+	s.writeItems(w, r, root, children)
+}
+
+func (s *Server) handleOnDeck(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.writeItems(w, r, s.container(), s.onDeckItems(""))
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	query := strings.ToLower(r.URL.Query().Get("query"))
+
+	var items []Item
+
+	for _, item := range s.fixtures.Items {
+		if query != "" && strings.Contains(strings.ToLower(item.Title), query) {
+			items = append(items, item)
+		}
+	}
+
+	s.writeItems(w, r, s.container(), items)
+}
+
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	root := s.container("size", len(s.fixtures.Sessions))
+
+	for _, session := range s.fixtures.Sessions {
+		ii, ok := s.findItem(session.RatingKey)
+
+		if !ok {
+			continue
+		}
+
+		e := s.itemElement(s.fixtures.Items[ii])
+
+		e.set("sessionKey", session.SessionKey, "viewOffset", session.ViewOffset)
+
+		e.addOne("User", newElement("User", "id", strconv.Itoa(session.UserID), "title", session.Username))
+		e.addOne("Player", newElement("Player",
+			"title", session.Player,
+			"product", session.Product,
+			"state", session.State,
+			"machineIdentifier", "plextest-player-"+session.SessionKey,
+		))
+		e.addOne("Session", newElement("Session", "id", session.ID, "location", "lan"))
+
+		root.add("Metadata", e)
+	}
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+func (s *Server) handleTerminateSession(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	if !s.requirePlexPass(w, r) {
+		return
+	}
+
+	id := r.URL.Query().Get("sessionId")
+
+	for ii, session := range s.fixtures.Sessions {
+		if session.ID != id {
+			continue
+		}
+
+		s.fixtures.Sessions = append(s.fixtures.Sessions[:ii], s.fixtures.Sessions[ii+1:]...)
+
+		w.WriteHeader(http.StatusOK)
+
+		return
+	}
+
+	writeError(w, r, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) handleTranscodeSessions(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	writeContainer(w, r, http.StatusOK, s.container("size", 0))
+}
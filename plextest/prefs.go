@@ -0,0 +1,141 @@
+package plextest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func (s *Server) findPreference(id string) (int, bool) {
+	for ii, pref := range s.fixtures.Preferences {
+		if pref.ID == id {
+			return ii, true
+		}
+	}
+
+	return -1, false
+}
+
+// preference returns the value of the server setting with id, empty when there is none
+func (s *Server) preference(id string) string {
+	if ii, ok := s.findPreference(id); ok {
+		return s.fixtures.Preferences[ii].Value
+	}
+
+	return ""
+}
+
+// typedValue returns value as the json type of a setting of prefType
+func typedValue(prefType, value string) interface{} {
+	switch prefType {
+	case "bool":
+		return value == "true"
+	case "int":
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	case "double":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+
+	return value
+}
+
+// validValue reports whether value is accepted by pref, normalizing booleans sent as 1 or 0
+func validValue(pref Preference, value string) (string, bool) {
+	switch pref.Type {
+	case "bool":
+		switch value {
+		case "1", "true":
+			return "true", true
+		case "0", "false":
+			return "false", true
+		}
+
+		return "", false
+	case "int":
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", false
+		}
+	case "double":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", false
+		}
+	}
+
+	if pref.EnumValues == "" {
+		return value, true
+	}
+
+	for _, pair := range strings.Split(pref.EnumValues, "|") {
+		if strings.SplitN(pair, ":", 2)[0] == value {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+func preferenceElement(pref Preference) *element {
+	e := newElement("Setting",
+		"id", pref.ID,
+		"label", pref.Label,
+		"type", pref.Type,
+		"default", typedValue(pref.Type, pref.Default),
+		"value", typedValue(pref.Type, pref.Value),
+		"hidden", pref.Hidden,
+		"advanced", pref.Advanced,
+		"group", pref.Group,
+	)
+
+	if pref.EnumValues != "" {
+		e.set("enumValues", pref.EnumValues)
+	}
+
+	return e
+}
+
+func (s *Server) handlePrefs(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	root := s.container("size", len(s.fixtures.Preferences))
+
+	for _, pref := range s.fixtures.Preferences {
+		root.add("Setting", preferenceElement(pref))
+	}
+
+	writeContainer(w, r, http.StatusOK, root)
+}
+
+// handleSetPrefs applies every setting of the query or none of them when one is unknown or invalid
+func (s *Server) handleSetPrefs(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	values := map[int]string{}
+
+	for id := range r.URL.Query() {
+		if strings.HasPrefix(id, "X-Plex-") {
+			continue
+		}
+
+		ii, ok := s.findPreference(id)
+
+		if !ok {
+			writeError(w, r, http.StatusBadRequest, "Bad Request")
+			return
+		}
+
+		value, ok := validValue(s.fixtures.Preferences[ii], r.URL.Query().Get(id))
+
+		if !ok {
+			writeError(w, r, http.StatusBadRequest, "Bad Request")
+			return
+		}
+
+		values[ii] = value
+	}
+
+	for ii, value := range values {
+		s.fixtures.Preferences[ii].Value = value
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,263 @@
+// Package plextest provides an in-memory fake of a Plex Media Server and of plex.tv
+// for testing code that uses the plex client without a real server or network access.
+//
+//	server := plextest.NewServer(plextest.DefaultFixtures())
+//	defer server.Close()
+//
+//	plexConn, err := plex.New(server.URL, server.Token(), plex.WithPlexTVURL(server.URL))
+//
+// The same http server answers both the Plex Media Server and the plex.tv endpoints.
+// Plex Media Server endpoints answer json or xml depending on the Accept header,
+// legacy plex.tv endpoints always answer xml like the real ones do
+package plextest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// Request is a request received by the fake server
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	// Form holds the url encoded body of POST and PUT requests
+	Form url.Values
+}
+
+// Server is a fake Plex Media Server and plex.tv backed by Fixtures.
+// Requests that change state, such as creating a library or adding a webhook,
+// change the fixtures so following requests see the change
+type Server struct {
+	// URL is the base url of the fake, i.e. http://127.0.0.1:41234
+	URL string
+
+	srv *httptest.Server
+
+	mu        sync.Mutex
+	fixtures  Fixtures
+	routes    []route
+	overrides []route
+	requests  []Request
+	pins      []*pin
+	nextID    int
+
+	subscribers   map[*websocket.Conn]bool
+	subscribersCh chan struct{}
+}
+
+// NewServer starts a fake server seeded with fixtures. Close it when done
+func NewServer(fixtures Fixtures) *Server {
+	s := &Server{
+		fixtures:      fixtures,
+		nextID:        1000,
+		subscribers:   map[*websocket.Conn]bool{},
+		subscribersCh: make(chan struct{}),
+	}
+
+	if s.fixtures.Token == "" {
+		s.fixtures.Token = DefaultFixtures().Token
+	}
+
+	if s.fixtures.MachineIdentifier == "" {
+		s.fixtures.MachineIdentifier = DefaultFixtures().MachineIdentifier
+	}
+
+	s.routes = append(s.pmsRoutes(), s.plexTVRoutes()...)
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+
+	return s
+}
+
+// Close shuts down the server and disconnects notification subscribers
+func (s *Server) Close() {
+	s.mu.Lock()
+
+	for conn := range s.subscribers {
+		conn.Close()
+	}
+
+	s.mu.Unlock()
+
+	s.srv.Close()
+}
+
+// Token returns the auth token accepted by the server
+func (s *Server) Token() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.fixtures.Token
+}
+
+// Fixtures returns a copy of the current state of the server
+func (s *Server) Fixtures() Fixtures {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fixtures := s.fixtures
+
+	fixtures.Libraries = append([]Library(nil), s.fixtures.Libraries...)
+	fixtures.Items = append([]Item(nil), s.fixtures.Items...)
+	fixtures.Sessions = append([]Session(nil), s.fixtures.Sessions...)
+	fixtures.Friends = append([]Friend(nil), s.fixtures.Friends...)
+	fixtures.Webhooks = append([]string(nil), s.fixtures.Webhooks...)
+	fixtures.Activities = append([]Activity(nil), s.fixtures.Activities...)
+
+	return fixtures
+}
+
+// Requests returns the requests received so far, oldest first
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// Handle overrides the endpoint matching method and path with handler, i.e. to
+// inject failures. Path segments in braces match any value: /library/metadata/{key}
+func (s *Server) Handle(method, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.overrides = append([]route{{
+		method:  method,
+		pattern: splitPath(path),
+		handler: func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+			handler(w, r)
+		},
+	}}, s.overrides...)
+}
+
+// route is an endpoint of the fake server. Handlers of built in routes run with s.mu held
+type route struct {
+	method  string
+	pattern []string
+	// public routes do not require the token
+	public  bool
+	handler func(w http.ResponseWriter, r *http.Request, params map[string]string)
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func (rt route) match(method string, segments []string) (map[string]string, bool) {
+	if rt.method != method || len(rt.pattern) != len(segments) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+
+	for ii, part := range rt.pattern {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			params[strings.Trim(part, "{}")] = segments[ii]
+			continue
+		}
+
+		if part != segments[ii] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	// the websocket handler blocks for the lifetime of the connection
+	if r.URL.Path == notificationsPath {
+		s.record(r)
+		s.serveNotifications(w, r)
+		return
+	}
+
+	s.mu.Lock()
+
+	s.recordLocked(r)
+
+	segments := splitPath(r.URL.Path)
+
+	// overrides run without the lock so they can call the methods of s
+	for _, rt := range s.overrides {
+		if params, ok := rt.match(r.Method, segments); ok {
+			s.mu.Unlock()
+			rt.handler(w, r, params)
+
+			return
+		}
+	}
+
+	defer s.mu.Unlock()
+
+	for _, rt := range s.routes {
+		params, ok := rt.match(r.Method, segments)
+
+		if !ok {
+			continue
+		}
+
+		if !rt.public && !s.authorized(r) {
+			writeError(w, r, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		rt.handler(w, r, params)
+
+		return
+	}
+
+	writeError(w, r, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token := r.Header.Get("X-Plex-Token")
+
+	if token == "" {
+		token = r.URL.Query().Get("X-Plex-Token")
+	}
+
+	return token != "" && token == s.fixtures.Token
+}
+
+func (s *Server) record(r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recordLocked(r)
+}
+
+func (s *Server) recordLocked(r *http.Request) {
+	req := Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: http.Header{},
+		Form:   url.Values{},
+	}
+
+	for key, values := range r.Header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if err := r.ParseForm(); err == nil {
+			req.Form = r.PostForm
+		}
+	}
+
+	s.requests = append(s.requests, req)
+}
+
+func (s *Server) newID() int {
+	s.nextID++
+
+	return s.nextID
+}
@@ -0,0 +1,231 @@
+package plextest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	plex "github.com/jrudio/go-plex-client"
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func newClient(t *testing.T, server *plextest.Server) *plex.Plex {
+	conn, err := plex.New(server.URL, server.Token(), plex.WithPlexTVURL(server.URL))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+func TestLibraryFlow(t *testing.T) {
+	server := plextest.NewServer(plextest.DefaultFixtures())
+	defer server.Close()
+
+	conn := newClient(t, server)
+
+	libraries, err := conn.GetLibraries()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count := len(libraries.MediaContainer.Directory); count != 2 {
+		t.Fatalf("expected 2 libraries, got %d", count)
+	}
+
+	it := conn.IterateLibraryContent("1", "", 2)
+
+	var titles []string
+
+	for it.Next() {
+		titles = append(titles, it.Item().Title)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(titles, ",") != "Alien,Aliens,Blade Runner" || it.TotalSize() != 3 {
+		t.Errorf("unexpected library content %v (total %d)", titles, it.TotalSize())
+	}
+
+	episodes, err := conn.GetEpisodes("201")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count := len(episodes.MediaContainer.Metadata); count != 2 {
+		t.Errorf("expected 2 episodes, got %d", count)
+	}
+
+	if ok, err := conn.AddLabelToMedia("1", "1", "100", "kids", ""); err != nil || !ok {
+		t.Fatalf("add label: %v", err)
+	}
+
+	if labels := server.Fixtures().Items[0].Labels; len(labels) != 1 || labels[0] != "kids" {
+		t.Errorf("expected label kids, got %v", labels)
+	}
+
+	if err := conn.CreateLibrary(plex.CreateLibraryParams{
+		Name:        "Music",
+		Location:    "/data/music",
+		LibraryType: "artist",
+		Agent:       "tv.plex.agents.music",
+		Scanner:     "Plex Music",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := len(server.Fixtures().Libraries); count != 3 {
+		t.Errorf("expected 3 libraries after creating one, got %d", count)
+	}
+}
+
+func TestSessionFlow(t *testing.T) {
+	server := plextest.NewServer(plextest.DefaultFixtures())
+	defer server.Close()
+
+	conn := newClient(t, server)
+
+	sessions, err := conn.GetSessions()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sessions.MediaContainer.Metadata) != 1 {
+		t.Fatalf("expected 1 session, got %d", len(sessions.MediaContainer.Metadata))
+	}
+
+	session := sessions.MediaContainer.Metadata[0]
+
+	if session.GrandparentTitle != "The Expanse" || session.Player.State != "playing" || session.ViewOffset.Duration() != 10*time.Minute {
+		t.Errorf("unexpected session %+v", session)
+	}
+
+	if err := conn.TerminateSession(session.Session.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.TerminateSession(session.Session.ID, ""); !errors.Is(err, plex.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a terminated session, got %v", err)
+	}
+}
+
+func TestFriendsFlow(t *testing.T) {
+	server := plextest.NewServer(plextest.DefaultFixtures())
+	defer server.Close()
+
+	conn := newClient(t, server)
+
+	friends, err := conn.GetFriends()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(friends) != 1 || friends[0].Username != "friend" {
+		t.Fatalf("unexpected friends %+v", friends)
+	}
+
+	if ok, err := conn.RemoveFriend("2"); err != nil || !ok {
+		t.Fatalf("remove friend: %v", err)
+	}
+
+	if count := len(server.Fixtures().Friends); count != 0 {
+		t.Errorf("expected the friend to be removed, %d left", count)
+	}
+}
+
+func TestNotifications(t *testing.T) {
+	server := plextest.NewServer(plextest.DefaultFixtures())
+	defer server.Close()
+
+	conn := newClient(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	playing := make(chan plex.NotificationContainer, 1)
+
+	events := plex.NewNotificationEvents()
+	events.OnPlaying(func(n plex.NotificationContainer) {
+		playing <- n
+	})
+
+	conn.SubscribeToNotificationsCtx(ctx, events, nil, func(err error) {})
+
+	if err := server.WaitForSubscribers(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := server.NotifyPlaying("1", "paused", 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case n := <-playing:
+		if len(n.PlaySessionStateNotification) != 1 || n.PlaySessionStateNotification[0].State != "paused" {
+			t.Errorf("unexpected notification %+v", n)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the notification")
+	}
+}
+
+func TestHandleOverride(t *testing.T) {
+	server := plextest.NewServer(plextest.DefaultFixtures())
+	defer server.Close()
+
+	server.Handle(http.MethodGet, "/library/metadata/{ratingKey}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	conn := newClient(t, server)
+
+	if _, err := conn.GetMetadata("100"); err == nil {
+		t.Error("expected the override to fail the request")
+	}
+
+	requests := server.Requests()
+
+	if last := requests[len(requests)-1]; last.Path != "/library/metadata/100" || last.Header.Get("X-Plex-Token") != server.Token() {
+		t.Errorf("unexpected recorded request %+v", last)
+	}
+
+	conn.Token = "wrong"
+
+	if _, err := conn.GetLibraries(); !errors.Is(err, plex.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	fixtures, err := plextest.LoadFixtures(strings.NewReader(`{
+		"token": "abc",
+		"libraries": [{"key": "7", "title": "Photos", "type": "photo", "locations": ["/data/photos"]}]
+	}`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := plextest.NewServer(fixtures)
+	defer server.Close()
+
+	libraries, err := newClient(t, server).GetLibraries()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(libraries.MediaContainer.Directory) != 1 || libraries.MediaContainer.Directory[0].Location[0].Path != "/data/photos" {
+		t.Errorf("unexpected libraries %+v", libraries.MediaContainer.Directory)
+	}
+}
@@ -3,10 +3,8 @@ package plex
 // I'll slowly migrate plex.tv related functions to this file
 
 import (
-	"encoding/json"
-	"encoding/xml"
+	"context"
 	"errors"
-	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -23,12 +21,12 @@ type PinResponse struct {
 	ID               int             `json:"id"`
 	Code             string          `json:"code"`
 	ClientIdentifier string          `json:"clientIdentifier"`
-	CreatedAt        string          `json:"createdAt"`
-	ExpiresAt        string          `json:"expiresAt"`
-	ExpiresIn        json.Number     `json:"expiresIn"`
+	CreatedAt        Timestamp       `json:"createdAt"`
+	ExpiresAt        Timestamp       `json:"expiresAt"`
+	ExpiresIn        FlexInt         `json:"expiresIn"`
 	AuthToken        string          `json:"authToken"`
 	Errors           []ErrorResponse `json:"errors"`
-	Trusted          bool            `json:"trusted"`
+	Trusted          FlexBool        `json:"trusted"`
 	Location         struct {
 		Code         string `json:"code"`
 		Country      string `json:"country"`
@@ -38,8 +36,14 @@ type PinResponse struct {
 	}
 }
 
-// RequestPIN will retrieve a code (valid for 15 minutes) from plex.tv to link an app to your plex account
-func RequestPIN(requestHeaders headers) (PinResponse, error) {
+// RequestPIN will retrieve a code (valid for 15 minutes) from plex.tv to link an app to your plex account.
+// opts configure the client identity and http client the same way they do for New
+func RequestPIN(requestHeaders headers, opts ...Option) (PinResponse, error) {
+	return RequestPINCtx(context.Background(), requestHeaders, opts...)
+}
+
+// RequestPINCtx is like RequestPIN but carries ctx for cancellation and deadlines
+func RequestPINCtx(ctx context.Context, requestHeaders headers, opts ...Option) (PinResponse, error) {
 	endpoint := "/api/v2/pins.json"
 
 	// POST request and returns a 201 status code
@@ -54,11 +58,15 @@ func RequestPIN(requestHeaders headers) (PinResponse, error) {
 	// }
 	var pinInformation PinResponse
 
+	p := newPlex(opts)
+
 	if requestHeaders.ClientIdentifier == "" {
-		requestHeaders = defaultHeaders()
+		requestHeaders = p.Headers
 	}
 
-	resp, err := post(plexURL+endpoint, nil, requestHeaders)
+	p.ClientIdentifier = requestHeaders.ClientIdentifier
+
+	resp, err := p.post(ctx, p.plexTV()+endpoint, nil, requestHeaders)
 
 	if err != nil {
 		return pinInformation, err
@@ -67,10 +75,10 @@ func RequestPIN(requestHeaders headers) (PinResponse, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return pinInformation, errors.New(resp.Status)
+		return pinInformation, newAPIError(resp)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&pinInformation); err != nil {
+	if err := decodeResponse(resp, &pinInformation); err != nil {
 		return pinInformation, err
 	}
 
@@ -80,18 +88,24 @@ func RequestPIN(requestHeaders headers) (PinResponse, error) {
 // CheckPIN will return information related to the pin such as the auth token if your code has been approved.
 // will return an error if code expired or still not linked
 // clientIdentifier must be the same when requesting a pin
-func CheckPIN(id int, clientIdentifier string) (PinResponse, error) {
+func CheckPIN(id int, clientIdentifier string, opts ...Option) (PinResponse, error) {
+	return CheckPINCtx(context.Background(), id, clientIdentifier, opts...)
+}
+
+// CheckPINCtx is like CheckPIN but carries ctx for cancellation and deadlines
+func CheckPINCtx(ctx context.Context, id int, clientIdentifier string, opts ...Option) (PinResponse, error) {
 	endpoint := "/api/v2/pins/"
 
 	endpoint = endpoint + strconv.Itoa(id) + ".json"
 
-	headers := defaultHeaders()
+	p := newPlex(opts)
 
 	if clientIdentifier != "" {
-		headers.ClientIdentifier = clientIdentifier
+		p.ClientIdentifier = clientIdentifier
+		p.Headers.ClientIdentifier = clientIdentifier
 	}
 
-	resp, err := get(plexURL+endpoint, headers)
+	resp, err := p.get(ctx, p.plexTV()+endpoint, p.Headers)
 
 	if err != nil {
 		return PinResponse{}, err
@@ -99,13 +113,17 @@ func CheckPIN(id int, clientIdentifier string) (PinResponse, error) {
 
 	defer resp.Body.Close()
 
+	// code doesn't exist or expired
+	if resp.StatusCode != http.StatusOK {
+		return PinResponse{}, newAPIError(resp)
+	}
+
 	var pinInformation PinResponse
 
-	if err := json.NewDecoder(resp.Body).Decode(&pinInformation); err != nil {
+	if err := decodeResponse(resp, &pinInformation); err != nil {
 		return pinInformation, err
 	}
 
-	// code doesn't exist or expired
 	if len(pinInformation.Errors) > 0 {
 		return pinInformation, errors.New(pinInformation.Errors[0].Message)
 	}
@@ -121,6 +139,11 @@ func CheckPIN(id int, clientIdentifier string) (PinResponse, error) {
 
 // LinkAccount allows you to authorize an app via a 4 character pin. returns nil on success
 func (p Plex) LinkAccount(code string) error {
+	return p.LinkAccountCtx(context.Background(), code)
+}
+
+// LinkAccountCtx is like LinkAccount but carries ctx for cancellation and deadlines
+func (p Plex) LinkAccountCtx(ctx context.Context, code string) error {
 	endpoint := "/api/v2/pins/link.json"
 
 	body := url.Values{
@@ -132,7 +155,7 @@ func (p Plex) LinkAccount(code string) error {
 	headers.ContentType = "application/x-www-form-urlencoded"
 
 	// PUT request with 'code: <4-character-pin>' in the body
-	resp, err := p.put(plexURL+endpoint, []byte(body.Encode()), headers)
+	resp, err := p.put(ctx, p.plexTV()+endpoint, []byte(body.Encode()), headers)
 
 	if err != nil {
 		return err
@@ -146,11 +169,9 @@ func (p Plex) LinkAccount(code string) error {
 
 	// var
 
-	// json.NewDecoder(resp.Body).Decode()
-
 	// should return 204 for success
 	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf(ErrorLinkAccount, resp.Status)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -174,6 +195,11 @@ func (w webhookErr) Error() string {
 
 // GetWebhooks fetches all webhooks - requires plex pass
 func (p Plex) GetWebhooks() ([]string, error) {
+	return p.GetWebhooksCtx(context.Background())
+}
+
+// GetWebhooksCtx is like GetWebhooks but carries ctx for cancellation and deadlines
+func (p Plex) GetWebhooksCtx(ctx context.Context) ([]string, error) {
 	type Hooks struct {
 		URL string `json:"url"`
 	}
@@ -182,7 +208,7 @@ func (p Plex) GetWebhooks() ([]string, error) {
 
 	endpoint := "/api/v2/user/webhooks"
 
-	resp, err := p.get(plexURL+endpoint, p.Headers)
+	resp, err := p.get(ctx, p.plexTV()+endpoint, p.Headers)
 
 	if err != nil {
 		return webhooks, err
@@ -190,21 +216,13 @@ func (p Plex) GetWebhooks() ([]string, error) {
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= http.StatusBadRequest && resp.StatusCode < http.StatusInternalServerError {
-		var webhookErr webhookErr
-
-		if err := json.NewDecoder(resp.Body).Decode(&webhookErr); err != nil {
-			return webhooks, err
-		}
-
-		return webhooks, fmt.Errorf(ErrorWebhook, webhookErr.Error())
-	} else if resp.StatusCode != http.StatusOK {
-		return webhooks, fmt.Errorf(ErrorWebhook, resp.Status)
+	if resp.StatusCode != http.StatusOK {
+		return webhooks, newAPIError(resp)
 	}
 
 	var hook []Hooks
 
-	if err := json.NewDecoder(resp.Body).Decode(&hook); err != nil {
+	if err := decodeResponse(resp, &hook); err != nil {
 		return webhooks, err
 	}
 
@@ -217,8 +235,13 @@ func (p Plex) GetWebhooks() ([]string, error) {
 
 // AddWebhook creates a new webhook for your plex server to send metadata - requires plex pass
 func (p Plex) AddWebhook(webhook string) error {
+	return p.AddWebhookCtx(context.Background(), webhook)
+}
+
+// AddWebhookCtx is like AddWebhook but carries ctx for cancellation and deadlines
+func (p Plex) AddWebhookCtx(ctx context.Context, webhook string) error {
 	// get current webhooks and append ours to it
-	currentWebhooks, err := p.GetWebhooks()
+	currentWebhooks, err := p.GetWebhooksCtx(ctx)
 
 	if err != nil {
 		return err
@@ -226,12 +249,17 @@ func (p Plex) AddWebhook(webhook string) error {
 
 	currentWebhooks = append(currentWebhooks, webhook)
 
-	return p.SetWebhooks(currentWebhooks)
+	return p.SetWebhooksCtx(ctx, currentWebhooks)
 }
 
 // SetWebhooks will set your webhooks to whatever you pass as an argument
 // webhooks with a length of 0 will remove all webhooks
 func (p Plex) SetWebhooks(webhooks []string) error {
+	return p.SetWebhooksCtx(context.Background(), webhooks)
+}
+
+// SetWebhooksCtx is like SetWebhooks but carries ctx for cancellation and deadlines
+func (p Plex) SetWebhooksCtx(ctx context.Context, webhooks []string) error {
 	endpoint := "/api/v2/user/webhooks"
 
 	body := url.Values{}
@@ -248,7 +276,7 @@ func (p Plex) SetWebhooks(webhooks []string) error {
 
 	headers.ContentType = "application/x-www-form-urlencoded"
 
-	resp, err := p.post(plexURL+endpoint, []byte(body.Encode()), headers)
+	resp, err := p.post(ctx, p.plexTV()+endpoint, []byte(body.Encode()), headers)
 
 	if err != nil {
 		return err
@@ -257,7 +285,7 @@ func (p Plex) SetWebhooks(webhooks []string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New(ErrorFailedToSetWebhook)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -265,11 +293,16 @@ func (p Plex) SetWebhooks(webhooks []string) error {
 
 // MyAccount gets account info (i.e. plex pass, servers, username, etc) from plex tv
 func (p Plex) MyAccount() (UserPlexTV, error) {
+	return p.MyAccountCtx(context.Background())
+}
+
+// MyAccountCtx is like MyAccount but carries ctx for cancellation and deadlines
+func (p Plex) MyAccountCtx(ctx context.Context) (UserPlexTV, error) {
 	endpoint := "/users/account"
 
 	var account UserPlexTV
 
-	resp, err := p.get(plexURL+endpoint, p.Headers)
+	resp, err := p.get(ctx, p.plexTV()+endpoint, p.Headers)
 
 	if err != nil {
 		return account, err
@@ -277,13 +310,11 @@ func (p Plex) MyAccount() (UserPlexTV, error) {
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusUnprocessableEntity {
-		return account, errors.New(ErrorInvalidToken)
-	} else if resp.StatusCode != http.StatusOK {
-		return account, errors.New(resp.Status)
+	if resp.StatusCode != http.StatusOK {
+		return account, newAPIError(resp)
 	}
 
-	if err := xml.NewDecoder(resp.Body).Decode(&account); err != nil {
+	if err := decodeResponse(resp, &account); err != nil {
 		return account, err
 	}
 
@@ -0,0 +1,106 @@
+package plex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func TestPINFlow(t *testing.T) {
+	server := plextest.NewServer(plextest.DefaultFixtures())
+	defer server.Close()
+
+	opts := []Option{WithPlexTVURL(server.URL), WithClientIdentifier("pin-test")}
+
+	pin, err := RequestPIN(headers{}, opts...)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pin.Code == "" || pin.ExpiresAt.IsZero() {
+		t.Fatalf("unexpected pin %+v", pin)
+	}
+
+	if _, err := CheckPIN(pin.ID, "pin-test", opts...); err == nil || err.Error() != ErrorPINNotAuthorized {
+		t.Fatalf("expected %q before the pin is linked, got %v", ErrorPINNotAuthorized, err)
+	}
+
+	if _, err := CheckPIN(pin.ID, "another-client", opts...); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for another client, got %v", err)
+	}
+
+	if err := server.AuthorizePIN(pin.Code); err != nil {
+		t.Fatal(err)
+	}
+
+	linked, err := CheckPIN(pin.ID, "pin-test", opts...)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if linked.AuthToken != server.Token() {
+		t.Errorf("expected auth token %q, got %q", server.Token(), linked.AuthToken)
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	fixtures := plextest.DefaultFixtures()
+	fixtures.Webhooks = []string{"http://example.com/one"}
+
+	conn, server := newTestConn(t, fixtures)
+
+	if err := conn.AddWebhook("http://example.com/two"); err != nil {
+		t.Fatal(err)
+	}
+
+	hooks, err := conn.GetWebhooks()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hooks) != 2 || hooks[0] != "http://example.com/one" || hooks[1] != "http://example.com/two" {
+		t.Errorf("unexpected webhooks %v", hooks)
+	}
+
+	if err := conn.SetWebhooks(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if hooks := server.Fixtures().Webhooks; len(hooks) != 0 {
+		t.Errorf("expected webhooks to be removed, got %v", hooks)
+	}
+
+	fixtures.Account.PlexPass = false
+
+	noPass, _ := newTestConn(t, fixtures)
+
+	if _, err := noPass.GetWebhooks(); !errors.Is(err, ErrPlexPassRequired) {
+		t.Errorf("expected ErrPlexPassRequired, got %v", err)
+	}
+}
+
+func TestMyAccount(t *testing.T) {
+	fixtures := plextest.DefaultFixtures()
+
+	conn, _ := newTestConn(t, fixtures)
+
+	account, err := conn.MyAccount()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if account.Username != fixtures.Account.Username || !account.Subscription.Active {
+		t.Errorf("unexpected account %+v", account)
+	}
+
+	conn.Token = "wrong"
+
+	if _, err := conn.MyAccount(); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
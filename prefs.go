@@ -0,0 +1,193 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Setting is a preference of the server or a library. Type is bool, int, double or text
+type Setting struct {
+	Advanced FlexBool   `json:"advanced" xml:"advanced,attr"`
+	Default  FlexString `json:"default" xml:"default,attr"`
+	Group    string     `json:"group" xml:"group,attr"`
+	Hidden   FlexBool   `json:"hidden" xml:"hidden,attr"`
+	ID       string     `json:"id" xml:"id,attr"`
+	Label    string     `json:"label" xml:"label,attr"`
+	Summary  string     `json:"summary" xml:"summary,attr"`
+	Type     string     `json:"type" xml:"type,attr"`
+	Value    FlexString `json:"value" xml:"value,attr"`
+	// EnumValues lists the accepted values as value:label pairs separated by |, see Enum
+	EnumValues string `json:"enumValues" xml:"enumValues,attr"`
+}
+
+// SettingEnumValue is one of the values a setting accepts
+type SettingEnumValue struct {
+	Value string
+	Label string
+}
+
+// Enum returns the values the setting accepts in order, nil when it accepts any value
+func (s Setting) Enum() []SettingEnumValue {
+	if s.EnumValues == "" {
+		return nil
+	}
+
+	var values []SettingEnumValue
+
+	for _, pair := range strings.Split(s.EnumValues, "|") {
+		value, label := pair, pair
+
+		if ii := strings.Index(pair, ":"); ii >= 0 {
+			value, label = pair[:ii], pair[ii+1:]
+		}
+
+		values = append(values, SettingEnumValue{Value: value, Label: label})
+	}
+
+	return values
+}
+
+// normalize returns value the way the server reports it, so booleans sent as 1 or 0 compare equal to true and false
+func (s Setting) normalize(value string) string {
+	if s.Type != "bool" {
+		return value
+	}
+
+	b, err := parseFlexBool(value)
+
+	if err != nil {
+		return value
+	}
+
+	if b {
+		return "true"
+	}
+
+	return "false"
+}
+
+// Preferences are the settings of a server or a library, see GetServerPreferences and GetLibraryPreferences
+type Preferences []Setting
+
+// Get returns the setting with id
+func (prefs Preferences) Get(id string) (Setting, bool) {
+	for _, setting := range prefs {
+		if setting.ID == id {
+			return setting, true
+		}
+	}
+
+	return Setting{}, false
+}
+
+// Values returns the value of every setting by id, i.e. to save them or to apply them to another server
+func (prefs Preferences) Values() map[string]string {
+	values := make(map[string]string, len(prefs))
+
+	for _, setting := range prefs {
+		values[setting.ID] = setting.normalize(setting.Value.String())
+	}
+
+	return values
+}
+
+// PreferenceChange is a setting whose value differs from the wanted one, see Preferences.Diff
+type PreferenceChange struct {
+	ID string
+	// Current is the value of the server and Wanted the value asked for
+	Current string
+	Wanted  string
+	// Unknown is true when the server has no setting with ID
+	Unknown bool
+}
+
+// Diff returns the settings that need to change for the server to have the wanted
+// values, sorted by id. Settings missing from wanted are left alone
+func (prefs Preferences) Diff(wanted map[string]string) []PreferenceChange {
+	var changes []PreferenceChange
+
+	for id, value := range wanted {
+		setting, ok := prefs.Get(id)
+
+		if !ok {
+			changes = append(changes, PreferenceChange{ID: id, Wanted: value, Unknown: true})
+			continue
+		}
+
+		current := setting.normalize(setting.Value.String())
+
+		if current != setting.normalize(value) {
+			changes = append(changes, PreferenceChange{ID: id, Current: current, Wanted: value})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+
+	return changes
+}
+
+type preferencesResponse struct {
+	MediaContainer struct {
+		Setting []Setting `json:"Setting" xml:"Setting"`
+	} `json:"MediaContainer" xml:"MediaContainer"`
+}
+
+// GetServerPreferences returns the settings of the server, including the hidden and advanced ones
+func (p *Plex) GetServerPreferences() (Preferences, error) {
+	return p.GetServerPreferencesCtx(context.Background())
+}
+
+// GetServerPreferencesCtx is like GetServerPreferences but carries ctx for cancellation and deadlines
+func (p *Plex) GetServerPreferencesCtx(ctx context.Context) (Preferences, error) {
+	return p.getPreferences(ctx, "/:/prefs")
+}
+
+func (p *Plex) getPreferences(ctx context.Context, path string) (Preferences, error) {
+	resp, err := p.get(ctx, p.URL+path, p.Headers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var result preferencesResponse
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return Preferences(result.MediaContainer.Setting), nil
+}
+
+// SetServerPreferences changes settings of the server by id. Settings missing from values are left alone
+func (p *Plex) SetServerPreferences(values map[string]string) error {
+	return p.SetServerPreferencesCtx(context.Background(), values)
+}
+
+// SetServerPreferencesCtx is like SetServerPreferences but carries ctx for cancellation and deadlines
+func (p *Plex) SetServerPreferencesCtx(ctx context.Context, values map[string]string) error {
+	return p.setPreferences(ctx, "/:/prefs", values)
+}
+
+func (p *Plex) setPreferences(ctx context.Context, path string, values map[string]string) error {
+	if len(values) == 0 {
+		return fmt.Errorf(ErrorCommon, "no preferences to set")
+	}
+
+	vals := url.Values{}
+
+	for id, value := range values {
+		vals.Set(id, value)
+	}
+
+	return p.action(ctx, http.MethodPut, path, vals)
+}
@@ -0,0 +1,105 @@
+package plex
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func TestServerPreferences(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	prefs, err := conn.GetServerPreferences()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quality, ok := prefs.Get("TranscoderQuality")
+
+	if !ok || quality.Type != "int" || quality.Group != "transcoder" || quality.Value != "0" {
+		t.Fatalf("unexpected setting %+v", quality)
+	}
+
+	if enum := quality.Enum(); len(enum) != 4 || enum[3].Value != "3" || enum[3].Label != "Make my CPU hurt" {
+		t.Errorf("unexpected enum values %+v", enum)
+	}
+
+	if values := prefs.Values(); values["HardwareAcceleratedCodecs"] != "true" || values["ManualPortMappingPort"] != "32400" {
+		t.Errorf("unexpected values %v", values)
+	}
+
+	wanted := map[string]string{
+		// booleans compare by value, so 1 is the same as true
+		"HardwareAcceleratedCodecs": "1",
+		"TranscoderQuality":         "2",
+		"ManualPortMappingMode":     "true",
+		"ManualPortMappingPort":     "32400",
+		"NoSuchSetting":             "1",
+	}
+
+	changes := prefs.Diff(wanted)
+
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", changes)
+	}
+
+	if changes[0].ID != "ManualPortMappingMode" || changes[0].Current != "false" || changes[0].Wanted != "true" {
+		t.Errorf("unexpected change %+v", changes[0])
+	}
+
+	if changes[1].ID != "NoSuchSetting" || !changes[1].Unknown {
+		t.Errorf("expected an unknown setting, got %+v", changes[1])
+	}
+
+	if err := conn.SetServerPreferences(map[string]string{"TranscoderQuality": "2", "ManualPortMappingMode": "true"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if prefs, err = conn.GetServerPreferences(); err != nil {
+		t.Fatal(err)
+	}
+
+	delete(wanted, "NoSuchSetting")
+
+	if changes := prefs.Diff(wanted); len(changes) != 0 {
+		t.Errorf("expected no changes after setting the preferences, got %+v", changes)
+	}
+
+	var apiErr *APIError
+
+	if err := conn.SetServerPreferences(map[string]string{"TranscoderQuality": "9"}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected a bad request for a value outside the enum, got %v", err)
+	}
+
+	if err := conn.SetServerPreferences(nil); err == nil {
+		t.Error("expected an error without preferences")
+	}
+
+	// the server refuses to delete media unless allowMediaDeletion is enabled
+	if err := conn.SetServerPreferences(map[string]string{"allowMediaDeletion": "0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.DeleteMetadata("100"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("expected a forbidden error, got %v", err)
+	}
+}
+
+func TestServerPreferencesXML(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	conn.Headers.Accept = "application/xml"
+
+	prefs, err := conn.GetServerPreferences()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if values := prefs.Values(); values["HardwareAcceleratedCodecs"] != "true" || values["TranscoderQuality"] != "0" {
+		t.Errorf("unexpected values %v", values)
+	}
+}
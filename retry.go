@@ -0,0 +1,196 @@
+package plex
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy retries idempotent requests (GET, HEAD, OPTIONS, PUT and DELETE) that failed
+// with a connection error, a 429 or a transient 5xx status code. Requests that append,
+// like AddToPlaylist, are sent once even though plex uses PUT for them
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one. Values below 2 disable retries
+	MaxAttempts int
+	// MinBackoff is the base delay before the first retry. It doubles on every retry and is jittered
+	MinBackoff time.Duration
+	// MaxBackoff caps the computed delay between retries. A Retry-After header sent by the server takes precedence
+	MaxBackoff time.Duration
+	// OnRetry is an optional hook called before waiting for the next attempt
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent describes a failed attempt that is about to be retried
+type RetryEvent struct {
+	Request *http.Request
+	// Attempt is the number of the attempt that failed, starting at 1
+	Attempt int
+	// StatusCode is the status code of the failed attempt or 0 on connection errors
+	StatusCode int
+	// Err is the connection error of the failed attempt, if any
+	Err error
+	// Delay is how long the client waits before the next attempt
+	Delay time.Duration
+}
+
+// DefaultRetryPolicy makes up to 3 attempts with a backoff between 500ms and 10s
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  10 * time.Second,
+	}
+}
+
+type nonIdempotentKey struct{}
+
+// nonIdempotent marks the requests sent with ctx as not safe to repeat, for the endpoints
+// that append over PUT, so a request the server applied before failing is not applied twice
+func nonIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, nonIdempotentKey{}, true)
+}
+
+// isIdempotent reports whether sending req twice has the same effect as sending it once
+func isIdempotent(req *http.Request) bool {
+	if marked, _ := req.Context().Value(nonIdempotentKey{}).(bool); marked {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// backoff returns the jittered exponential delay before the retry following attempt
+func (r *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := r.MinBackoff
+
+	for ii := 1; ii < attempt && delay < r.MaxBackoff; ii++ {
+		delay *= 2
+	}
+
+	if r.MaxBackoff > 0 && delay > r.MaxBackoff {
+		delay = r.MaxBackoff
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	// equal jitter: keep half of the delay and randomize the other half
+	half := delay / 2
+
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// retryAfter parses the Retry-After header which is either seconds or an http date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+
+		if delay < 0 {
+			delay = 0
+		}
+
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// wrap returns a round tripper that sends requests through next and retries them according to the policy
+func (r *RetryPolicy) wrap(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if r.MaxAttempts < 2 || !isIdempotent(req) {
+			return next.RoundTrip(req)
+		}
+
+		ctx := req.Context()
+		attemptReq := req
+
+		for attempt := 1; ; attempt++ {
+			resp, err := next.RoundTrip(attemptReq)
+
+			retryable := err != nil || isRetryableStatus(resp.StatusCode)
+
+			if !retryable || attempt >= r.MaxAttempts || ctx.Err() != nil {
+				return resp, err
+			}
+
+			event := RetryEvent{
+				Request: attemptReq,
+				Attempt: attempt,
+				Err:     err,
+				Delay:   r.backoff(attempt),
+			}
+
+			if err == nil {
+				event.StatusCode = resp.StatusCode
+
+				if delay, ok := retryAfter(resp); ok {
+					event.Delay = delay
+				}
+
+				// drain the body so the connection can be reused
+				io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+				resp.Body.Close()
+			}
+
+			if r.OnRetry != nil {
+				r.OnRetry(event)
+			}
+
+			timer := time.NewTimer(event.Delay)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+
+			// the body of the previous attempt has been consumed
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+
+				if err != nil {
+					return nil, err
+				}
+
+				nextReq := *req
+				nextReq.Body = body
+				attemptReq = &nextReq
+			}
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package plex
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if requests < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"MediaContainer":{"size":0}}`)
+	}))
+
+	defer server.Close()
+
+	var events []RetryEvent
+
+	_plex := &Plex{
+		URL: server.URL,
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+			MaxBackoff:  time.Millisecond,
+			OnRetry: func(e RetryEvent) {
+				events = append(events, e)
+			},
+		},
+	}
+
+	if _, err := _plex.GetSessions(); err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+
+	if len(events) != 2 || events[0].StatusCode != http.StatusServiceUnavailable || events[1].Attempt != 2 {
+		t.Errorf("unexpected retry events: %+v", events)
+	}
+
+	// POST is not idempotent and must not be retried
+	requests = 0
+
+	if err := _plex.CreateLibrary(CreateLibraryParams{Name: "a", Location: "/a", LibraryType: "movie", Agent: "a", Scanner: "s"}); err == nil {
+		t.Error("expected an error from CreateLibrary")
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 request for a POST, got %d", requests)
+	}
+
+	// appending to a playlist uses PUT but is not idempotent either
+	requests = 0
+	_plex.MachineIdentifier = "machine-id"
+
+	if err := _plex.AddToPlaylist("1", "100"); err == nil {
+		t.Error("expected an error from AddToPlaylist")
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 request for a playlist append, got %d", requests)
+	}
+
+	// other PUT requests are still retried
+	requests = 0
+
+	if err := _plex.RenamePlaylist("1", "Favorites"); err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 3 {
+		t.Errorf("expected 3 requests for a rename, got %d", requests)
+	}
+}
@@ -0,0 +1,63 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ScanLibrary asks the server to scan the library for new, changed and removed files.
+// The scan runs in the background, see WatchActivities to know when it is done
+func (p *Plex) ScanLibrary(sectionKey string) error {
+	return p.ScanLibraryCtx(context.Background(), sectionKey)
+}
+
+// ScanLibraryCtx is like ScanLibrary but carries ctx for cancellation and deadlines
+func (p *Plex) ScanLibraryCtx(ctx context.Context, sectionKey string) error {
+	return p.refreshLibrary(ctx, http.MethodGet, sectionKey, nil)
+}
+
+// ScanLibraryPath scans a single folder of the library, which is much faster than a
+// full scan after adding a few files. path is the folder as the server sees it
+func (p *Plex) ScanLibraryPath(sectionKey, path string) error {
+	return p.ScanLibraryPathCtx(context.Background(), sectionKey, path)
+}
+
+// ScanLibraryPathCtx is like ScanLibraryPath but carries ctx for cancellation and deadlines
+func (p *Plex) ScanLibraryPathCtx(ctx context.Context, sectionKey, path string) error {
+	if path == "" {
+		return fmt.Errorf(ErrorCommon, "path is required")
+	}
+
+	return p.refreshLibrary(ctx, http.MethodGet, sectionKey, url.Values{"path": []string{path}})
+}
+
+// RefreshLibraryMetadata scans the library and downloads the metadata of every item
+// again, even the ones that did not change
+func (p *Plex) RefreshLibraryMetadata(sectionKey string) error {
+	return p.RefreshLibraryMetadataCtx(context.Background(), sectionKey)
+}
+
+// RefreshLibraryMetadataCtx is like RefreshLibraryMetadata but carries ctx for cancellation and deadlines
+func (p *Plex) RefreshLibraryMetadataCtx(ctx context.Context, sectionKey string) error {
+	return p.refreshLibrary(ctx, http.MethodGet, sectionKey, url.Values{"force": []string{"1"}})
+}
+
+// CancelLibraryScan stops the running scan or metadata refresh of the library
+func (p *Plex) CancelLibraryScan(sectionKey string) error {
+	return p.CancelLibraryScanCtx(context.Background(), sectionKey)
+}
+
+// CancelLibraryScanCtx is like CancelLibraryScan but carries ctx for cancellation and deadlines
+func (p *Plex) CancelLibraryScanCtx(ctx context.Context, sectionKey string) error {
+	return p.refreshLibrary(ctx, http.MethodDelete, sectionKey, nil)
+}
+
+func (p *Plex) refreshLibrary(ctx context.Context, method, sectionKey string, params url.Values) error {
+	if sectionKey == "" {
+		return fmt.Errorf(ErrorCommon, ErrorKeyIsRequired)
+	}
+
+	return p.action(mutating(ctx), method, "/library/sections/"+sectionKey+"/refresh", params)
+}
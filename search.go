@@ -1,16 +1,27 @@
 package plex
 
-import "regexp"
+import (
+	"context"
+	"regexp"
+)
 
-// SearchPlex searches just like Search, but omits the last 4 results which are not relevant
+// SearchPlex searches just like Search, but keeps only the first 4 results which are the most relevant.
+// See SearchHubs for results grouped by type
 func (p *Plex) SearchPlex(title string) (SearchResults, error) {
-	results, err := p.Search(title)
+	return p.SearchPlexCtx(context.Background(), title)
+}
+
+// SearchPlexCtx is like SearchPlex but carries ctx for cancellation and deadlines
+func (p *Plex) SearchPlexCtx(ctx context.Context, title string) (SearchResults, error) {
+	results, err := p.SearchCtx(ctx, title)
 
 	if err != nil {
 		return SearchResults{}, err
 	}
 
-	results.MediaContainer.Metadata = results.MediaContainer.Metadata[:4]
+	if len(results.MediaContainer.Metadata) > 4 {
+		results.MediaContainer.Metadata = results.MediaContainer.Metadata[:4]
+	}
 
 	return results, nil
 }
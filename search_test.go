@@ -1,6 +1,24 @@
 package plex
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func TestSearchPlexFewResults(t *testing.T) {
+	conn, _ := newTestConn(t, plextest.DefaultFixtures())
+
+	results, err := conn.SearchPlex("alien")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count := len(results.MediaContainer.Metadata); count != 2 {
+		t.Errorf("expected 2 results, got %d", count)
+	}
+}
 
 func TestExtractKeyFromRatingKey(t *testing.T) {
 	keys := [][]string{
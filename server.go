@@ -0,0 +1,179 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ServerIdentity is the response of /identity, which the server answers without a token
+type ServerIdentity struct {
+	MachineIdentifier string   `json:"machineIdentifier" xml:"machineIdentifier,attr"`
+	Version           string   `json:"version" xml:"version,attr"`
+	Claimed           FlexBool `json:"claimed" xml:"claimed,attr"`
+}
+
+type identityResponse struct {
+	MediaContainer ServerIdentity `json:"MediaContainer" xml:"MediaContainer"`
+}
+
+// ServerCapabilities describes a server: its version and platform, the plex.tv
+// account it is signed in to and what its transcoder supports
+type ServerCapabilities struct {
+	FriendlyName      string    `json:"friendlyName" xml:"friendlyName,attr"`
+	MachineIdentifier string    `json:"machineIdentifier" xml:"machineIdentifier,attr"`
+	Version           string    `json:"version" xml:"version,attr"`
+	Platform          string    `json:"platform" xml:"platform,attr"`
+	PlatformVersion   string    `json:"platformVersion" xml:"platformVersion,attr"`
+	UpdatedAt         Timestamp `json:"updatedAt" xml:"updatedAt,attr"`
+
+	MyPlex             FlexBool `json:"myPlex" xml:"myPlex,attr"`
+	MyPlexUsername     string   `json:"myPlexUsername" xml:"myPlexUsername,attr"`
+	MyPlexSubscription FlexBool `json:"myPlexSubscription" xml:"myPlexSubscription,attr"`
+	// OwnerFeatures is a comma separated list of the features of the owner's account, see HasFeature
+	OwnerFeatures string `json:"ownerFeatures" xml:"ownerFeatures,attr"`
+
+	AllowSync          FlexBool `json:"allowSync" xml:"allowSync,attr"`
+	AllowSharing       FlexBool `json:"allowSharing" xml:"allowSharing,attr"`
+	AllowMediaDeletion FlexBool `json:"allowMediaDeletion" xml:"allowMediaDeletion,attr"`
+	Multiuser          FlexBool `json:"multiuser" xml:"multiuser,attr"`
+
+	TranscoderAudio               FlexBool `json:"transcoderAudio" xml:"transcoderAudio,attr"`
+	TranscoderVideo               FlexBool `json:"transcoderVideo" xml:"transcoderVideo,attr"`
+	TranscoderPhoto               FlexBool `json:"transcoderPhoto" xml:"transcoderPhoto,attr"`
+	TranscoderSubtitles           FlexBool `json:"transcoderSubtitles" xml:"transcoderSubtitles,attr"`
+	TranscoderLyrics              FlexBool `json:"transcoderLyrics" xml:"transcoderLyrics,attr"`
+	TranscoderActiveVideoSessions FlexInt  `json:"transcoderActiveVideoSessions" xml:"transcoderActiveVideoSessions,attr"`
+	// TranscoderVideoBitrates, TranscoderVideoQualities and TranscoderVideoResolutions
+	// are comma separated lists of the same length, one entry per quality
+	TranscoderVideoBitrates    string `json:"transcoderVideoBitrates" xml:"transcoderVideoBitrates,attr"`
+	TranscoderVideoQualities   string `json:"transcoderVideoQualities" xml:"transcoderVideoQualities,attr"`
+	TranscoderVideoResolutions string `json:"transcoderVideoResolutions" xml:"transcoderVideoResolutions,attr"`
+}
+
+type capabilitiesResponse struct {
+	MediaContainer ServerCapabilities `json:"MediaContainer" xml:"MediaContainer"`
+}
+
+// PlexPass reports whether the account the server is signed in to has an active Plex Pass
+func (c ServerCapabilities) PlexPass() bool {
+	return c.MyPlexSubscription.Bool()
+}
+
+// HasFeature reports whether feature is one of OwnerFeatures, i.e. "webhooks"
+func (c ServerCapabilities) HasFeature(feature string) bool {
+	for _, f := range strings.Split(c.OwnerFeatures, ",") {
+		if strings.TrimSpace(f) == feature {
+			return true
+		}
+	}
+
+	return false
+}
+
+// capabilityCache keeps the capabilities of the server at url. Plex is copied by value
+// in places, so the cache lives behind a pointer instead of holding a lock in Plex
+type capabilityCache struct {
+	mu           sync.Mutex
+	url          string
+	capabilities *ServerCapabilities
+}
+
+// GetServerIdentity returns the machine identifier and version of the server
+func (p *Plex) GetServerIdentity() (ServerIdentity, error) {
+	return p.GetServerIdentityCtx(context.Background())
+}
+
+// GetServerIdentityCtx is like GetServerIdentity but carries ctx for cancellation and deadlines
+func (p *Plex) GetServerIdentityCtx(ctx context.Context) (ServerIdentity, error) {
+	resp, err := p.get(ctx, p.URL+"/identity", p.Headers)
+
+	if err != nil {
+		return ServerIdentity{}, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ServerIdentity{}, newAPIError(resp)
+	}
+
+	var result identityResponse
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return ServerIdentity{}, err
+	}
+
+	return result.MediaContainer, nil
+}
+
+// GetServerCapabilities returns the version, platform, Plex Pass status and transcoder
+// capabilities of the server. Each call asks the server again and refreshes the
+// capabilities the Plex Pass features check
+func (p *Plex) GetServerCapabilities() (ServerCapabilities, error) {
+	return p.GetServerCapabilitiesCtx(context.Background())
+}
+
+// GetServerCapabilitiesCtx is like GetServerCapabilities but carries ctx for cancellation and deadlines
+func (p *Plex) GetServerCapabilitiesCtx(ctx context.Context) (ServerCapabilities, error) {
+	resp, err := p.get(ctx, p.URL+"/", p.Headers)
+
+	if err != nil {
+		return ServerCapabilities{}, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ServerCapabilities{}, newAPIError(resp)
+	}
+
+	var result capabilitiesResponse
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return ServerCapabilities{}, err
+	}
+
+	if p.capabilities != nil {
+		p.capabilities.mu.Lock()
+		p.capabilities.url = p.URL
+		p.capabilities.capabilities = &result.MediaContainer
+		p.capabilities.mu.Unlock()
+	}
+
+	return result.MediaContainer, nil
+}
+
+// cachedCapabilities returns the capabilities of the server, asking it only once per url
+func (p *Plex) cachedCapabilities(ctx context.Context) (ServerCapabilities, error) {
+	if p.capabilities != nil {
+		p.capabilities.mu.Lock()
+		cached := p.capabilities.capabilities
+		cachedURL := p.capabilities.url
+		p.capabilities.mu.Unlock()
+
+		if cached != nil && cachedURL == p.URL {
+			return *cached, nil
+		}
+	}
+
+	return p.GetServerCapabilitiesCtx(ctx)
+}
+
+// requirePlexPass fails with ErrPlexPassRequired before feature is requested
+// when the server is not signed in to an account with a Plex Pass
+func (p *Plex) requirePlexPass(ctx context.Context, feature string) error {
+	capabilities, err := p.cachedCapabilities(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if !capabilities.PlexPass() {
+		return fmt.Errorf("plex: %s: %w", feature, ErrPlexPassRequired)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package plex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jrudio/go-plex-client/plextest"
+)
+
+func TestServerIdentityAndCapabilities(t *testing.T) {
+	fixtures := plextest.DefaultFixtures()
+
+	conn, _ := newTestConn(t, fixtures)
+
+	identity, err := conn.GetServerIdentity()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if identity.MachineIdentifier != fixtures.MachineIdentifier || identity.Version != fixtures.Version || !identity.Claimed.Bool() {
+		t.Errorf("unexpected identity %+v", identity)
+	}
+
+	capabilities, err := conn.GetServerCapabilities()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if capabilities.Version != fixtures.Version || capabilities.Platform != "Linux" || capabilities.MachineIdentifier != fixtures.MachineIdentifier {
+		t.Errorf("unexpected capabilities %+v", capabilities)
+	}
+
+	if !capabilities.PlexPass() || !capabilities.HasFeature("session_kick") || capabilities.HasFeature("session") {
+		t.Errorf("expected a plex pass with session_kick, got %+v", capabilities)
+	}
+
+	if !capabilities.TranscoderVideo.Bool() || !capabilities.AllowMediaDeletion.Bool() || capabilities.UpdatedAt.Unix() != 1600000000 {
+		t.Errorf("unexpected capabilities %+v", capabilities)
+	}
+
+	conn.Headers.Accept = "application/xml"
+
+	capabilities, err = conn.GetServerCapabilities()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if capabilities.FriendlyName != fixtures.FriendlyName || !capabilities.PlexPass() || capabilities.TranscoderVideoBitrates == "" {
+		t.Errorf("unexpected xml capabilities %+v", capabilities)
+	}
+}
+
+func TestPlexPassFailsFast(t *testing.T) {
+	fixtures := plextest.DefaultFixtures()
+	fixtures.Account.PlexPass = false
+
+	conn, server := newTestConn(t, fixtures)
+
+	if err := conn.TerminateSession(fixtures.Sessions[0].ID, ""); !errors.Is(err, ErrPlexPassRequired) {
+		t.Errorf("expected ErrPlexPassRequired, got %v", err)
+	}
+
+	if _, err := conn.AddLabelToMedia("1", "1", "100", "kids", ""); !errors.Is(err, ErrPlexPassRequired) {
+		t.Errorf("expected ErrPlexPassRequired, got %v", err)
+	}
+
+	if err := conn.EditMetadata("100", NewMetadataEdits().AddTags(FieldLabel, "kids")); !errors.Is(err, ErrPlexPassRequired) {
+		t.Errorf("expected ErrPlexPassRequired when editing labels, got %v", err)
+	}
+
+	// the capabilities are fetched once and neither feature reached the server
+	requests := server.Requests()
+
+	if len(requests) != 1 || requests[0].Path != "/" {
+		t.Errorf("expected a single capabilities request, got %+v", requests)
+	}
+
+	if len(server.Fixtures().Sessions) != len(fixtures.Sessions) {
+		t.Error("expected the session to keep playing")
+	}
+
+	// edits without labels need no Plex Pass
+	if err := conn.EditMetadata("100", NewMetadataEdits().Set(FieldTitle, "Alien: Director's Cut")); err != nil {
+		t.Errorf("expected a title edit to go through, got %v", err)
+	}
+}
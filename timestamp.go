@@ -0,0 +1,176 @@
+package plex
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type timestampFormat uint8
+
+const (
+	// a json number or an xml attribute of unix seconds
+	formatEpoch timestampFormat = iota
+	// unix seconds sent as a json string
+	formatEpochString
+	// an RFC 3339 date, as sent by the plex.tv v2 api
+	formatRFC3339
+)
+
+// Timestamp is a point in time sent by plex either as unix seconds or as an RFC 3339 string.
+// It remembers which one it was decoded from and encodes back to the same format.
+// A zero or missing value decodes to the zero time, so IsZero reports whether it was set
+type Timestamp struct {
+	time.Time
+
+	format timestampFormat
+}
+
+// NewTimestamp returns a Timestamp that encodes as unix seconds
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// Unix returns the unix seconds of t, or 0 when t is the zero time
+func (t Timestamp) Unix() int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Time.Unix()
+}
+
+func (t *Timestamp) parse(value string, quoted bool) error {
+	value = strings.TrimSpace(value)
+
+	if value == "" || value == "null" {
+		*t = Timestamp{}
+
+		if quoted {
+			t.format = formatRFC3339
+		}
+
+		return nil
+	}
+
+	if seconds, err := parseFlexInt(value); err == nil {
+		*t = Timestamp{format: formatEpoch}
+
+		if quoted {
+			t.format = formatEpochString
+		}
+
+		if seconds != 0 {
+			t.Time = time.Unix(int64(seconds), 0)
+		}
+
+		return nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+
+	if err != nil {
+		return fmt.Errorf("plex: invalid timestamp %q", value)
+	}
+
+	*t = Timestamp{Time: parsed, format: formatRFC3339}
+
+	return nil
+}
+
+func (t Timestamp) text() string {
+	if t.format == formatRFC3339 {
+		if t.IsZero() {
+			return ""
+		}
+
+		return t.Time.Format(time.RFC3339)
+	}
+
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// MarshalJSON implements json.Marshaler
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	switch {
+	case t.format == formatRFC3339 && t.IsZero():
+		return []byte("null"), nil
+	case t.format == formatEpoch:
+		return []byte(t.text()), nil
+	}
+
+	return []byte(strconv.Quote(t.text())), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	value, ok, err := flexScalar(data)
+
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		*t = Timestamp{}
+		return nil
+	}
+
+	return t.parse(value, strings.HasPrefix(strings.TrimSpace(string(data)), `"`))
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr
+func (t Timestamp) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: t.text()}, nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr
+func (t *Timestamp) UnmarshalXMLAttr(attr xml.Attr) error {
+	return t.parse(attr.Value, false)
+}
+
+// Millis is a duration or an offset sent by plex in milliseconds,
+// such as the duration of a media or the view offset of a session
+type Millis int64
+
+// NewMillis converts d to milliseconds
+func NewMillis(d time.Duration) Millis {
+	return Millis(d / time.Millisecond)
+}
+
+// Duration returns m as a time.Duration
+func (m Millis) Duration() time.Duration {
+	return time.Duration(m) * time.Millisecond
+}
+
+// String formats m like a time.Duration, i.e. "1h32m10s"
+func (m Millis) String() string {
+	return m.Duration().String()
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (m *Millis) UnmarshalJSON(data []byte) error {
+	var value FlexInt
+
+	if err := value.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	*m = Millis(value)
+
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr
+func (m *Millis) UnmarshalXMLAttr(attr xml.Attr) error {
+	var value FlexInt
+
+	if err := value.UnmarshalXMLAttr(attr); err != nil {
+		return err
+	}
+
+	*m = Millis(value)
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package plex
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimestampJSON(t *testing.T) {
+	tests := []struct {
+		body string
+		want time.Time
+	}{
+		{`1600000000`, time.Unix(1600000000, 0)},
+		{`"1600000000"`, time.Unix(1600000000, 0)},
+		{`"2020-09-13T12:26:40Z"`, time.Unix(1600000000, 0)},
+		{`0`, time.Time{}},
+		{`null`, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		var ts Timestamp
+
+		if err := json.Unmarshal([]byte(tt.body), &ts); err != nil {
+			t.Errorf("%s: %v", tt.body, err)
+			continue
+		}
+
+		if !ts.Time.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.body, ts.Time, tt.want)
+		}
+
+		if tt.body == "null" {
+			continue
+		}
+
+		encoded, err := json.Marshal(ts)
+
+		if err != nil {
+			t.Errorf("%s: %v", tt.body, err)
+			continue
+		}
+
+		if string(encoded) != tt.body {
+			t.Errorf("round trip of %s gave %s", tt.body, encoded)
+		}
+	}
+
+	var ts Timestamp
+
+	if err := json.Unmarshal([]byte(`"yesterday"`), &ts); err == nil {
+		t.Error("expected an error for an invalid timestamp")
+	}
+}
+
+func TestTimestampXML(t *testing.T) {
+	type Video struct {
+		AddedAt  Timestamp `xml:"addedAt,attr"`
+		Duration Millis    `xml:"duration,attr"`
+	}
+
+	var item Video
+
+	if err := xml.Unmarshal([]byte(`<Video addedAt="1600000000" duration="5400000" />`), &item); err != nil {
+		t.Fatal(err)
+	}
+
+	if item.AddedAt.Unix() != 1600000000 {
+		t.Errorf("unexpected addedAt %v", item.AddedAt)
+	}
+
+	if item.Duration.Duration() != 90*time.Minute {
+		t.Errorf("unexpected duration %v", item.Duration)
+	}
+
+	encoded, err := xml.Marshal(item)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `addedAt="1600000000"`; !strings.Contains(string(encoded), want) {
+		t.Errorf("expected %s in %s", want, encoded)
+	}
+}
+
+func TestMillis(t *testing.T) {
+	var m Millis
+
+	if err := json.Unmarshal([]byte(`"1500"`), &m); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Duration() != 1500*time.Millisecond {
+		t.Errorf("unexpected duration %v", m.Duration())
+	}
+
+	if NewMillis(2*time.Second) != 2000 {
+		t.Errorf("unexpected millis %d", NewMillis(2*time.Second))
+	}
+}
@@ -2,196 +2,109 @@ package plex
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"net/http"
-	"time"
+	"net/url"
 )
 
-// func (p *Plex) options(query string) (*http.Response, error) {
-// 	client := p.HTTPClient
+// RoundTripperFunc is an adapter to allow the use of ordinary functions as an http.RoundTripper
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
 
-// 	req, reqErr := http.NewRequest("OPTIONS", query, nil)
-
-// 	if reqErr != nil {
-// 		return &http.Response{}, reqErr
-// 	}
-
-// 	resp, err := client.Do(req)
-
-// 	if err != nil {
-// 		return &http.Response{}, err
-// 	}
-
-// 	return resp, nil
-// }
-
-func (p *Plex) grab(query string, h headers) (*http.Response, error) {
-	client := p.DownloadClient
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
 
-	req, reqErr := http.NewRequest("GET", query, nil)
+// Middleware intercepts every request sent by a Plex instance. It receives the next
+// http.RoundTripper in the chain and returns one that wraps it. A middleware can modify
+// the request, observe the response or answer the request itself without calling next
+type Middleware func(next http.RoundTripper) http.RoundTripper
 
-	if reqErr != nil {
-		return &http.Response{}, reqErr
-	}
+// Use appends middleware to the request pipeline. Middleware added first is the outermost,
+// so it sees the request first and the response last
+func (p *Plex) Use(middleware ...Middleware) {
+	p.Middleware = append(p.Middleware, middleware...)
+}
 
-	req.Header.Add("Accept", h.Accept)
-	req.Header.Add("X-Plex-Platform", h.Platform)
-	req.Header.Add("X-Plex-Platform-Version", h.PlatformVersion)
-	req.Header.Add("X-Plex-Provides", h.Provides)
-	req.Header.Add("X-Plex-Client-Identifier", p.ClientIdentifier)
-	req.Header.Add("X-Plex-Product", h.Product)
-	req.Header.Add("X-Plex-Version", h.Version)
-	req.Header.Add("X-Plex-Device", h.Device)
-	// req.Header.Add("X-Plex-Container-Size", h.ContainerSize)
-	// req.Header.Add("X-Plex-Container-Start", h.ContainerStart)
-	req.Header.Add("X-Plex-Token", p.Token)
+// do is the single request executor used by every method. It builds the request,
+// sets the X-Plex-* headers, runs it through the middleware chain and sends it with client
+func (p *Plex) do(ctx context.Context, client *http.Client, method, query string, body []byte, h headers) (*http.Response, error) {
+	var reqBody io.Reader
 
-	// optional headers
-	if h.TargetClientIdentifier != "" {
-		req.Header.Add("X-Plex-Target-Identifier", h.TargetClientIdentifier)
+	if method == http.MethodPost || method == http.MethodPut {
+		reqBody = bytes.NewReader(body)
 	}
 
-	resp, err := client.Do(req)
+	req, err := http.NewRequestWithContext(ctx, method, query, reqBody)
 
 	if err != nil {
 		return &http.Response{}, err
 	}
 
-	return resp, nil
-}
+	clientIdentifier := p.ClientIdentifier
 
-func (p *Plex) get(query string, h headers) (*http.Response, error) {
-	client := p.HTTPClient
+	if clientIdentifier == "" {
+		clientIdentifier = h.ClientIdentifier
+	}
 
-	req, reqErr := http.NewRequest("GET", query, nil)
+	token := p.Token
 
-	if reqErr != nil {
-		return &http.Response{}, reqErr
+	if h.Token != "" {
+		token = h.Token
 	}
 
 	req.Header.Add("Accept", h.Accept)
 	req.Header.Add("X-Plex-Platform", h.Platform)
 	req.Header.Add("X-Plex-Platform-Version", h.PlatformVersion)
 	req.Header.Add("X-Plex-Provides", h.Provides)
-	req.Header.Add("X-Plex-Client-Identifier", p.ClientIdentifier)
+	req.Header.Add("X-Plex-Client-Identifier", clientIdentifier)
 	req.Header.Add("X-Plex-Product", h.Product)
 	req.Header.Add("X-Plex-Version", h.Version)
 	req.Header.Add("X-Plex-Device", h.Device)
-	// req.Header.Add("X-Plex-Container-Size", h.ContainerSize)
-	// req.Header.Add("X-Plex-Container-Start", h.ContainerStart)
-	req.Header.Add("X-Plex-Token", p.Token)
 
 	// optional headers
-	if h.TargetClientIdentifier != "" {
-		req.Header.Add("X-Plex-Target-Identifier", h.TargetClientIdentifier)
-	}
-
-	resp, err := client.Do(req)
-
-	if err != nil {
-		return &http.Response{}, err
-	}
-
-	return resp, nil
-}
-
-func get(query string, h headers) (*http.Response, error) {
-	client := http.Client{
-		Timeout: 3 * time.Second,
+	if h.DeviceName != "" {
+		req.Header.Add("X-Plex-Device-Name", h.DeviceName)
 	}
 
-	req, err := http.NewRequest("GET", query, nil)
-
-	if err != nil {
-		return &http.Response{}, err
+	if h.ContainerStart != "" {
+		req.Header.Add("X-Plex-Container-Start", h.ContainerStart)
 	}
 
-	req.Header.Add("Accept", h.Accept)
-	req.Header.Add("X-Plex-Platform", h.Platform)
-	req.Header.Add("X-Plex-Platform-Version", h.PlatformVersion)
-	req.Header.Add("X-Plex-Provides", h.Provides)
-	req.Header.Add("X-Plex-Client-Identifier", h.ClientIdentifier)
-	req.Header.Add("X-Plex-Product", h.Product)
-	req.Header.Add("X-Plex-Version", h.Version)
-	req.Header.Add("X-Plex-Device", h.Device)
-	// req.Header.Add("X-Plex-Container-Size", h.ContainerSize)
-	// req.Header.Add("X-Plex-Container-Start", h.ContainerStart)
-	if h.Token != "" {
-		req.Header.Add("X-Plex-Token", h.Token)
+	if h.ContainerSize != "" {
+		req.Header.Add("X-Plex-Container-Size", h.ContainerSize)
 	}
 
-	resp, err := client.Do(req)
-
-	if err != nil {
-		return &http.Response{}, err
+	if token != "" {
+		req.Header.Add("X-Plex-Token", token)
 	}
 
-	return resp, nil
-}
-
-func (p *Plex) delete(query string, h headers) (*http.Response, error) {
-	client := p.HTTPClient
-
-	req, reqErr := http.NewRequest("DELETE", query, nil)
-
-	if reqErr != nil {
-		return &http.Response{}, reqErr
+	if reqBody != nil {
+		req.Header.Add("Content-Type", h.ContentType)
 	}
 
-	req.Header.Add("Accept", h.Accept)
-	req.Header.Add("X-Plex-Platform", h.Platform)
-	req.Header.Add("X-Plex-Platform-Version", h.PlatformVersion)
-	req.Header.Add("X-Plex-Provides", h.Provides)
-	req.Header.Add("X-Plex-Client-Identifier", p.ClientIdentifier)
-	req.Header.Add("X-Plex-Product", h.Product)
-	req.Header.Add("X-Plex-Version", h.Version)
-	req.Header.Add("X-Plex-Device", h.Device)
-	// req.Header.Add("X-Plex-Container-Size", h.ContainerSize)
-	// req.Header.Add("X-Plex-Container-Start", h.ContainerStart)
-	req.Header.Add("X-Plex-Token", p.Token)
-
-	// optional headers
 	if h.TargetClientIdentifier != "" {
 		req.Header.Add("X-Plex-Target-Identifier", h.TargetClientIdentifier)
 	}
 
-	resp, err := client.Do(req)
+	var transport http.RoundTripper = RoundTripperFunc(client.Do)
 
-	if err != nil {
-		return &http.Response{}, err
+	// retries happen inside the middleware chain so middleware sees a single request
+	if p.RetryPolicy != nil {
+		transport = p.RetryPolicy.wrap(transport)
 	}
 
-	return resp, nil
-}
-
-func (p *Plex) post(query string, body []byte, h headers) (*http.Response, error) {
-	client := p.HTTPClient
-
-	req, err := http.NewRequest("POST", query, bytes.NewBuffer(body))
-
-	if err != nil {
-		return &http.Response{}, err
+	// dry run sits outside retries so a request that is not sent is not retried either
+	if p.DryRun != nil {
+		transport = dryRun(p.DryRun, transport)
 	}
 
-	// req.Header.Set("Content-Type", "application/json")
-	req.Header.Add("Accept", h.Accept)
-	req.Header.Add("X-Plex-Platform", h.Platform)
-	req.Header.Add("X-Plex-Platform-Version", h.PlatformVersion)
-	req.Header.Add("X-Plex-Provides", h.Provides)
-	req.Header.Add("X-Plex-Client-Identifier", p.ClientIdentifier)
-	req.Header.Add("X-Plex-Product", h.Product)
-	req.Header.Add("X-Plex-Version", h.Version)
-	req.Header.Add("X-Plex-Device", h.Device)
-	// req.Header.Add("X-Plex-Container-Size", h.ContainerSize)
-	// req.Header.Add("X-Plex-Container-Start", h.ContainerStart)
-	req.Header.Add("X-Plex-Token", p.Token)
-	req.Header.Add("Content-Type", h.ContentType)
-
-	// optional headers
-	if h.TargetClientIdentifier != "" {
-		req.Header.Add("X-Plex-Target-Identifier", h.TargetClientIdentifier)
+	for ii := len(p.Middleware) - 1; ii >= 0; ii-- {
+		transport = p.Middleware[ii](transport)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := transport.RoundTrip(req)
 
 	if err != nil {
 		return &http.Response{}, err
@@ -200,74 +113,46 @@ func (p *Plex) post(query string, body []byte, h headers) (*http.Response, error
 	return resp, nil
 }
 
-// post sends a POST request and is the same as plex.post while omitting the plex token header
-func post(query string, body []byte, h headers) (*http.Response, error) {
-	client := http.Client{
-		Timeout: 3 * time.Second,
-	}
-
-	req, err := http.NewRequest("POST", query, bytes.NewBuffer(body))
-
-	if err != nil {
-		return &http.Response{}, err
-	}
-
-	req.Header.Add("Accept", h.Accept)
-	req.Header.Add("X-Plex-Platform", h.Platform)
-	req.Header.Add("X-Plex-Platform-Version", h.PlatformVersion)
-	req.Header.Add("X-Plex-Provides", h.Provides)
-	req.Header.Add("X-Plex-Client-Identifier", h.ClientIdentifier)
-	req.Header.Add("X-Plex-Product", h.Product)
-	req.Header.Add("X-Plex-Version", h.Version)
-	req.Header.Add("X-Plex-Device", h.Device)
-	// req.Header.Add("X-Plex-Container-Size", h.ContainerSize)
-	// req.Header.Add("X-Plex-Container-Start", h.ContainerStart)
-	if h.Token != "" {
-		req.Header.Add("X-Plex-Token", h.Token)
-	}
-	req.Header.Add("Content-Type", h.ContentType)
+// grab is a GET request sent with the download client, which has no timeout
+func (p *Plex) grab(ctx context.Context, query string, h headers) (*http.Response, error) {
+	return p.do(ctx, &p.DownloadClient, http.MethodGet, query, nil, h)
+}
 
-	resp, err := client.Do(req)
+func (p *Plex) get(ctx context.Context, query string, h headers) (*http.Response, error) {
+	return p.do(ctx, &p.HTTPClient, http.MethodGet, query, nil, h)
+}
 
-	if err != nil {
-		return &http.Response{}, err
-	}
+func (p *Plex) delete(ctx context.Context, query string, h headers) (*http.Response, error) {
+	return p.do(ctx, &p.HTTPClient, http.MethodDelete, query, nil, h)
+}
 
-	return resp, nil
+func (p *Plex) post(ctx context.Context, query string, body []byte, h headers) (*http.Response, error) {
+	return p.do(ctx, &p.HTTPClient, http.MethodPost, query, body, h)
 }
 
-func (p *Plex) put(query string, body []byte, h headers) (*http.Response, error) {
-	client := p.HTTPClient
+func (p *Plex) put(ctx context.Context, query string, body []byte, h headers) (*http.Response, error) {
+	return p.do(ctx, &p.HTTPClient, http.MethodPut, query, body, h)
+}
 
-	req, reqErr := http.NewRequest("PUT", query, bytes.NewBuffer(body))
+// action sends a request to the server whose reply carries nothing but its status code
+func (p *Plex) action(ctx context.Context, method, path string, params url.Values) error {
+	query := p.URL + path
 
-	if reqErr != nil {
-		return &http.Response{}, reqErr
+	if len(params) > 0 {
+		query += "?" + params.Encode()
 	}
 
-	req.Header.Set("Content-Type", h.ContentType)
-	req.Header.Add("Accept", h.Accept)
-	req.Header.Add("X-Plex-Platform", h.Platform)
-	req.Header.Add("X-Plex-Platform-Version", h.PlatformVersion)
-	req.Header.Add("X-Plex-Provides", h.Provides)
-	req.Header.Add("X-Plex-Client-Identifier", p.ClientIdentifier)
-	req.Header.Add("X-Plex-Product", h.Product)
-	req.Header.Add("X-Plex-Version", h.Version)
-	req.Header.Add("X-Plex-Device", h.Device)
-	// req.Header.Add("X-Plex-Container-Size", h.ContainerSize)
-	// req.Header.Add("X-Plex-Container-Start", h.ContainerStart)
-	req.Header.Add("X-Plex-Token", p.Token)
+	resp, err := p.do(ctx, &p.HTTPClient, method, query, nil, p.Headers)
 
-	// optional headers
-	if h.TargetClientIdentifier != "" {
-		req.Header.Add("X-Plex-Target-Identifier", h.TargetClientIdentifier)
+	if err != nil {
+		return err
 	}
 
-	resp, err := client.Do(req)
+	defer resp.Body.Close()
 
-	if err != nil {
-		return &http.Response{}, err
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
 	}
 
-	return resp, nil
+	return nil
 }
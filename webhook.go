@@ -9,49 +9,49 @@ import (
 
 // Webhook contains a webhooks information
 type Webhook struct {
-	Event   string `json:"event"`
-	User    bool   `json:"user"`
-	Owner   bool   `json:"owner"`
+	Event   string   `json:"event"`
+	User    FlexBool `json:"user"`
+	Owner   FlexBool `json:"owner"`
 	Account struct {
-		ID    int    `json:"id"`
-		Thumb string `json:"thumb"`
-		Title string `json:"title"`
+		ID    FlexInt `json:"id"`
+		Thumb string  `json:"thumb"`
+		Title string  `json:"title"`
 	} `json:"Account"`
 	Server struct {
 		Title string `json:"title"`
 		UUID  string `json:"uuid"`
 	} `json:"Server"`
 	Player struct {
-		Local         bool   `json:"local"`
-		PublicAddress string `json:"PublicAddress"`
-		Title         string `json:"title"`
-		UUID          string `json:"uuid"`
+		Local         FlexBool `json:"local"`
+		PublicAddress string   `json:"PublicAddress"`
+		Title         string   `json:"title"`
+		UUID          string   `json:"uuid"`
 	} `json:"Player"`
 	Metadata struct {
-		LibrarySectionType   string `json:"librarySectionType"`
-		RatingKey            string `json:"ratingKey"`
-		Key                  string `json:"key"`
-		ParentRatingKey      string `json:"parentRatingKey"`
-		GrandparentRatingKey string `json:"grandparentRatingKey"`
-		GUID                 string `json:"guid"`
-		LibrarySectionID     int    `json:"librarySectionID"`
-		MediaType            string `json:"type"`
-		Title                string `json:"title"`
-		GrandparentKey       string `json:"grandparentKey"`
-		ParentKey            string `json:"parentKey"`
-		GrandparentTitle     string `json:"grandparentTitle"`
-		ParentTitle          string `json:"parentTitle"`
-		Summary              string `json:"summary"`
-		Index                int    `json:"index"`
-		ParentIndex          int    `json:"parentIndex"`
-		RatingCount          int    `json:"ratingCount"`
-		Thumb                string `json:"thumb"`
-		Art                  string `json:"art"`
-		ParentThumb          string `json:"parentThumb"`
-		GrandparentThumb     string `json:"grandparentThumb"`
-		GrandparentArt       string `json:"grandparentArt"`
-		AddedAt              int    `json:"addedAt"`
-		UpdatedAt            int    `json:"updatedAt"`
+		LibrarySectionType   string    `json:"librarySectionType"`
+		RatingKey            string    `json:"ratingKey"`
+		Key                  string    `json:"key"`
+		ParentRatingKey      string    `json:"parentRatingKey"`
+		GrandparentRatingKey string    `json:"grandparentRatingKey"`
+		GUID                 string    `json:"guid"`
+		LibrarySectionID     FlexInt   `json:"librarySectionID"`
+		MediaType            string    `json:"type"`
+		Title                string    `json:"title"`
+		GrandparentKey       string    `json:"grandparentKey"`
+		ParentKey            string    `json:"parentKey"`
+		GrandparentTitle     string    `json:"grandparentTitle"`
+		ParentTitle          string    `json:"parentTitle"`
+		Summary              string    `json:"summary"`
+		Index                FlexInt   `json:"index"`
+		ParentIndex          FlexInt   `json:"parentIndex"`
+		RatingCount          FlexInt   `json:"ratingCount"`
+		Thumb                string    `json:"thumb"`
+		Art                  string    `json:"art"`
+		ParentThumb          string    `json:"parentThumb"`
+		GrandparentThumb     string    `json:"grandparentThumb"`
+		GrandparentArt       string    `json:"grandparentArt"`
+		AddedAt              Timestamp `json:"addedAt"`
+		UpdatedAt            Timestamp `json:"updatedAt"`
 	} `json:"Metadata"`
 }
 
@@ -1,6 +1,7 @@
 package plex
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -13,29 +14,21 @@ import (
 
 // TimelineEntry ...
 type TimelineEntry struct {
-	Identifier    string `json:"identifier"`
-	ItemID        int64  `json:"itemID"`
-	MetadataState string `json:"metadataState"`
-	SectionID     int64  `json:"sectionID"`
-	State         int64  `json:"state"`
-	Title         string `json:"title"`
-	Type          int64  `json:"type"`
-	UpdatedAt     int64  `json:"updatedAt"`
+	Identifier    string    `json:"identifier"`
+	ItemID        FlexInt   `json:"itemID"`
+	MetadataState string    `json:"metadataState"`
+	SectionID     FlexInt   `json:"sectionID"`
+	State         FlexInt   `json:"state"`
+	Title         string    `json:"title"`
+	Type          FlexInt   `json:"type"`
+	UpdatedAt     Timestamp `json:"updatedAt"`
 }
 
 // ActivityNotification ...
 type ActivityNotification struct {
-	Activity struct {
-		Cancellable bool   `json:"cancellable"`
-		Progress    int64  `json:"progress"`
-		Subtitle    string `json:"subtitle"`
-		Title       string `json:"title"`
-		Type        string `json:"type"`
-		UserID      int64  `json:"userID"`
-		UUID        string `json:"uuid"`
-	} `json:"Activity"`
-	Event string `json:"event"`
-	UUID  string `json:"uuid"`
+	Activity Activity `json:"Activity"`
+	Event    string   `json:"event"`
+	UUID     string   `json:"uuid"`
 }
 
 // StatusNotification ...
@@ -47,61 +40,48 @@ type StatusNotification struct {
 
 // PlaySessionStateNotification ...
 type PlaySessionStateNotification struct {
-	GUID             string `json:"guid"`
-	Key              string `json:"key"`
-	PlayQueueItemID  int64  `json:"playQueueItemID"`
-	RatingKey        string `json:"ratingKey"`
-	SessionKey       string `json:"sessionKey"`
-	State            string `json:"state"`
-	URL              string `json:"url"`
-	ViewOffset       int64  `json:"viewOffset"`
-	TranscodeSession string `json:"transcodeSession"`
+	GUID             string  `json:"guid"`
+	Key              string  `json:"key"`
+	PlayQueueItemID  FlexInt `json:"playQueueItemID"`
+	RatingKey        string  `json:"ratingKey"`
+	SessionKey       string  `json:"sessionKey"`
+	State            string  `json:"state"`
+	URL              string  `json:"url"`
+	ViewOffset       Millis  `json:"viewOffset"`
+	TranscodeSession string  `json:"transcodeSession"`
 }
 
 // ReachabilityNotification ...
 type ReachabilityNotification struct {
-	Reachability bool `json:"reachability"`
+	Reachability FlexBool `json:"reachability"`
 }
 
 // BackgroundProcessingQueueEventNotification ...
 type BackgroundProcessingQueueEventNotification struct {
-	Event   string `json:"event"`
-	QueueID int64  `json:"queueID"`
+	Event   string  `json:"event"`
+	QueueID FlexInt `json:"queueID"`
 }
 
 // TranscodeSession ...
 type TranscodeSession struct {
-	AudioChannels        int64   `json:"audioChannels"`
-	AudioCodec           string  `json:"audioCodec"`
-	AudioDecision        string  `json:"audioDecision"`
-	Complete             bool    `json:"complete"`
-	Container            string  `json:"container"`
-	Context              string  `json:"context"`
-	Duration             int64   `json:"duration"`
-	Key                  string  `json:"key"`
-	Progress             float64 `json:"progress"`
-	Protocol             string  `json:"protocol"`
-	Remaining            int64   `json:"remaining"`
-	SourceAudioCodec     string  `json:"sourceAudioCodec"`
-	SourceVideoCodec     string  `json:"sourceVideoCodec"`
-	Speed                float64 `json:"speed"`
-	Throttled            bool    `json:"throttled"`
-	TranscodeHwRequested bool    `json:"transcodeHwRequested"`
-	VideoCodec           string  `json:"videoCodec"`
-	VideoDecision        string  `json:"videoDecision"`
-}
-
-// Setting ...
-type Setting struct {
-	Advanced bool   `json:"advanced"`
-	Default  string `json:"default"`
-	Group    string `json:"group"`
-	Hidden   bool   `json:"hidden"`
-	ID       string `json:"id"`
-	Label    string `json:"label"`
-	Summary  string `json:"summary"`
-	Type     string `json:"type"`
-	Value    int64  `json:"value"`
+	AudioChannels        FlexInt   `json:"audioChannels"`
+	AudioCodec           string    `json:"audioCodec"`
+	AudioDecision        string    `json:"audioDecision"`
+	Complete             FlexBool  `json:"complete"`
+	Container            string    `json:"container"`
+	Context              string    `json:"context"`
+	Duration             Millis    `json:"duration"`
+	Key                  string    `json:"key"`
+	Progress             FlexFloat `json:"progress"`
+	Protocol             string    `json:"protocol"`
+	Remaining            FlexInt   `json:"remaining"`
+	SourceAudioCodec     string    `json:"sourceAudioCodec"`
+	SourceVideoCodec     string    `json:"sourceVideoCodec"`
+	Speed                FlexFloat `json:"speed"`
+	Throttled            FlexBool  `json:"throttled"`
+	TranscodeHwRequested FlexBool  `json:"transcodeHwRequested"`
+	VideoCodec           string    `json:"videoCodec"`
+	VideoDecision        string    `json:"videoDecision"`
 }
 
 // NotificationContainer read pms notifications
@@ -122,7 +102,7 @@ type NotificationContainer struct {
 
 	Setting []Setting `json:"Setting"`
 
-	Size int64 `json:"size"`
+	Size FlexInt `json:"size"`
 	// Type can be one of:
 	// playing,
 	// reachability,
@@ -173,8 +153,31 @@ func (e *NotificationEvents) OnTranscodeUpdate(fn func(n NotificationContainer))
 	e.events["transcodeSession.update"] = fn
 }
 
+// OnActivity shows the progress of server activities like library scans and media analysis
+func (e *NotificationEvents) OnActivity(fn func(n NotificationContainer)) {
+	e.events["activity"] = fn
+}
+
 // SubscribeToNotifications connects to your server via websockets listening for events
 func (p *Plex) SubscribeToNotifications(events *NotificationEvents, interrupt <-chan os.Signal, fn func(error)) {
+	p.SubscribeToNotificationsCtx(context.Background(), events, interrupt, fn)
+}
+
+// SubscribeToNotificationsCtx is like SubscribeToNotifications but carries ctx for cancellation and deadlines.
+// Cancelling ctx closes the connection the same way an interrupt does
+func (p *Plex) SubscribeToNotificationsCtx(ctx context.Context, events *NotificationEvents, interrupt <-chan os.Signal, fn func(error)) {
+	p.subscribe(ctx, events, interrupt, fn, func(format string, v ...interface{}) {
+		fmt.Printf(format+"\n", v...)
+	})
+}
+
+// subscribe connects to the notifications of the server. logf receives what is not an
+// error of the connection, like notifications without a callback, and may be nil to drop it
+func (p *Plex) subscribe(ctx context.Context, events *NotificationEvents, interrupt <-chan os.Signal, fn func(error), logf func(format string, v ...interface{})) {
+	if logf == nil {
+		logf = func(string, ...interface{}) {}
+	}
+
 	plexURL, err := url.Parse(p.URL)
 
 	if err != nil {
@@ -188,7 +191,7 @@ func (p *Plex) SubscribeToNotifications(events *NotificationEvents, interrupt <-
 		"X-Plex-Token": []string{p.Token},
 	}
 
-	c, _, err := websocket.DefaultDialer.Dial(websocketURL.String(), headers)
+	c, _, err := websocket.DefaultDialer.DialContext(ctx, websocketURL.String(), headers)
 
 	if err != nil {
 		fn(err)
@@ -205,7 +208,7 @@ func (p *Plex) SubscribeToNotifications(events *NotificationEvents, interrupt <-
 			_, message, err := c.ReadMessage()
 
 			if err != nil {
-				fmt.Println("read:", err)
+				logf("read: %v", err)
 				fn(err)
 				return
 			}
@@ -215,7 +218,7 @@ func (p *Plex) SubscribeToNotifications(events *NotificationEvents, interrupt <-
 			var notif WebsocketNotification
 
 			if err := json.Unmarshal(message, &notif); err != nil {
-				fmt.Printf("convert message to json failed: %v\n", err)
+				logf("convert message to json failed: %v", err)
 				continue
 			}
 
@@ -223,7 +226,7 @@ func (p *Plex) SubscribeToNotifications(events *NotificationEvents, interrupt <-
 			fn, ok := events.events[notif.Type]
 
 			if !ok {
-				fmt.Printf("unknown websocket event name: %v\n", notif.Type)
+				logf("unknown websocket event name: %v", notif.Type)
 				continue
 			}
 
@@ -231,6 +234,24 @@ func (p *Plex) SubscribeToNotifications(events *NotificationEvents, interrupt <-
 		}
 	}()
 
+	closeConnection := func() {
+		// To cleanly close a connection, a client should send a close
+		// frame and wait for the server to close the connection.
+		err := c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+
+		if err != nil {
+			logf("write close: %v", err)
+			fn(err)
+		}
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			logf("closing websocket...")
+			c.Close()
+		}
+	}
+
 	go func() {
 		ticker := time.NewTicker(time.Second)
 		defer ticker.Stop()
@@ -243,23 +264,12 @@ func (p *Plex) SubscribeToNotifications(events *NotificationEvents, interrupt <-
 				if err != nil {
 					fn(err)
 				}
+			case <-ctx.Done():
+				closeConnection()
+				return
 			case <-interrupt:
-				fmt.Println("interrupt")
-				// To cleanly close a connection, a client should send a close
-				// frame and wait for the server to close the connection.
-				err := c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-
-				if err != nil {
-					fmt.Println("write close:", err)
-					fn(err)
-				}
-
-				select {
-				case <-done:
-				case <-time.After(time.Second):
-					fmt.Println("closing websocket...")
-					c.Close()
-				}
+				logf("interrupt")
+				closeConnection()
 				return
 			}
 		}